@@ -0,0 +1,487 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// Environment variables, whose values may be secrets, aren't compared.
+const defaultDiffEnvExclude = "*password*;*psw*;*secret*;*key*;*token*;*auth*"
+
+// A build to compare. A local build is read from the build-info collected by the CLI, before it was published.
+type BuildReference struct {
+	BuildName   string
+	BuildNumber string
+	ProjectKey  string
+	Local       bool
+}
+
+func (br BuildReference) String() string {
+	buildString := br.BuildName + "/" + br.BuildNumber
+	if br.Local {
+		buildString += " (local)"
+	}
+	return buildString
+}
+
+// Reports the changes between two builds.
+type BuildDiffCommand struct {
+	serverDetails *config.ServerDetails
+	// The changes are reported from the 'from' build to the 'to' build.
+	from BuildReference
+	to   BuildReference
+	// Semicolon-separated patterns of environment variables, which aren't compared.
+	envExclude string
+	format     coreutils.OutputFormat
+}
+
+func NewBuildDiffCommand() *BuildDiffCommand {
+	return &BuildDiffCommand{envExclude: defaultDiffEnvExclude}
+}
+
+func (bdc *BuildDiffCommand) SetServerDetails(serverDetails *config.ServerDetails) *BuildDiffCommand {
+	bdc.serverDetails = serverDetails
+	return bdc
+}
+
+func (bdc *BuildDiffCommand) SetFrom(from BuildReference) *BuildDiffCommand {
+	bdc.from = from
+	return bdc
+}
+
+func (bdc *BuildDiffCommand) SetTo(to BuildReference) *BuildDiffCommand {
+	bdc.to = to
+	return bdc
+}
+
+func (bdc *BuildDiffCommand) SetEnvExclude(envExclude string) *BuildDiffCommand {
+	bdc.envExclude = envExclude
+	return bdc
+}
+
+func (bdc *BuildDiffCommand) SetFormat(format coreutils.OutputFormat) *BuildDiffCommand {
+	bdc.format = format
+	return bdc
+}
+
+func (bdc *BuildDiffCommand) CommandName() string {
+	return "rt_build_diff"
+}
+
+func (bdc *BuildDiffCommand) ServerDetails() (*config.ServerDetails, error) {
+	return bdc.serverDetails, nil
+}
+
+func (bdc *BuildDiffCommand) Run() error {
+	var servicesManager artifactory.ArtifactoryServicesManager
+	if !bdc.from.Local || !bdc.to.Local {
+		var err error
+		if servicesManager, err = utils.CreateServiceManager(bdc.serverDetails, -1, 0, false); err != nil {
+			return err
+		}
+	}
+	fromBuildInfo, err := bdc.readBuildInfo(bdc.from, servicesManager)
+	if err != nil {
+		return err
+	}
+	toBuildInfo, err := bdc.readBuildInfo(bdc.to, servicesManager)
+	if err != nil {
+		return err
+	}
+	diff := DiffBuildInfo(fromBuildInfo, toBuildInfo)
+	diff.From, diff.To = bdc.from.String(), bdc.to.String()
+	return diff.print(bdc.format)
+}
+
+func (bdc *BuildDiffCommand) readBuildInfo(build BuildReference, servicesManager artifactory.ArtifactoryServicesManager) (*buildinfo.BuildInfo, error) {
+	var buildInfo *buildinfo.BuildInfo
+	var err error
+	if build.Local {
+		buildInfo, err = readLocalBuildInfo(build)
+	} else {
+		buildInfo, err = readPublishedBuildInfo(build, servicesManager)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buildInfo, errorutils.CheckError(buildInfo.ExcludeEnv(strings.Split(bdc.envExclude, ";")...))
+}
+
+func readLocalBuildInfo(build BuildReference) (*buildinfo.BuildInfo, error) {
+	partials, err := utils.ReadPartialBuildInfoFiles(build.BuildName, build.BuildNumber, build.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	generatedBuildsInfo, err := utils.GetGeneratedBuildsInfo(build.BuildName, build.BuildNumber, build.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(partials) == 0 && len(generatedBuildsInfo) == 0 {
+		return nil, errorutils.CheckErrorf("no local build-info was found for build %s/%s", build.BuildName, build.BuildNumber)
+	}
+	localBuild, err := utils.CreateBuildInfoService().GetOrCreateBuildWithProject(build.BuildName, build.BuildNumber, build.ProjectKey)
+	if errorutils.CheckError(err) != nil {
+		return nil, err
+	}
+	buildInfo, err := localBuild.ToBuildInfo()
+	return buildInfo, errorutils.CheckError(err)
+}
+
+func readPublishedBuildInfo(build BuildReference, servicesManager artifactory.ArtifactoryServicesManager) (*buildinfo.BuildInfo, error) {
+	buildInfoParams := services.BuildInfoParams{BuildName: build.BuildName, BuildNumber: build.BuildNumber, ProjectKey: build.ProjectKey}
+	publishedBuildInfo, found, err := servicesManager.GetBuildInfo(buildInfoParams)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errorutils.CheckErrorf("build %s/%s not found in Artifactory", build.BuildName, build.BuildNumber)
+	}
+	return &publishedBuildInfo.BuildInfo, nil
+}
+
+// The changes between two builds.
+type BuildInfoDiff struct {
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Modules      []BuildChange `json:"modules"`
+	Artifacts    []BuildChange `json:"artifacts"`
+	Dependencies []BuildChange `json:"dependencies"`
+	EnvVars      []BuildChange `json:"envVars"`
+	Vcs          []BuildChange `json:"vcs"`
+}
+
+// An added, removed or changed build element. From and To describe the element in each build, e.g. its version, checksum or value.
+type BuildChange struct {
+	Module string     `json:"module,omitempty"`
+	Name   string     `json:"name"`
+	Change ChangeType `json:"change"`
+	From   string     `json:"from,omitempty"`
+	To     string     `json:"to,omitempty"`
+}
+
+// Compare the modules, artifacts, dependencies, environment variables and VCS revisions of two builds.
+// Modules and dependencies are matched by their IDs without the version, and artifacts by their paths without the module version,
+// so a version bump is reported as a change rather than a removal and an addition.
+func DiffBuildInfo(from, to *buildinfo.BuildInfo) *BuildInfoDiff {
+	diff := &BuildInfoDiff{Modules: []BuildChange{}, Artifacts: []BuildChange{}, Dependencies: []BuildChange{}}
+	fromModules, fromKeys := mapModules(from.Modules)
+	toModules, toKeys := mapModules(to.Modules)
+	for _, moduleId := range sortedUnion(fromKeys, toKeys) {
+		fromModule, inFrom := fromModules[moduleId]
+		toModule, inTo := toModules[moduleId]
+		switch {
+		case !inTo:
+			diff.Modules = append(diff.Modules, BuildChange{Name: fromModule.Id, Change: Removed})
+			continue
+		case !inFrom:
+			diff.Modules = append(diff.Modules, BuildChange{Name: toModule.Id, Change: Added})
+			continue
+		}
+		_, fromVersion := splitIdVersion(fromModule.Id)
+		_, toVersion := splitIdVersion(toModule.Id)
+		if fromModule.Id != toModule.Id {
+			diff.Modules = append(diff.Modules, BuildChange{Name: moduleId, Change: Changed, From: fromVersion, To: toVersion})
+		}
+		diff.Artifacts = append(diff.Artifacts, diffArtifacts(moduleId, fromModule.Artifacts, toModule.Artifacts, fromVersion, toVersion)...)
+		diff.Dependencies = append(diff.Dependencies, diffDependencies(moduleId, fromModule.Dependencies, toModule.Dependencies)...)
+	}
+	diff.EnvVars = diffValues(getEnvVars(from), getEnvVars(to))
+	diff.Vcs = diffValues(getVcsRevisions(from), getVcsRevisions(to))
+	return diff
+}
+
+func diffArtifacts(moduleId string, fromArtifacts, toArtifacts []buildinfo.Artifact, fromVersion, toVersion string) []BuildChange {
+	changes := []BuildChange{}
+	fromMap, fromKeys := mapArtifacts(fromArtifacts, fromVersion)
+	toMap, toKeys := mapArtifacts(toArtifacts, toVersion)
+	for _, name := range sortedUnion(fromKeys, toKeys) {
+		fromArtifact, inFrom := fromMap[name]
+		toArtifact, inTo := toMap[name]
+		switch {
+		case !inTo:
+			changes = append(changes, BuildChange{Module: moduleId, Name: name, Change: Removed, From: getChecksum(fromArtifact.Checksum)})
+		case !inFrom:
+			changes = append(changes, BuildChange{Module: moduleId, Name: name, Change: Added, To: getChecksum(toArtifact.Checksum)})
+		case !isSameChecksum(fromArtifact.Checksum, toArtifact.Checksum):
+			changes = append(changes, BuildChange{Module: moduleId, Name: name, Change: Changed, From: getChecksum(fromArtifact.Checksum), To: getChecksum(toArtifact.Checksum)})
+		}
+	}
+	return changes
+}
+
+func diffDependencies(moduleId string, fromDependencies, toDependencies []buildinfo.Dependency) []BuildChange {
+	changes := []BuildChange{}
+	fromMap, fromKeys := mapDependencies(fromDependencies)
+	toMap, toKeys := mapDependencies(toDependencies)
+	for _, name := range sortedUnion(fromKeys, toKeys) {
+		fromDependency, inFrom := fromMap[name]
+		toDependency, inTo := toMap[name]
+		switch {
+		case !inTo:
+			changes = append(changes, BuildChange{Module: moduleId, Name: fromDependency.Id, Change: Removed, From: getChecksum(fromDependency.Checksum)})
+		case !inFrom:
+			changes = append(changes, BuildChange{Module: moduleId, Name: toDependency.Id, Change: Added, To: getChecksum(toDependency.Checksum)})
+		case fromDependency.Id != toDependency.Id:
+			_, fromVersion := splitIdVersion(fromDependency.Id)
+			_, toVersion := splitIdVersion(toDependency.Id)
+			changes = append(changes, BuildChange{Module: moduleId, Name: name, Change: Changed, From: fromVersion, To: toVersion})
+		case !isSameChecksum(fromDependency.Checksum, toDependency.Checksum):
+			changes = append(changes, BuildChange{Module: moduleId, Name: name, Change: Changed, From: getChecksum(fromDependency.Checksum), To: getChecksum(toDependency.Checksum)})
+		}
+	}
+	return changes
+}
+
+// Compare the values of keys, such as environment variables or VCS URLs.
+func diffValues(fromValues, toValues map[string]string) []BuildChange {
+	changes := []BuildChange{}
+	for _, key := range sortedUnion(mapKeys(fromValues), mapKeys(toValues)) {
+		fromValue, inFrom := fromValues[key]
+		toValue, inTo := toValues[key]
+		switch {
+		case !inTo:
+			changes = append(changes, BuildChange{Name: key, Change: Removed, From: fromValue})
+		case !inFrom:
+			changes = append(changes, BuildChange{Name: key, Change: Added, To: toValue})
+		case fromValue != toValue:
+			changes = append(changes, BuildChange{Name: key, Change: Changed, From: fromValue, To: toValue})
+		}
+	}
+	return changes
+}
+
+func mapModules(modules []buildinfo.Module) (map[string]buildinfo.Module, []string) {
+	ids := make([]string, len(modules))
+	for i, module := range modules {
+		ids[i] = module.Id
+	}
+	keys := getVersionlessKeys(ids)
+	result := make(map[string]buildinfo.Module, len(modules))
+	for i, module := range modules {
+		result[keys[i]] = module
+	}
+	return result, keys
+}
+
+func mapDependencies(dependencies []buildinfo.Dependency) (map[string]buildinfo.Dependency, []string) {
+	ids := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		ids[i] = dependency.Id
+	}
+	keys := getVersionlessKeys(ids)
+	result := make(map[string]buildinfo.Dependency, len(dependencies))
+	for i, dependency := range dependencies {
+		result[keys[i]] = dependency
+	}
+	return result, keys
+}
+
+func mapArtifacts(artifacts []buildinfo.Artifact, moduleVersion string) (map[string]buildinfo.Artifact, []string) {
+	names := make([]string, len(artifacts))
+	for i, artifact := range artifacts {
+		names[i] = getArtifactName(artifact)
+	}
+	keys := getVersionlessArtifactKeys(names, moduleVersion)
+	result := make(map[string]buildinfo.Artifact, len(artifacts))
+	for i, artifact := range artifacts {
+		result[keys[i]] = artifact
+	}
+	return result, keys
+}
+
+// Return the keys by which the IDs are matched between the builds, which are the IDs without the version.
+// If several IDs share the same versionless ID, e.g. two versions of the same npm package, their full IDs are used instead.
+func getVersionlessKeys(ids []string) []string {
+	count := make(map[string]int)
+	for _, id := range ids {
+		name, _ := splitIdVersion(id)
+		count[name]++
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id
+		if name, _ := splitIdVersion(id); count[name] == 1 {
+			keys[i] = name
+		}
+	}
+	return keys
+}
+
+// Return the keys by which the artifacts are matched between the builds, which are their paths with the module version replaced by '*',
+// e.g. 'org/jfrog/hello/*/hello-*.jar'. The version is replaced only where it isn't a part of a longer word or version.
+// If several artifacts share the same versionless path, e.g. 'docs/1.0/index.html' and 'docs/*/index.html', their full paths are used instead.
+func getVersionlessArtifactKeys(names []string, moduleVersion string) []string {
+	keys := make([]string, len(names))
+	copy(keys, names)
+	if moduleVersion == "" {
+		return keys
+	}
+	versionRegexp := regexp.MustCompile(`(^|[^0-9A-Za-z.])` + regexp.QuoteMeta(moduleVersion) + `($|[^0-9A-Za-z.]|\.[A-Za-z])`)
+	count := make(map[string]int)
+	for i, name := range names {
+		keys[i] = versionRegexp.ReplaceAllString(name, "${1}*${2}")
+		count[keys[i]]++
+	}
+	for i, name := range names {
+		if count[keys[i]] > 1 {
+			keys[i] = name
+		}
+	}
+	return keys
+}
+
+// Split IDs such as 'org.jfrog:hello:1.0.0' or 'lodash:4.17.21' into their name and version.
+// IDs without a version, such as 'docker-image' or 'sha256__<digest>', are returned as the name.
+func splitIdVersion(id string) (name, version string) {
+	separatorIndex := strings.LastIndex(id, ":")
+	if separatorIndex == -1 {
+		return id, ""
+	}
+	return id[:separatorIndex], id[separatorIndex+1:]
+}
+
+func getArtifactName(artifact buildinfo.Artifact) string {
+	if artifact.Path != "" {
+		return artifact.Path
+	}
+	return artifact.Name
+}
+
+// The SHA-256 checksum may be missing in build-info of old CLI versions, so it is compared only if it exists in both builds.
+func isSameChecksum(from, to buildinfo.Checksum) bool {
+	if from.Sha256 != "" && to.Sha256 != "" {
+		return from.Sha256 == to.Sha256
+	}
+	return from.Sha1 == to.Sha1 && from.Md5 == to.Md5
+}
+
+func getChecksum(checksum buildinfo.Checksum) string {
+	if checksum.Sha256 != "" {
+		return "sha256:" + checksum.Sha256
+	}
+	if checksum.Sha1 != "" {
+		return "sha1:" + checksum.Sha1
+	}
+	return ""
+}
+
+func getEnvVars(buildInfo *buildinfo.BuildInfo) map[string]string {
+	envVars := make(map[string]string)
+	for key, value := range buildInfo.Properties {
+		if strings.HasPrefix(key, buildinfo.BuildInfoEnvPrefix) {
+			envVars[strings.TrimPrefix(key, buildinfo.BuildInfoEnvPrefix)] = value
+		}
+	}
+	return envVars
+}
+
+func getVcsRevisions(buildInfo *buildinfo.BuildInfo) map[string]string {
+	revisions := make(map[string]string)
+	for _, vcs := range buildInfo.VcsList {
+		revisions[vcs.Url] = vcs.Revision
+	}
+	return revisions
+}
+
+func mapKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Return the sorted union of the keys, without duplicates.
+func sortedUnion(first, second []string) []string {
+	encountered := make(map[string]bool)
+	var keys []string
+	for _, key := range append(append([]string{}, first...), second...) {
+		if !encountered[key] {
+			keys = append(keys, key)
+			encountered[key] = true
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (bid *BuildInfoDiff) totalChanges() int {
+	return len(bid.Modules) + len(bid.Artifacts) + len(bid.Dependencies) + len(bid.EnvVars) + len(bid.Vcs)
+}
+
+func (bid *BuildInfoDiff) print(format coreutils.OutputFormat) error {
+	switch format {
+	case coreutils.JsonFormat:
+		content, err := json.MarshalIndent(bid, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(string(content))
+		return nil
+	case coreutils.TableFormat, "":
+		return bid.printTable()
+	default:
+		return errorutils.CheckErrorf("unsupported build diff format: '%s'", format)
+	}
+}
+
+type buildChangeRow struct {
+	Element string `col-name:"Element"`
+	Module  string `col-name:"Module"`
+	Name    string `col-name:"Name"`
+	Change  string `col-name:"Change"`
+	From    string `col-name:"From"`
+	To      string `col-name:"To"`
+}
+
+func (bid *BuildInfoDiff) printTable() error {
+	var rows []buildChangeRow
+	for _, section := range []struct {
+		element string
+		changes []BuildChange
+	}{
+		{"Module", bid.Modules},
+		{"Artifact", bid.Artifacts},
+		{"Dependency", bid.Dependencies},
+		{"Env var", bid.EnvVars},
+		{"VCS", bid.Vcs},
+	} {
+		for _, change := range section.changes {
+			rows = append(rows, buildChangeRow{Element: section.element, Module: valueOrDash(change.Module), Name: change.Name,
+				Change: string(change.Change), From: valueOrDash(change.From), To: valueOrDash(change.To)})
+		}
+	}
+	title := fmt.Sprintf("Changes from build %s to build %s", bid.From, bid.To)
+	if err := coreutils.PrintTable(rows, title, "No changes were found", false); err != nil {
+		return err
+	}
+	log.Output(fmt.Sprintf("Total changes: %d", bid.totalChanges()))
+	return nil
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+package buildinfo
+
+import (
+	"testing"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffBuildInfo(t *testing.T) {
+	from := &buildinfo.BuildInfo{
+		Properties: map[string]string{"buildInfo.env.JAVA_HOME": "/opt/java11", "buildInfo.env.CI": "true", "buildInfo.env.OLD": "1", "java.version": "11"},
+		VcsList:    []buildinfo.Vcs{{Url: "https://github.com/jfrog/hello.git", Revision: "abc"}},
+		Modules: []buildinfo.Module{
+			{
+				Id: "org.jfrog:hello:1.4.2",
+				Artifacts: []buildinfo.Artifact{
+					{Name: "hello.pom", Path: "org/jfrog/hello.pom", Checksum: buildinfo.Checksum{Sha1: "1", Sha256: "pom"}},
+					{Name: "hello-1.4.2.jar", Path: "org/jfrog/hello/1.4.2/hello-1.4.2.jar", Checksum: buildinfo.Checksum{Sha1: "2", Sha256: "old-jar"}},
+					{Name: "hello-sources.jar", Path: "org/jfrog/hello-sources.jar", Checksum: buildinfo.Checksum{Sha1: "3"}},
+				},
+				Dependencies: []buildinfo.Dependency{
+					{Id: "junit:junit:4.12", Checksum: buildinfo.Checksum{Sha1: "junit-4.12"}},
+					{Id: "commons-io:commons-io:2.6", Checksum: buildinfo.Checksum{Sha1: "commons-io"}},
+					{Id: "org.slf4j:slf4j-api:1.7.30", Checksum: buildinfo.Checksum{Sha1: "slf4j"}},
+					{Id: "snapshot:lib:1.0-SNAPSHOT", Checksum: buildinfo.Checksum{Sha1: "snapshot-1"}},
+				},
+			},
+			{Id: "org.jfrog:legacy:1.4.2"},
+		},
+	}
+	to := &buildinfo.BuildInfo{
+		Properties: map[string]string{"buildInfo.env.JAVA_HOME": "/opt/java17", "buildInfo.env.CI": "true", "buildInfo.env.NEW": "2", "java.version": "17"},
+		VcsList:    []buildinfo.Vcs{{Url: "https://github.com/jfrog/hello.git", Revision: "def"}},
+		Modules: []buildinfo.Module{
+			{
+				Id: "org.jfrog:hello:1.4.3",
+				Artifacts: []buildinfo.Artifact{
+					{Name: "hello.pom", Path: "org/jfrog/hello.pom", Checksum: buildinfo.Checksum{Sha1: "1", Sha256: "pom"}},
+					{Name: "hello-1.4.3.jar", Path: "org/jfrog/hello/1.4.3/hello-1.4.3.jar", Checksum: buildinfo.Checksum{Sha1: "2", Sha256: "new-jar"}},
+					{Name: "hello-javadoc.jar", Path: "org/jfrog/hello-javadoc.jar", Checksum: buildinfo.Checksum{Sha1: "4"}},
+				},
+				Dependencies: []buildinfo.Dependency{
+					{Id: "junit:junit:4.13", Checksum: buildinfo.Checksum{Sha1: "junit-4.13"}},
+					{Id: "commons-io:commons-io:2.6", Checksum: buildinfo.Checksum{Sha1: "commons-io"}},
+					{Id: "com.google.guava:guava:31.1", Checksum: buildinfo.Checksum{Sha1: "guava"}},
+					{Id: "snapshot:lib:1.0-SNAPSHOT", Checksum: buildinfo.Checksum{Sha1: "snapshot-2"}},
+				},
+			},
+			{Id: "hello-docker"},
+		},
+	}
+
+	diff := DiffBuildInfo(from, to)
+	assert.Equal(t, []BuildChange{
+		{Name: "hello-docker", Change: Added},
+		{Name: "org.jfrog:hello", Change: Changed, From: "1.4.2", To: "1.4.3"},
+		{Name: "org.jfrog:legacy:1.4.2", Change: Removed},
+	}, diff.Modules)
+	assert.Equal(t, []BuildChange{
+		{Module: "org.jfrog:hello", Name: "org/jfrog/hello-javadoc.jar", Change: Added, To: "sha1:4"},
+		{Module: "org.jfrog:hello", Name: "org/jfrog/hello-sources.jar", Change: Removed, From: "sha1:3"},
+		// The version bump of the artifact is reported as a change.
+		{Module: "org.jfrog:hello", Name: "org/jfrog/hello/*/hello-*.jar", Change: Changed, From: "sha256:old-jar", To: "sha256:new-jar"},
+	}, diff.Artifacts)
+	assert.Equal(t, []BuildChange{
+		{Module: "org.jfrog:hello", Name: "com.google.guava:guava:31.1", Change: Added, To: "sha1:guava"},
+		{Module: "org.jfrog:hello", Name: "junit:junit", Change: Changed, From: "4.12", To: "4.13"},
+		{Module: "org.jfrog:hello", Name: "org.slf4j:slf4j-api:1.7.30", Change: Removed, From: "sha1:slf4j"},
+		{Module: "org.jfrog:hello", Name: "snapshot:lib", Change: Changed, From: "sha1:snapshot-1", To: "sha1:snapshot-2"},
+	}, diff.Dependencies)
+	// Build properties which aren't environment variables are ignored.
+	assert.Equal(t, []BuildChange{
+		{Name: "JAVA_HOME", Change: Changed, From: "/opt/java11", To: "/opt/java17"},
+		{Name: "NEW", Change: Added, To: "2"},
+		{Name: "OLD", Change: Removed, From: "1"},
+	}, diff.EnvVars)
+	assert.Equal(t, []BuildChange{{Name: "https://github.com/jfrog/hello.git", Change: Changed, From: "abc", To: "def"}}, diff.Vcs)
+	assert.Equal(t, 14, diff.totalChanges())
+}
+
+func TestDiffBuildInfoIdentical(t *testing.T) {
+	build := &buildinfo.BuildInfo{Modules: []buildinfo.Module{{Id: "hello:1.0", Artifacts: []buildinfo.Artifact{{Name: "hello.tgz", Checksum: buildinfo.Checksum{Sha1: "1"}}}}}}
+	diff := DiffBuildInfo(build, build)
+	assert.Zero(t, diff.totalChanges())
+}
+
+func TestGetVersionlessKeys(t *testing.T) {
+	// Several versions of the same package are matched by their full IDs.
+	ids := []string{"org.jfrog:hello:1.0", "lodash:4.17.20", "lodash:4.17.21", "sha256__abc", "github.com/jfrog/hello"}
+	assert.Equal(t, []string{"org.jfrog:hello", "lodash:4.17.20", "lodash:4.17.21", "sha256__abc", "github.com/jfrog/hello"}, getVersionlessKeys(ids))
+}
+
+func TestGetVersionlessArtifactKeys(t *testing.T) {
+	names := []string{"org/jfrog/hello/1.0/hello-1.0.jar", "hello-11.0.tgz", "hello-1.0.1.zip", "hello-2.1.0.zip", "hello_1.0"}
+	// The version is replaced only where it isn't a part of another version.
+	assert.Equal(t, []string{"org/jfrog/hello/*/hello-*.jar", "hello-11.0.tgz", "hello-1.0.1.zip", "hello-2.1.0.zip", "hello_*"}, getVersionlessArtifactKeys(names, "1.0"))
+	assert.Equal(t, names, getVersionlessArtifactKeys(names, ""))
+	// Artifacts, which share the same versionless path, are matched by their full paths.
+	names = []string{"docs/1.0/index.html", "docs/*/index.html", "hello-1.0.jar"}
+	assert.Equal(t, []string{"docs/1.0/index.html", "docs/*/index.html", "hello-*.jar"}, getVersionlessArtifactKeys(names, "1.0"))
+}
+
+func TestMapArtifactsCollision(t *testing.T) {
+	artifacts := []buildinfo.Artifact{{Name: "index.html", Path: "docs/1.0/index.html"}, {Name: "index.html", Path: "docs/*/index.html"}}
+	artifactsMap, keys := mapArtifacts(artifacts, "1.0")
+	// No artifact is overwritten by another one with the same versionless path.
+	assert.Len(t, artifactsMap, 2)
+	assert.Equal(t, []string{"docs/1.0/index.html", "docs/*/index.html"}, keys)
+	assert.Equal(t, "docs/1.0/index.html", artifactsMap["docs/1.0/index.html"].Path)
+}
+
+func TestIsSameChecksum(t *testing.T) {
+	assert.True(t, isSameChecksum(buildinfo.Checksum{Sha1: "1", Sha256: "a"}, buildinfo.Checksum{Sha1: "1", Sha256: "a"}))
+	assert.False(t, isSameChecksum(buildinfo.Checksum{Sha1: "1", Sha256: "a"}, buildinfo.Checksum{Sha1: "1", Sha256: "b"}))
+	// SHA-256 is compared only if it exists in both builds.
+	assert.True(t, isSameChecksum(buildinfo.Checksum{Sha1: "1", Md5: "m"}, buildinfo.Checksum{Sha1: "1", Md5: "m", Sha256: "a"}))
+	assert.False(t, isSameChecksum(buildinfo.Checksum{Sha1: "1"}, buildinfo.Checksum{Sha1: "2", Sha256: "a"}))
+}
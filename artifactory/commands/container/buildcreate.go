@@ -60,6 +60,14 @@ func (bdc *BuildDockerCreateCommand) Run() error {
 	if err != nil {
 		return err
 	}
+	signatureArtifacts, err := bdc.signImage(bdc.manifestSha256, repo, buildName, buildNumber, project)
+	if err != nil {
+		return err
+	}
+	if buildInfo != nil && len(buildInfo.Modules) > 0 {
+		// The signature and the attestation are recorded as artifacts of the image's module.
+		buildInfo.Modules[0].Artifacts = append(buildInfo.Modules[0].Artifacts, signatureArtifacts...)
+	}
 	return utils.SaveBuildInfo(buildName, buildNumber, project, buildInfo)
 }
 
@@ -1,6 +1,7 @@
 package container
 
 import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/gofrog/version"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
@@ -18,6 +19,8 @@ type ContainerCommandBase struct {
 	repo               string
 	buildConfiguration *utils.BuildConfiguration
 	serverDetails      *config.ServerDetails
+	// The private key, which signs the image. The image isn't signed if empty.
+	signingKeyPath string
 }
 
 func (ccb *ContainerCommandBase) ImageTag() string {
@@ -83,6 +86,29 @@ func (ccb *ContainerCommandBase) SetServerDetails(serverDetails *config.ServerDe
 	return ccb
 }
 
+func (ccb *ContainerCommandBase) SigningKeyPath() string {
+	return ccb.signingKeyPath
+}
+
+func (ccb *ContainerCommandBase) SetSigningKeyPath(signingKeyPath string) *ContainerCommandBase {
+	ccb.signingKeyPath = signingKeyPath
+	return ccb
+}
+
+// Signs the image's manifest and attaches a provenance attestation to it, if a signing key is set.
+// Returns the signature and the attestation as build-info artifacts.
+func (ccb *ContainerCommandBase) signImage(manifestDigest, repo, buildName, buildNumber, project string) ([]buildinfo.Artifact, error) {
+	if ccb.signingKeyPath == "" {
+		return nil, nil
+	}
+	loginConfig := &container.ContainerManagerLoginConfig{ServerDetails: ccb.serverDetails}
+	return container.NewImageSigner(ccb.image, loginConfig, ccb.signingKeyPath).
+		SetManifestDigest(manifestDigest).
+		SetRepo(repo).
+		SetBuildDetails(buildName, buildNumber, project).
+		Sign()
+}
+
 func (ccb *ContainerCommandBase) init() error {
 	toCollect, err := ccb.buildConfiguration.IsCollectBuildInfo()
 	if err != nil || !toCollect {
@@ -30,7 +30,8 @@ func (cm *ContainerCommand) SetCmdParams(cmdParams []string) *ContainerCommand {
 }
 
 func (cm *ContainerCommand) PerformLogin(serverDetails *config.ServerDetails, containerManagerType container.ContainerManagerType) error {
-	if !cm.skipLogin {
+	// The OCI container manager has no login command. It authenticates on every request to the registry.
+	if !cm.skipLogin && containerManagerType != container.Oci {
 		// Exclude refreshable tokens when working with external tools (build tools, curl, etc)
 		// Otherwise refresh Token may be expireted and docker login will fail.
 		if serverDetails.ServerId != "" {
@@ -45,3 +46,11 @@ func (cm *ContainerCommand) PerformLogin(serverDetails *config.ServerDetails, co
 	}
 	return nil
 }
+
+// Returns the container manager, which runs the command.
+func (cm *ContainerCommand) newContainerManager(serverDetails *config.ServerDetails) container.ContainerManager {
+	if cm.containerManagerType == container.Oci {
+		return container.NewOciManager(&container.ContainerManagerLoginConfig{ServerDetails: serverDetails})
+	}
+	return container.NewManager(cm.containerManagerType)
+}
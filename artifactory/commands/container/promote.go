@@ -1,14 +1,31 @@
 package container
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
+const defaultPromotionStatus = "Promoted"
+
 type DockerPromoteCommand struct {
 	serverDetails *config.ServerDetails
 	params        services.DockerPromoteParams
+	// If true, an existing tag of the target image is overwritten.
+	force bool
+	// If set, the source image is identified by its manifest digest rather than its tag, and promoted to the target tag.
+	sourceDigest string
+	// If the build name and number are set, the promotion is recorded as a status of the build.
+	buildConfiguration *utils.BuildConfiguration
+	status             string
+	comment            string
 }
 
 func NewDockerPromoteCommand() *DockerPromoteCommand {
@@ -21,8 +38,171 @@ func (dp *DockerPromoteCommand) Run() error {
 	if err != nil {
 		return err
 	}
-	// Promote docker
-	return servicesManager.PromoteDocker(dp.params)
+	if err = dp.resolveSourceTag(servicesManager); err != nil {
+		return err
+	}
+	targetImage, targetTag := dp.params.GetTargetDockerRepository(), dp.params.GetTargetTag()
+	if targetImage == "" {
+		targetImage = dp.params.SourceDockerImage
+	}
+	if targetTag == "" {
+		targetTag = dp.params.SourceTag
+	}
+	if err = dp.validateTargetTag(targetImage, targetTag, servicesManager); err != nil {
+		return err
+	}
+	var sourceImage *container.StoredImage
+	var copiedLayers []string
+	if dp.params.SourceTag != "" {
+		if sourceImage, err = container.GetStoredImage(dp.params.SourceRepo, dp.params.SourceDockerImage, dp.params.SourceTag, servicesManager); err != nil {
+			return err
+		}
+		if sourceImage == nil {
+			return errorutils.CheckErrorf("couldn't find the tag %s of the image %s in %s", dp.params.SourceTag, dp.params.SourceDockerImage, dp.params.SourceRepo)
+		}
+		// The image is promoted by its tag, so the tag must still have the requested digest.
+		if dp.sourceDigest != "" && sourceImage.ManifestDigest() != dp.sourceDigest {
+			return errorutils.CheckErrorf("the tag %s of the image %s in %s doesn't have the digest %s", dp.params.SourceTag, dp.params.SourceDockerImage, dp.params.SourceRepo, dp.sourceDigest)
+		}
+		// The layers are verified before the promotion, so a promoted image is never missing layers in the target repository.
+		copiedLayers, err = sourceImage.CopyMissingLayers(dp.params.TargetRepo, targetImage, targetTag, servicesManager)
+		if err != nil {
+			return dp.deleteCopiedLayers(copiedLayers, err, servicesManager)
+		}
+		if len(copiedLayers) > 0 {
+			log.Info(fmt.Sprintf("Copied %d layers, which were missing in %s.", len(copiedLayers), dp.params.TargetRepo))
+		}
+	} else {
+		log.Debug("The entire image is promoted, so its layers aren't verified in the target repository.")
+	}
+	if err = dp.promote(targetImage, targetTag, copiedLayers, servicesManager); err != nil {
+		return err
+	}
+	return dp.recordBuildStatus(targetImage, targetTag, servicesManager)
+}
+
+// If the source image is identified by its digest, find its tag in the source repository.
+func (dp *DockerPromoteCommand) resolveSourceTag(servicesManager artifactory.ArtifactoryServicesManager) error {
+	if dp.sourceDigest == "" {
+		return nil
+	}
+	if dp.params.TargetTag == "" {
+		return errorutils.CheckErrorf("the target tag is mandatory when the source image is identified by its digest")
+	}
+	if dp.params.SourceTag != "" {
+		// The digest of the source tag is verified before the promotion.
+		return nil
+	}
+	sourceTag, err := container.GetImageTagByDigest(dp.params.SourceRepo, dp.params.SourceDockerImage, dp.sourceDigest, servicesManager)
+	if err != nil {
+		return err
+	}
+	log.Debug(fmt.Sprintf("Found the tag %s of the image %s with digest %s.", sourceTag, dp.params.SourceDockerImage, dp.sourceDigest))
+	dp.params.SourceTag = sourceTag
+	return nil
+}
+
+// The source and target tags may have been pushed while the layers were copied, so they are verified again right before the promotion.
+// If the image isn't promoted, the copied layers are deleted from the target repository.
+func (dp *DockerPromoteCommand) promote(targetImage, targetTag string, copiedLayers []string, servicesManager artifactory.ArtifactoryServicesManager) error {
+	if err := dp.verifySourceDigest(servicesManager); err != nil {
+		return dp.deleteCopiedLayers(copiedLayers, err, servicesManager)
+	}
+	if !dp.force {
+		if err := dp.validateTargetTag(targetImage, targetTag, servicesManager); err != nil {
+			// The image, which was pushed to the target tag, may reference the copied layers, so they are kept.
+			if len(copiedLayers) > 0 {
+				log.Warn(fmt.Sprintf("The image wasn't promoted. The following layers, which were copied to %s, were kept:\n%s", dp.params.TargetRepo, strings.Join(copiedLayers, "\n")))
+			}
+			return err
+		}
+	}
+	if err := servicesManager.PromoteDocker(dp.params); err != nil {
+		return dp.deleteCopiedLayers(copiedLayers, err, servicesManager)
+	}
+	return nil
+}
+
+// Delete the layers, which were copied to the target repository before the promotion failed, and return the promotion error.
+func (dp *DockerPromoteCommand) deleteCopiedLayers(copiedLayers []string, promotionErr error, servicesManager artifactory.ArtifactoryServicesManager) error {
+	if len(copiedLayers) == 0 {
+		return promotionErr
+	}
+	log.Info(fmt.Sprintf("The image wasn't promoted. Deleting the %d layers, which were copied to %s...", len(copiedLayers), dp.params.TargetRepo))
+	if notDeleted := container.DeleteCopiedLayers(copiedLayers, servicesManager); len(notDeleted) > 0 {
+		log.Warn(fmt.Sprintf("Couldn't delete the following layers, which were copied to %s:\n%s", dp.params.TargetRepo, strings.Join(notDeleted, "\n")))
+	}
+	return promotionErr
+}
+
+// The tag may have been pushed again while the layers were verified, so its digest is verified again right before the promotion.
+func (dp *DockerPromoteCommand) verifySourceDigest(servicesManager artifactory.ArtifactoryServicesManager) error {
+	if dp.sourceDigest == "" {
+		return nil
+	}
+	digest, err := container.GetImageTagDigest(dp.params.SourceRepo, dp.params.SourceDockerImage, dp.params.SourceTag, servicesManager)
+	if err != nil {
+		return err
+	}
+	if digest != dp.sourceDigest {
+		return errorutils.CheckErrorf("the tag %s of the image %s in %s no longer has the digest %s", dp.params.SourceTag, dp.params.SourceDockerImage, dp.params.SourceRepo, dp.sourceDigest)
+	}
+	return nil
+}
+
+// Tags are immutable in the target repository, unless the promotion is forced.
+func (dp *DockerPromoteCommand) validateTargetTag(targetImage, targetTag string, servicesManager artifactory.ArtifactoryServicesManager) error {
+	if targetTag == "" {
+		// The entire image is promoted, so any of its tags may be overwritten.
+		hasTags, err := container.HasImageTags(dp.params.TargetRepo, targetImage, servicesManager)
+		if err != nil || !hasTags {
+			return err
+		}
+		if !dp.force {
+			return errorutils.CheckErrorf("the image %s already exists in %s. Use the force option to overwrite its tags", targetImage, dp.params.TargetRepo)
+		}
+		log.Warn(fmt.Sprintf("Overwriting the tags of the image %s in %s.", targetImage, dp.params.TargetRepo))
+		return nil
+	}
+	existingImage, err := container.GetStoredImage(dp.params.TargetRepo, targetImage, targetTag, servicesManager)
+	if err != nil || existingImage == nil {
+		return err
+	}
+	if !dp.force {
+		return errorutils.CheckErrorf("the tag %s of the image %s already exists in %s with digest %s. Use the force option to overwrite it", targetTag, targetImage, dp.params.TargetRepo, existingImage.ManifestDigest())
+	}
+	log.Warn(fmt.Sprintf("Overwriting the tag %s of the image %s in %s, which has digest %s.", targetTag, targetImage, dp.params.TargetRepo, existingImage.ManifestDigest()))
+	return nil
+}
+
+// Record the promotion as a status of the build, without moving or copying the build's artifacts.
+func (dp *DockerPromoteCommand) recordBuildStatus(targetImage, targetTag string, servicesManager artifactory.ArtifactoryServicesManager) error {
+	toCollect, err := dp.buildConfiguration.IsCollectBuildInfo()
+	if err != nil || !toCollect {
+		return err
+	}
+	buildName, err := dp.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildNumber, err := dp.buildConfiguration.GetBuildNumber()
+	if err != nil {
+		return err
+	}
+	promotionParams := services.PromotionParams{
+		BuildName:   buildName,
+		BuildNumber: buildNumber,
+		ProjectKey:  dp.buildConfiguration.GetProject(),
+		Status:      dp.status,
+		Comment:     dp.comment,
+	}
+	if promotionParams.Status == "" {
+		promotionParams.Status = defaultPromotionStatus
+	}
+	if promotionParams.Comment == "" {
+		promotionParams.Comment = fmt.Sprintf("Promoted the image %s from %s to %s", targetImage+":"+targetTag, dp.params.SourceRepo, dp.params.TargetRepo)
+	}
+	return servicesManager.PromoteBuild(promotionParams)
 }
 
 func (dp *DockerPromoteCommand) CommandName() string {
@@ -42,3 +222,28 @@ func (dp *DockerPromoteCommand) SetParams(params services.DockerPromoteParams) *
 	dp.params = params
 	return dp
 }
+
+func (dp *DockerPromoteCommand) SetForce(force bool) *DockerPromoteCommand {
+	dp.force = force
+	return dp
+}
+
+func (dp *DockerPromoteCommand) SetSourceDigest(sourceDigest string) *DockerPromoteCommand {
+	dp.sourceDigest = sourceDigest
+	return dp
+}
+
+func (dp *DockerPromoteCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *DockerPromoteCommand {
+	dp.buildConfiguration = buildConfiguration
+	return dp
+}
+
+func (dp *DockerPromoteCommand) SetStatus(status string) *DockerPromoteCommand {
+	dp.status = status
+	return dp
+}
+
+func (dp *DockerPromoteCommand) SetComment(comment string) *DockerPromoteCommand {
+	dp.comment = comment
+	return dp
+}
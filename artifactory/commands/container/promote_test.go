@@ -0,0 +1,324 @@
+package container
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var aqlFindRegexp = regexp.MustCompile(`items\.find\((.*)\)\.include`)
+
+// An in-memory Artifactory, which supports the REST APIs used by the Docker promotion.
+type fakeArtifactory struct {
+	t *testing.T
+	// Maps the file path, including its repository, to its content.
+	files           map[string][]byte
+	promoteRequests []services.DockerPromoteBody
+	copyRequests    []string
+	buildPromotions []services.BuildPromotionBody
+	// Called on every copy request, before the file is copied
+	onCopy func()
+	// If true, the promotion requests fail
+	failPromote bool
+	// The promotions and copies, in the order they were requested.
+	operations []string
+}
+
+func newFakeArtifactory(t *testing.T) *fakeArtifactory {
+	return &fakeArtifactory{t: t, files: make(map[string][]byte)}
+}
+
+func (fa *fakeArtifactory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if !assert.NoError(fa.t, err) {
+		return
+	}
+	switch {
+	case r.URL.Path == "/api/system/version":
+		_, err = w.Write([]byte(`{"version":"7.49.0"}`))
+		assert.NoError(fa.t, err)
+	case r.URL.Path == "/api/search/aql":
+		fa.search(w, string(body))
+	case strings.HasPrefix(r.URL.Path, "/api/docker/"):
+		var promoteBody services.DockerPromoteBody
+		if !assert.NoError(fa.t, json.Unmarshal(body, &promoteBody)) {
+			return
+		}
+		fa.promoteRequests = append(fa.promoteRequests, promoteBody)
+		fa.operations = append(fa.operations, "promote")
+		if fa.failPromote {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fa.promote(strings.Split(r.URL.Path, "/")[3], promoteBody)
+	case strings.HasPrefix(r.URL.Path, "/api/copy/"):
+		source := strings.TrimPrefix(r.URL.Path, "/api/copy/")
+		target := strings.TrimPrefix(r.URL.Query().Get("to"), "/")
+		fa.copyRequests = append(fa.copyRequests, source+" -> "+target)
+		fa.operations = append(fa.operations, "copy")
+		if fa.onCopy != nil {
+			fa.onCopy()
+		}
+		fa.files[target] = fa.files[source]
+		_, err = w.Write([]byte(`{"messages":[]}`))
+		assert.NoError(fa.t, err)
+	case strings.HasPrefix(r.URL.Path, "/api/build/promote/"):
+		var promotionBody services.BuildPromotionBody
+		if !assert.NoError(fa.t, json.Unmarshal(body, &promotionBody)) {
+			return
+		}
+		fa.buildPromotions = append(fa.buildPromotions, promotionBody)
+	case r.Method == http.MethodDelete:
+		fa.operations = append(fa.operations, "delete")
+		delete(fa.files, strings.TrimPrefix(r.URL.Path, "/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fileContent, ok := fa.files[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err = w.Write(fileContent)
+		assert.NoError(fa.t, err)
+	}
+}
+
+// Returns the files which match the repository, path and name criteria of the AQL query.
+func (fa *fakeArtifactory) search(w http.ResponseWriter, aql string) {
+	match := aqlFindRegexp.FindStringSubmatch(aql)
+	if !assert.Len(fa.t, match, 2, aql) {
+		return
+	}
+	var query struct {
+		Or []struct {
+			And []map[string]interface{} `json:"$and"`
+		} `json:"$or"`
+	}
+	if !assert.NoError(fa.t, json.Unmarshal([]byte(match[1]), &query)) {
+		return
+	}
+	results := []servicesutils.ResultItem{}
+	for filePath, fileContent := range fa.files {
+		repo, relativePath, _ := strings.Cut(filePath, "/")
+		item := servicesutils.ResultItem{Repo: repo, Path: path.Dir(relativePath), Name: path.Base(relativePath), Type: "file", Sha256: sha256Hex(fileContent)}
+		for _, criteria := range query.Or {
+			if matchAqlCriteria(criteria.And[0]["repo"], item.Repo) && matchAqlCriteria(criteria.And[0]["path"], item.Path) && matchAqlCriteria(criteria.And[0]["name"], item.Name) {
+				results = append(results, item)
+				break
+			}
+		}
+	}
+	content, err := json.Marshal(map[string]interface{}{"results": results})
+	if !assert.NoError(fa.t, err) {
+		return
+	}
+	_, err = w.Write(content)
+	assert.NoError(fa.t, err)
+}
+
+// Moves or copies the tag folder of the image, like the Docker promotion API does.
+func (fa *fakeArtifactory) promote(sourceRepo string, promoteBody services.DockerPromoteBody) {
+	sourceFolder := path.Join(sourceRepo, promoteBody.DockerRepository, promoteBody.Tag) + "/"
+	targetFolder := path.Join(promoteBody.TargetRepo, promoteBody.TargetDockerRepository, promoteBody.TargetTag) + "/"
+	for filePath, fileContent := range fa.files {
+		if strings.HasPrefix(filePath, sourceFolder) {
+			fa.files[targetFolder+strings.TrimPrefix(filePath, sourceFolder)] = fileContent
+			if !promoteBody.Copy {
+				delete(fa.files, filePath)
+			}
+		}
+	}
+}
+
+func matchAqlCriteria(criteria interface{}, value string) bool {
+	if matchCriteria, ok := criteria.(map[string]interface{}); ok {
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(matchCriteria["$match"].(string)), `\*`, ".*") + "$"
+		return regexp.MustCompile(pattern).MatchString(value)
+	}
+	return criteria == value
+}
+
+func sha256Hex(content []byte) string {
+	checksum := sha256.Sum256(content)
+	return hex.EncodeToString(checksum[:])
+}
+
+// Stores an image tag, with its config and layers, and returns its manifest digest.
+// The first layer is stored in the folder of another image, as Artifactory does with layers which already exist in the repository.
+func (fa *fakeArtifactory) addImage(repo, imageName, tag string, layers ...string) string {
+	configDigest := "sha256:" + sha256Hex([]byte(tag))
+	imageManifest := map[string]interface{}{"config": map[string]string{"digest": configDigest}}
+	var manifestLayers []map[string]string
+	for i, layer := range layers {
+		layerDigest := "sha256:" + sha256Hex([]byte(layer))
+		manifestLayers = append(manifestLayers, map[string]string{"digest": layerDigest, "mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip"})
+		folder := path.Join(repo, imageName, tag)
+		if i == 0 {
+			folder = path.Join(repo, "base", "1.0")
+		}
+		fa.files[path.Join(folder, strings.Replace(layerDigest, ":", "__", 1))] = []byte(layer)
+	}
+	imageManifest["layers"] = manifestLayers
+	manifestContent, err := json.Marshal(imageManifest)
+	require.NoError(fa.t, err)
+	fa.files[path.Join(repo, imageName, tag, "manifest.json")] = manifestContent
+	fa.files[path.Join(repo, imageName, tag, strings.Replace(configDigest, ":", "__", 1))] = []byte(tag)
+	return "sha256:" + sha256Hex(manifestContent)
+}
+
+func TestDockerPromote(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "app-layer")
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.SourceTag = "1.0"
+	buildConfiguration := utils.NewBuildConfiguration("build", "1", "", "")
+	promoteCommand := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetBuildConfiguration(buildConfiguration)
+	require.NoError(t, promoteCommand.Run())
+
+	// The base layer, which isn't stored in the promoted tag folder, is copied to the target repository.
+	assert.Len(t, artifactory.promoteRequests, 1)
+	assert.Equal(t, []string{fmt.Sprintf("docker-dev/base/1.0/sha256__%s -> docker-prod/hello/1.0/sha256__%s", sha256Hex([]byte("base-layer")), sha256Hex([]byte("base-layer")))}, artifactory.copyRequests)
+	// The missing layer is copied before the image is promoted.
+	assert.Equal(t, []string{"copy", "promote"}, artifactory.operations)
+	// The promotion is recorded as a status of the build, without promoting the build's artifacts.
+	require.Len(t, artifactory.buildPromotions, 1)
+	assert.Equal(t, "Promoted", artifactory.buildPromotions[0].Status)
+	assert.Equal(t, "Promoted the image hello:1.0 from docker-dev to docker-prod", artifactory.buildPromotions[0].Comment)
+	assert.Empty(t, artifactory.buildPromotions[0].TargetRepo)
+}
+
+func TestDockerPromoteExistingTag(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "new-layer")
+	existingDigest := artifactory.addImage("docker-prod", "hello", "1.0", "base-layer", "old-layer")
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.SourceTag = "1.0"
+	params.Copy = true
+	err := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).Run()
+	assert.ErrorContains(t, err, "the tag 1.0 of the image hello already exists in docker-prod with digest "+existingDigest)
+	assert.Empty(t, artifactory.promoteRequests)
+
+	// The tag is overwritten if the promotion is forced.
+	require.NoError(t, NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetForce(true).Run())
+	assert.Len(t, artifactory.promoteRequests, 1)
+	assert.Empty(t, artifactory.buildPromotions)
+}
+
+func TestDockerPromoteByDigest(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "layer-1.0")
+	digest := artifactory.addImage("docker-dev", "hello", "1.1", "base-layer", "layer-1.1")
+	artifactory.addImage("docker-dev", "hello", "latest", "base-layer", "layer-latest")
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.Copy = true
+	// The target tag is mandatory.
+	err := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetSourceDigest(digest).Run()
+	assert.ErrorContains(t, err, "the target tag is mandatory")
+
+	params.TargetTag = "release"
+	require.NoError(t, NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetSourceDigest(digest).Run())
+	require.Len(t, artifactory.promoteRequests, 1)
+	assert.Equal(t, "1.1", artifactory.promoteRequests[0].Tag)
+	assert.Equal(t, "release", artifactory.promoteRequests[0].TargetTag)
+
+	// The source tag must have the digest.
+	params.SourceTag = "latest"
+	params.TargetTag = "release-2"
+	err = NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetSourceDigest(digest).Run()
+	assert.ErrorContains(t, err, "the tag latest of the image hello in docker-dev doesn't have the digest "+digest)
+}
+
+func TestDockerPromoteMissingLayer(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "app-layer")
+	// The base layer is missing in both repositories.
+	delete(artifactory.files, path.Join("docker-dev", "base", "1.0", "sha256__"+sha256Hex([]byte("base-layer"))))
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.SourceTag = "1.0"
+	err := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).Run()
+	assert.ErrorContains(t, err, "Could not find layer: sha256__"+sha256Hex([]byte("base-layer")))
+	// The image isn't promoted, because it couldn't be pulled from the target repository.
+	assert.Empty(t, artifactory.promoteRequests)
+}
+
+func TestDockerPromoteByDigestRetagged(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	digest := artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "layer-1")
+	// The tag is pushed again while the missing base layer is copied.
+	artifactory.onCopy = func() {
+		artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "layer-2")
+	}
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.TargetTag = "release"
+	err := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).SetSourceDigest(digest).Run()
+	assert.ErrorContains(t, err, "the tag 1.0 of the image hello in docker-dev no longer has the digest "+digest)
+	assert.Empty(t, artifactory.promoteRequests)
+	// The layer, which was copied for the image, is deleted.
+	assert.NotContains(t, artifactory.files, path.Join("docker-prod", "hello", "release", "sha256__"+sha256Hex([]byte("base-layer"))))
+	assert.Equal(t, []string{"copy", "delete"}, artifactory.operations)
+}
+
+func TestDockerPromoteTargetTagPushed(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "app-layer")
+	// The target tag is pushed while the missing base layer is copied.
+	artifactory.onCopy = func() {
+		artifactory.addImage("docker-prod", "hello", "2.0", "other-base-layer", "other-layer")
+	}
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.SourceTag = "1.0"
+	params.TargetTag = "2.0"
+	err := NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).Run()
+	assert.ErrorContains(t, err, "the tag 2.0 of the image hello already exists in docker-prod")
+	assert.Empty(t, artifactory.promoteRequests)
+	// The pushed image may reference the copied layer, so it is kept.
+	assert.Contains(t, artifactory.files, path.Join("docker-prod", "hello", "2.0", "sha256__"+sha256Hex([]byte("base-layer"))))
+	assert.Equal(t, []string{"copy"}, artifactory.operations)
+}
+
+func TestDockerPromoteFailure(t *testing.T) {
+	artifactory := newFakeArtifactory(t)
+	testServer, serverDetails, _ := tests.CreateRtRestsMockServer(t, artifactory.ServeHTTP)
+	defer testServer.Close()
+	artifactory.addImage("docker-dev", "hello", "1.0", "base-layer", "app-layer")
+	artifactory.failPromote = true
+
+	params := services.NewDockerPromoteParams("hello", "docker-dev", "docker-prod")
+	params.SourceTag = "1.0"
+	assert.Error(t, NewDockerPromoteCommand().SetServerDetails(serverDetails).SetParams(params).Run())
+	// The layer, which was copied for the image, is deleted.
+	assert.NotContains(t, artifactory.files, path.Join("docker-prod", "hello", "1.0", "sha256__"+sha256Hex([]byte("base-layer"))))
+	assert.Equal(t, []string{"copy", "promote", "delete"}, artifactory.operations)
+}
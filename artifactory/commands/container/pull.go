@@ -38,7 +38,7 @@ func (pc *PullCommand) Run() error {
 		return err
 	}
 	// Perform pull.
-	cm := container.NewManager(pc.containerManagerType)
+	cm := pc.newContainerManager(serverDetails)
 	err = cm.RunNativeCmd(pc.cmdParams)
 	if err != nil {
 		return err
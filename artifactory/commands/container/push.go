@@ -3,6 +3,7 @@ package container
 import (
 	"path"
 
+	buildinfo "github.com/jfrog/build-info-go/entities"
 	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
@@ -74,7 +75,7 @@ func (pc *PushCommand) Run() error {
 		return err
 	}
 	// Perform push.
-	cm := container.NewManager(pc.containerManagerType)
+	cm := pc.newContainerManager(serverDetails)
 	err = cm.RunNativeCmd(pc.cmdParams)
 	if err != nil {
 		return err
@@ -83,7 +84,7 @@ func (pc *PushCommand) Run() error {
 	if err != nil {
 		return err
 	}
-	if !toCollect && !pc.IsDetailedSummary() {
+	if !toCollect && !pc.IsDetailedSummary() && pc.SigningKeyPath() == "" {
 		return nil
 	}
 	buildName, err := pc.buildConfiguration.GetBuildName()
@@ -111,28 +112,48 @@ func (pc *PushCommand) Run() error {
 			return err
 		}
 		buildInfoModule, err := builder.Build(pc.BuildConfiguration().GetModule())
+		if err != nil {
+			return err
+		}
+		signatureArtifacts, err := pc.signPushedImage(builder.GetManifestDigest(), repo, buildName, buildNumber, pc.BuildConfiguration().GetProject())
 		if err != nil || buildInfoModule == nil {
 			return err
 		}
+		// The signature and the attestation are recorded as artifacts of the image's module.
+		if len(buildInfoModule.Modules) > 0 {
+			buildInfoModule.Modules[0].Artifacts = append(buildInfoModule.Modules[0].Artifacts, signatureArtifacts...)
+		}
 		if err = utils.SaveBuildInfo(buildName, buildNumber, pc.BuildConfiguration().GetProject(), buildInfoModule); err != nil {
 			return err
 		}
+	} else {
+		// The build-info collection hasn't been triggered at this point, and we do need it for handling the detailed summary and for finding the pushed manifest.
+		// We are therefore skipping setting mage build name/number props before running build-info collection.
+		builder.SetSkipTaggingLayers(true)
+		if _, err = builder.Build(""); err != nil {
+			return err
+		}
+		if _, err = pc.signPushedImage(builder.GetManifestDigest(), repo, "", "", ""); err != nil {
+			return err
+		}
 	}
 	if pc.IsDetailedSummary() {
-		if !toCollect {
-			// The build-info collection hasn't been triggered at this point, and we do need it for handling the detailed summary.
-			// We are therefore skipping setting mage build name/number props before running build-info collection.
-			builder.SetSkipTaggingLayers(true)
-			_, err = builder.Build("")
-			if err != nil {
-				return err
-			}
-		}
 		return pc.layersMapToFileTransferDetails(serverDetails.ArtifactoryUrl, builder.GetLayers())
 	}
 	return nil
 }
 
+// Signs the pushed manifest, which is found in Artifactory by its digest, rather than by the tag, which may have been pushed again since.
+func (pc *PushCommand) signPushedImage(manifestDigest, repo, buildName, buildNumber, project string) ([]buildinfo.Artifact, error) {
+	if pc.SigningKeyPath() == "" {
+		return nil, nil
+	}
+	if manifestDigest == "" {
+		return nil, errorutils.CheckErrorf("couldn't find the pushed manifest of the image %s in Artifactory, so it can't be signed", pc.image.Name())
+	}
+	return pc.signImage(manifestDigest, repo, buildName, buildNumber, project)
+}
+
 func (pc *PushCommand) layersMapToFileTransferDetails(artifactoryUrl string, layers *[]servicesutils.ResultItem) error {
 	var details []clientutils.FileTransferDetails
 	for _, layer := range *layers {
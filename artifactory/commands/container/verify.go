@@ -0,0 +1,57 @@
+package container
+
+import (
+	"fmt"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Verifies the signatures and provenance attestations, which were attached to an image when it was pushed.
+type VerifyCommand struct {
+	ContainerCommandBase
+	// The PEM encoded public key, which matches the signing key.
+	publicKeyPath string
+	// If true, the verification fails unless the image has a verified SLSA provenance attestation.
+	requireProvenance bool
+}
+
+func NewVerifyCommand() *VerifyCommand {
+	return &VerifyCommand{}
+}
+
+func (vc *VerifyCommand) SetPublicKeyPath(publicKeyPath string) *VerifyCommand {
+	vc.publicKeyPath = publicKeyPath
+	return vc
+}
+
+func (vc *VerifyCommand) SetRequireProvenance(requireProvenance bool) *VerifyCommand {
+	vc.requireProvenance = requireProvenance
+	return vc
+}
+
+func (vc *VerifyCommand) Run() error {
+	loginConfig := &container.ContainerManagerLoginConfig{ServerDetails: vc.serverDetails}
+	result, err := container.VerifyImage(vc.image, loginConfig, vc.publicKeyPath)
+	if err != nil {
+		return err
+	}
+	if result.VerifiedSignatures == 0 {
+		return errorutils.CheckErrorf("no valid signature of the image %s with digest %s was found", vc.image.Name(), result.ManifestDigest)
+	}
+	if vc.requireProvenance && result.VerifiedAttestations == 0 {
+		return errorutils.CheckErrorf("no valid provenance attestation of the image %s with digest %s was found", vc.image.Name(), result.ManifestDigest)
+	}
+	log.Info(fmt.Sprintf("Verified %d signatures and %d provenance attestations of the image %s with digest %s.", result.VerifiedSignatures, result.VerifiedAttestations, vc.image.Name(), result.ManifestDigest))
+	return nil
+}
+
+func (vc *VerifyCommand) CommandName() string {
+	return "rt_docker_verify"
+}
+
+func (vc *VerifyCommand) ServerDetails() (*config.ServerDetails, error) {
+	return vc.serverDetails, nil
+}
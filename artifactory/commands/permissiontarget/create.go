@@ -2,6 +2,7 @@ package permissiontarget
 
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
 )
 
 type PermissionTargetCreateCommand struct {
@@ -22,6 +23,12 @@ func (ptcc *PermissionTargetCreateCommand) SetVars(vars string) *PermissionTarge
 	return ptcc
 }
 
+// Send the provided permission target, instead of reading it from a template.
+func (ptcc *PermissionTargetCreateCommand) SetParams(params *services.PermissionTargetParams) *PermissionTargetCreateCommand {
+	ptcc.params = params
+	return ptcc
+}
+
 func (ptcc *PermissionTargetCreateCommand) SetServerDetails(serverDetails *config.ServerDetails) *PermissionTargetCreateCommand {
 	ptcc.serverDetails = serverDetails
 	return ptcc
@@ -16,6 +16,8 @@ type PermissionTargetCommand struct {
 	serverDetails *config.ServerDetails
 	templatePath  string
 	vars          string
+	// If set, the permission target is sent as is, instead of being read from the template.
+	params *services.PermissionTargetParams
 }
 
 func (ptc *PermissionTargetCommand) Vars() string {
@@ -27,17 +29,34 @@ func (ptc *PermissionTargetCommand) TemplatePath() string {
 }
 
 func (ptc *PermissionTargetCommand) PerformPermissionTargetCmd(isUpdate bool) (err error) {
-	permissionTargetConfigMap, err := utils.ConvertTemplateToMap(ptc)
+	params := ptc.params
+	if params == nil {
+		if params, err = ptc.getParamsFromTemplate(); err != nil {
+			return err
+		}
+	}
+	servicesManager, err := rtUtils.CreateServiceManager(ptc.serverDetails, -1, 0, false)
 	if err != nil {
 		return err
 	}
+	if isUpdate {
+		return servicesManager.UpdatePermissionTarget(*params)
+	}
+	return servicesManager.CreatePermissionTarget(*params)
+}
+
+func (ptc *PermissionTargetCommand) getParamsFromTemplate() (*services.PermissionTargetParams, error) {
+	permissionTargetConfigMap, err := utils.ConvertTemplateToMap(ptc)
+	if err != nil {
+		return nil, err
+	}
 	// Go over the confMap and write the values with the correct types
 	for key, value := range permissionTargetConfigMap {
 		isBuildSection := false
 		switch key {
 		case Name:
 			if _, ok := value.(string); !ok {
-				return errorutils.CheckErrorf("template syntax error: the value for the  key: \"Name\" is not a string type.")
+				return nil, errorutils.CheckErrorf("template syntax error: the value for the  key: \"Name\" is not a string type.")
 			}
 		case Build:
 			isBuildSection = true
@@ -47,31 +66,24 @@ func (ptc *PermissionTargetCommand) PerformPermissionTargetCmd(isUpdate bool) (e
 		case ReleaseBundle:
 			permissionSection, err := covertPermissionSection(value, isBuildSection)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			permissionTargetConfigMap[key] = permissionSection
 		default:
-			return errorutils.CheckErrorf("template syntax error: unknown key: \"" + key + "\".")
+			return nil, errorutils.CheckErrorf("template syntax error: unknown key: \"" + key + "\".")
 		}
 	}
 	// Convert the new JSON with the correct types to params struct
 	content, err := json.Marshal(permissionTargetConfigMap)
 	if errorutils.CheckError(err) != nil {
-		return err
+		return nil, err
 	}
 	params := services.NewPermissionTargetParams()
 	err = json.Unmarshal(content, &params)
 	if errorutils.CheckError(err) != nil {
-		return err
-	}
-	servicesManager, err := rtUtils.CreateServiceManager(ptc.serverDetails, -1, 0, false)
-	if err != nil {
-		return err
-	}
-	if isUpdate {
-		return servicesManager.UpdatePermissionTarget(params)
+		return nil, err
 	}
-	return servicesManager.CreatePermissionTarget(params)
+	return &params, nil
 }
 
 // Each section is a map of string->interface{}. We need to convert each value to its correct type
@@ -2,6 +2,7 @@ package permissiontarget
 
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
 )
 
 type PermissionTargetUpdateCommand struct {
@@ -22,6 +23,12 @@ func (ptuc *PermissionTargetUpdateCommand) SetVars(vars string) *PermissionTarge
 	return ptuc
 }
 
+// Send the provided permission target, instead of reading it from a template.
+func (ptuc *PermissionTargetUpdateCommand) SetParams(params *services.PermissionTargetParams) *PermissionTargetUpdateCommand {
+	ptuc.params = params
+	return ptuc
+}
+
 func (ptuc *PermissionTargetUpdateCommand) SetServerDetails(serverDetails *config.ServerDetails) *PermissionTargetUpdateCommand {
 	ptuc.serverDetails = serverDetails
 	return ptuc
@@ -17,6 +17,8 @@ type ReplicationCreateCommand struct {
 	serverDetails *config.ServerDetails
 	templatePath  string
 	vars          string
+	// If set, the replication is created as is, instead of being read from the template.
+	params *services.CreateReplicationParams
 }
 
 func NewReplicationCreateCommand() *ReplicationCreateCommand {
@@ -33,6 +35,12 @@ func (rcc *ReplicationCreateCommand) SetVars(vars string) *ReplicationCreateComm
 	return rcc
 }
 
+// Create the provided replication, instead of reading it from a template.
+func (rcc *ReplicationCreateCommand) SetParams(params *services.CreateReplicationParams) *ReplicationCreateCommand {
+	rcc.params = params
+	return rcc
+}
+
 func (rcc *ReplicationCreateCommand) SetServerDetails(serverDetails *config.ServerDetails) *ReplicationCreateCommand {
 	rcc.serverDetails = serverDetails
 	return rcc
@@ -47,6 +55,20 @@ func (rcc *ReplicationCreateCommand) CommandName() string {
 }
 
 func (rcc *ReplicationCreateCommand) Run() (err error) {
+	params := rcc.params
+	if params == nil {
+		if params, err = rcc.getParamsFromTemplate(); err != nil {
+			return err
+		}
+	}
+	servicesManager, err := rtUtils.CreateServiceManager(rcc.serverDetails, -1, 0, false)
+	if err != nil {
+		return err
+	}
+	return servicesManager.CreateReplication(*params)
+}
+
+func (rcc *ReplicationCreateCommand) getParamsFromTemplate() (params *services.CreateReplicationParams, err error) {
 	content, err := fileutils.ReadFile(rcc.templatePath)
 	if errorutils.CheckError(err) != nil {
 		return
@@ -72,43 +94,39 @@ func (rcc *ReplicationCreateCommand) Run() (err error) {
 		if key == "serverId" {
 			serverId = value.(string)
 		} else {
-			err := writersMap[key](&replicationConfigMap, key, value.(string))
+			err = writersMap[key](&replicationConfigMap, key, value.(string))
 			if err != nil {
-				return err
+				return
 			}
 		}
 	}
 	err = fillMissingDefaultValue(replicationConfigMap)
 	if err != nil {
-		return err
+		return
 	}
 	// Write a JSON with the correct values
 	content, err = json.Marshal(replicationConfigMap)
 	if errorutils.CheckError(err) != nil {
 		return
 	}
-	var params services.CreateReplicationParams
-	err = json.Unmarshal(content, &params)
+	params = new(services.CreateReplicationParams)
+	err = json.Unmarshal(content, params)
 	if errorutils.CheckError(err) != nil {
 		return
 	}
 
-	setPathPrefixBackwardCompatibility(&params)
-	servicesManager, err := rtUtils.CreateServiceManager(rcc.serverDetails, -1, 0, false)
-	if err != nil {
-		return err
-	}
+	setPathPrefixBackwardCompatibility(params)
 	// In case 'serverId' is not found, pull replication will be assumed.
 	if serverId != "" {
 		if targetRepo, ok := replicationConfigMap["targetRepoKey"]; ok {
-			if err = updateArtifactoryInfo(&params, serverId, targetRepo.(string)); err != nil {
-				return err
+			if err = updateArtifactoryInfo(params, serverId, targetRepo.(string)); err != nil {
+				return
 			}
 		} else {
-			return errorutils.CheckErrorf("expected 'targetRepoKey' field in the json template file.")
+			return nil, errorutils.CheckErrorf("expected 'targetRepoKey' field in the json template file.")
 		}
 	}
-	return servicesManager.CreateReplication(params)
+	return
 }
 
 func fillMissingDefaultValue(replicationConfigMap map[string]interface{}) error {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -12,7 +13,11 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-const MaxThreadsLimit = 1024
+const (
+	MaxThreadsLimit      = 1024
+	timeWindowsSeparator = ";"
+	noTimeWindows        = "none"
+)
 
 type TransferSettingsCommand struct {
 }
@@ -26,28 +31,83 @@ func (tst *TransferSettingsCommand) Run() error {
 	if err != nil {
 		return err
 	}
-	var currThreadsNumber string
 	if currSettings == nil {
-		currThreadsNumber = strconv.Itoa(utils.DefaultThreads)
-	} else {
-		currThreadsNumber = strconv.Itoa(currSettings.ThreadsNumber)
+		currSettings = &utils.TransferSettings{ThreadsNumber: utils.DefaultThreads}
 	}
 	var threadsNumberInput string
-	ioutils.ScanFromConsole("Set the maximum number of working threads", &threadsNumberInput, currThreadsNumber)
+	ioutils.ScanFromConsole("Set the maximum number of working threads", &threadsNumberInput, strconv.Itoa(currSettings.ThreadsNumber))
 	threadsNumber, err := strconv.Atoi(threadsNumberInput)
 	if err != nil || threadsNumber < 1 || threadsNumber > MaxThreadsLimit {
 		return errorutils.CheckError(errors.New("the value must be a number between 1 and " + strconv.Itoa(MaxThreadsLimit)))
 	}
-	conf := &utils.TransferSettings{ThreadsNumber: threadsNumber}
+	maxBytesPerSec, err := scanRateLimit("Set the maximum number of bytes per second (0 for unlimited)", currSettings.MaxBytesPerSec)
+	if err != nil {
+		return err
+	}
+	maxFilesPerSec, err := scanRateLimit("Set the maximum number of files per second (0 for unlimited)", currSettings.MaxFilesPerSec)
+	if err != nil {
+		return err
+	}
+	timeWindows, err := scanTimeWindows(currSettings.TimeWindows)
+	if err != nil {
+		return err
+	}
+	conf := &utils.TransferSettings{ThreadsNumber: threadsNumber, MaxBytesPerSec: maxBytesPerSec, MaxFilesPerSec: maxFilesPerSec, TimeWindows: timeWindows}
 	err = utils.SaveTransferSettings(conf)
 	if err != nil {
 		return err
 	}
 	log.Output("The settings were saved successfully. It might take a few moments for the new settings to take effect.")
 	log.Output(fmt.Sprintf("Note - For Build Info repositories, the number of worker threads will be limited to %d.", utils.MaxBuildInfoThreads))
+	if len(timeWindows) > 0 {
+		log.Output("Note - Outside the allowed time windows, the transfer will pause and resume automatically when the next time window starts.")
+	}
 	return nil
 }
 
+func scanRateLimit(caption string, currentLimit int64) (int64, error) {
+	var limitInput string
+	ioutils.ScanFromConsole(caption, &limitInput, strconv.FormatInt(currentLimit, 10))
+	limit, err := strconv.ParseInt(limitInput, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, errorutils.CheckError(errors.New("the value must be a non-negative number"))
+	}
+	return limit, nil
+}
+
+func scanTimeWindows(currentTimeWindows []utils.TimeWindow) ([]utils.TimeWindow, error) {
+	currentTimeWindowsStr := noTimeWindows
+	if len(currentTimeWindows) > 0 {
+		var timeWindowsStrings []string
+		for i := range currentTimeWindows {
+			timeWindowsStrings = append(timeWindowsStrings, currentTimeWindows[i].String())
+		}
+		currentTimeWindowsStr = strings.Join(timeWindowsStrings, timeWindowsSeparator)
+	}
+	var timeWindowsInput string
+	ioutils.ScanFromConsole("Set the time windows in which the transfer is allowed to run, separated by '"+timeWindowsSeparator+"'. "+
+		"For example 'Mon-Fri 22:00-06:00"+timeWindowsSeparator+"Sat,Sun 00:00-24:00' ('"+noTimeWindows+"' for no limitation)", &timeWindowsInput, currentTimeWindowsStr)
+	return parseTimeWindows(timeWindowsInput)
+}
+
+func parseTimeWindows(timeWindowsStr string) ([]utils.TimeWindow, error) {
+	if strings.EqualFold(strings.TrimSpace(timeWindowsStr), noTimeWindows) {
+		return nil, nil
+	}
+	var timeWindows []utils.TimeWindow
+	for _, timeWindowStr := range strings.Split(timeWindowsStr, timeWindowsSeparator) {
+		if strings.TrimSpace(timeWindowStr) == "" {
+			continue
+		}
+		timeWindow, err := utils.ParseTimeWindow(timeWindowStr)
+		if err != nil {
+			return nil, err
+		}
+		timeWindows = append(timeWindows, *timeWindow)
+	}
+	return timeWindows, nil
+}
+
 func (tst *TransferSettingsCommand) ServerDetails() (*config.ServerDetails, error) {
 	// There's no need to report the usage of this command.
 	return nil, nil
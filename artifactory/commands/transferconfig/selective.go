@@ -0,0 +1,677 @@
+package transferconfig
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/permissiontarget"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/replication"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/usersmanagement"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+// ConfigEntity is a kind of configuration entity, which can be transferred selectively using the Artifactory REST API,
+// instead of exporting and importing the entire configuration.
+type ConfigEntity string
+
+const (
+	Repositories      ConfigEntity = "repositories"
+	PermissionTargets ConfigEntity = "permissions"
+	Groups            ConfigEntity = "groups"
+	Users             ConfigEntity = "users"
+	Replications      ConfigEntity = "replications"
+)
+
+// The entities are transferred in this order, so that each entity is created after the entities it references.
+// For example, users reference groups and permission targets reference repositories, users and groups.
+var configEntities = []ConfigEntity{Groups, Users, Repositories, Replications, PermissionTargets}
+
+// Users that exist in every Artifactory instance, and should not be transferred
+var systemUsers = []string{"anonymous", "_internal", "access-admin"}
+
+const (
+	entityCreated     = "Created"
+	entityMerged      = "Merged"
+	entityOverwritten = "Overwritten"
+	entitySkipped     = "Skipped"
+	entityFailed      = "Failed"
+
+	generatedPasswordBytes = 24
+)
+
+// Parse the entities provided by the user, for example 'repositories,users'.
+func ParseConfigEntities(entities []string) ([]ConfigEntity, error) {
+	var parsed []ConfigEntity
+	for _, entity := range entities {
+		configEntity := ConfigEntity(strings.ToLower(strings.TrimSpace(entity)))
+		if !slices.Contains(configEntities, configEntity) {
+			return nil, errorutils.CheckErrorf("unsupported config entity '%s'. Supported entities: %s", entity, configEntitiesString())
+		}
+		if !slices.Contains(parsed, configEntity) {
+			parsed = append(parsed, configEntity)
+		}
+	}
+	return parsed, nil
+}
+
+func configEntitiesString() string {
+	var entities []string
+	for _, entity := range configEntities {
+		entities = append(entities, string(entity))
+	}
+	return strings.Join(entities, ", ")
+}
+
+type selectiveTransferResult struct {
+	Entity  string `col-name:"Entity"`
+	Name    string `col-name:"Name"`
+	Action  string `col-name:"Action"`
+	Details string `col-name:"Details"`
+}
+
+// Transfers the selected entities from the source to the target using the REST API.
+// Entities that already exist in the target are merged with the source entities, unless overwrite is set.
+// The entities are created in the target by the permissiontarget, usersmanagement and replication commands.
+type selectiveTransfer struct {
+	source        artifactory.ArtifactoryServicesManager
+	target        artifactory.ArtifactoryServicesManager
+	targetDetails *config.ServerDetails
+	repoFilter    *utils.RepositoryFilter
+	overwrite     bool
+	dryRun        bool
+	results       []selectiveTransferResult
+	failuresFound bool
+}
+
+// Transfer the selected config entities, merging them into the existing data in the target.
+func (tcc *TransferConfigCommand) runSelective() (err error) {
+	promptMsg := fmt.Sprintf("This command will transfer the following Artifactory config entities: %s\n", joinConfigEntities(tcc.entities)) +
+		fmt.Sprintf("From %s - <%s>\n", coreutils.PrintBold("Source"), tcc.sourceServerDetails.ArtifactoryUrl) +
+		fmt.Sprintf("To %s - <%s>\n", coreutils.PrintBold("Target"), tcc.targetServerDetails.ArtifactoryUrl)
+	if tcc.overwrite {
+		promptMsg += "Entities that already exist in the target will be overwritten.\n"
+	} else {
+		promptMsg += "Entities that already exist in the target will be merged or skipped.\n"
+	}
+	if !coreutils.AskYesNo(promptMsg+"Are you sure you want to continue?", false) {
+		return nil
+	}
+	if tcc.sourceServerDetails.GetArtifactoryUrl() == tcc.targetServerDetails.GetArtifactoryUrl() {
+		return errorutils.CheckErrorf("The source and target Artifactory servers are identical, but should be different.")
+	}
+	sourceServicesManager, err := utils.CreateServiceManager(tcc.sourceServerDetails, -1, 0, false)
+	if err != nil {
+		return err
+	}
+	targetServicesManager, err := utils.CreateServiceManager(tcc.targetServerDetails, -1, 0, false)
+	if err != nil {
+		return err
+	}
+	st := &selectiveTransfer{
+		source:        sourceServicesManager,
+		target:        targetServicesManager,
+		targetDetails: tcc.targetServerDetails,
+		repoFilter:    &utils.RepositoryFilter{IncludePatterns: tcc.includeReposPatterns, ExcludePatterns: tcc.excludeReposPatterns},
+		overwrite:     tcc.overwrite,
+		dryRun:        tcc.dryRun,
+	}
+	if err = st.run(tcc.entities); err != nil {
+		return err
+	}
+	if err = coreutils.PrintTable(st.results, "Config Transfer Summary", "Nothing to transfer", false); err != nil {
+		return err
+	}
+	if st.failuresFound {
+		return errorutils.CheckErrorf("some config entities failed to transfer. See the summary above for details")
+	}
+	log.Info("Config transfer completed successfully!")
+	return nil
+}
+
+func joinConfigEntities(entities []ConfigEntity) string {
+	var entitiesStr []string
+	for _, entity := range entities {
+		entitiesStr = append(entitiesStr, string(entity))
+	}
+	return strings.Join(entitiesStr, ", ")
+}
+
+func (st *selectiveTransfer) run(entities []ConfigEntity) error {
+	transferFuncs := map[ConfigEntity]func() error{
+		Groups:            st.transferGroups,
+		Users:             st.transferUsers,
+		Repositories:      st.transferRepositories,
+		Replications:      st.transferReplications,
+		PermissionTargets: st.transferPermissionTargets,
+	}
+	for _, entity := range configEntities {
+		if !slices.Contains(entities, entity) {
+			continue
+		}
+		log.Info(coreutils.PrintTitle(coreutils.PrintBold(fmt.Sprintf("========== Transferring %s ==========", entity))))
+		if err := transferFuncs[entity](); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record the result of transferring a single entity.
+// err - The error returned while transferring the entity, if any. Failures are recorded, and the transfer continues to the next entity.
+func (st *selectiveTransfer) addResult(entity ConfigEntity, name, action, details string, err error) {
+	if err != nil {
+		action = entityFailed
+		details = err.Error()
+		st.failuresFound = true
+	}
+	if st.dryRun && action != entityFailed && action != entitySkipped {
+		action += " (dry run)"
+	}
+	log.Info(fmt.Sprintf("%s '%s': %s", entity, name, action))
+	st.results = append(st.results, selectiveTransferResult{Entity: string(entity), Name: name, Action: action, Details: details})
+}
+
+// Transfer the groups. Existing groups are merged by adding the members of the source group to the target group.
+// Only members which already exist in the target are added. The rest are added to the group when the users are transferred.
+func (st *selectiveTransfer) transferGroups() error {
+	groupNames, err := st.source.GetAllGroups()
+	if err != nil {
+		return err
+	}
+	targetUsers, err := st.getTargetUsers()
+	if err != nil {
+		return err
+	}
+	for _, groupName := range *groupNames {
+		group, err := st.source.GetGroup(services.GroupParams{GroupDetails: services.Group{Name: groupName}, IncludeUsers: true})
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			continue
+		}
+		var members []string
+		for _, member := range group.UsersNames {
+			if slices.Contains(targetUsers, member) {
+				members = append(members, member)
+			}
+		}
+		group.UsersNames = members
+		targetGroup, err := st.target.GetGroup(services.GroupParams{GroupDetails: services.Group{Name: groupName}, IncludeUsers: true})
+		if err != nil {
+			return err
+		}
+		switch {
+		case targetGroup == nil || st.overwrite:
+			action := entityCreated
+			if targetGroup != nil {
+				action = entityOverwritten
+			}
+			if !st.dryRun {
+				err = usersmanagement.NewGroupCreateCommand().SetServerDetails(st.targetDetails).SetName(groupName).SetDetails(group).SetReplaceIfExists(true).Run()
+			}
+			st.addResult(Groups, groupName, action, "", err)
+		default:
+			mergedMembers, changed := mergeStringSlices(targetGroup.UsersNames, members)
+			if !changed {
+				st.addResult(Groups, groupName, entitySkipped, "Already exists in the target", nil)
+				continue
+			}
+			if !st.dryRun {
+				err = usersmanagement.NewGroupUpdateCommand().SetServerDetails(st.targetDetails).SetName(groupName).SetUsers(mergedMembers).Run()
+			}
+			st.addResult(Groups, groupName, entityMerged, "Added the members of the source group", err)
+		}
+	}
+	return nil
+}
+
+func (st *selectiveTransfer) getTargetUsers() ([]string, error) {
+	users, err := st.target.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	var userNames []string
+	for _, user := range users {
+		userNames = append(userNames, user.Name)
+	}
+	return userNames, nil
+}
+
+// Transfer the users. The passwords of the users can't be retrieved from the source,
+// and therefore new users are created with a random password, which should be reset by an administrator.
+// Existing users are merged by adding them to the groups of the source user, or overwritten without changing their password.
+func (st *selectiveTransfer) transferUsers() error {
+	users, err := st.source.GetAllUsers()
+	if err != nil {
+		return err
+	}
+	for _, listedUser := range users {
+		if slices.Contains(systemUsers, listedUser.Name) {
+			continue
+		}
+		user, err := st.source.GetUser(services.UserParams{UserDetails: services.User{Name: listedUser.Name}})
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			continue
+		}
+		user.LastLoggedIn = ""
+		targetUser, err := st.target.GetUser(services.UserParams{UserDetails: services.User{Name: user.Name}})
+		if err != nil {
+			return err
+		}
+		if targetUser == nil {
+			st.createUser(user)
+			continue
+		}
+		if st.overwrite {
+			st.overwriteUser(user)
+			continue
+		}
+		missingGroups := getMissingGroups(targetUser.Groups, user.Groups)
+		if len(missingGroups) == 0 {
+			st.addResult(Users, user.Name, entitySkipped, "Already exists in the target", nil)
+			continue
+		}
+		if !st.dryRun {
+			err = st.addGroupsMember(missingGroups, user.Name)
+		}
+		st.addResult(Users, user.Name, entityMerged, "Added the user to the groups of the source user", err)
+	}
+	return nil
+}
+
+// Create the user in the target with a random password.
+func (st *selectiveTransfer) createUser(user *services.User) {
+	password, err := generatePassword()
+	if err != nil {
+		st.addResult(Users, user.Name, entityCreated, "", err)
+		return
+	}
+	user.Password = password
+	if !st.dryRun {
+		err = usersmanagement.NewUsersCreateCommand().SetServerDetails(st.targetDetails).SetUsers([]services.User{*user}).SetUsersGroups(user.Groups).Run()
+	}
+	st.addResult(Users, user.Name, entityCreated, "Created with a random password - the password should be reset", err)
+}
+
+// Overwrite the details of the existing user in the target. The password of the user isn't sent, and therefore isn't changed.
+func (st *selectiveTransfer) overwriteUser(user *services.User) {
+	var err error
+	if !st.dryRun {
+		err = st.target.UpdateUser(services.UserParams{UserDetails: *user})
+	}
+	st.addResult(Users, user.Name, entityOverwritten, "The password of the existing user was kept", err)
+}
+
+// Add the user to the members of the groups in the target.
+func (st *selectiveTransfer) addGroupsMember(groupNames []string, userName string) error {
+	for _, groupName := range groupNames {
+		group, err := st.target.GetGroup(services.GroupParams{GroupDetails: services.Group{Name: groupName}, IncludeUsers: true})
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return errorutils.CheckErrorf("the group '%s' doesn't exist in the target", groupName)
+		}
+		members, _ := mergeStringSlices(group.UsersNames, []string{userName})
+		if err = usersmanagement.NewGroupUpdateCommand().SetServerDetails(st.targetDetails).SetName(groupName).SetUsers(members).Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generate a random password, which meets the password complexity requirements of Artifactory.
+func generatePassword() (string, error) {
+	randomBytes := make([]byte, generatedPasswordBytes)
+	if _, err := io.ReadFull(rand.Reader, randomBytes); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes) + "aA1!", nil
+}
+
+// Transfer the repositories that match the repositories filter.
+// Local and remote repositories are transferred before virtual repositories, which may reference them.
+func (st *selectiveTransfer) transferRepositories() error {
+	targetRepos, err := st.getTargetRepos()
+	if err != nil {
+		return err
+	}
+	artifactoryUrl := clientutils.AddTrailingSlashIfNeeded(st.target.GetConfig().GetServiceDetails().GetUrl())
+	rtDetails, err := createArtifactoryClientDetails(st.target)
+	if err != nil {
+		return err
+	}
+	rtDetails.Headers = map[string]string{"Content-Type": "application/json"}
+	for _, repoType := range []utils.RepoType{utils.Local, utils.Federated, utils.Remote, utils.Virtual} {
+		repoKeys, err := utils.GetFilteredRepositoriesByNameAndType(st.source, st.repoFilter.IncludePatterns, st.repoFilter.ExcludePatterns, repoType)
+		if err != nil {
+			return err
+		}
+		for _, repoKey := range repoKeys {
+			var repoConfig map[string]interface{}
+			if err = st.source.GetRepository(repoKey, &repoConfig); err != nil {
+				return err
+			}
+			if repoType == utils.Federated {
+				// The members of the federated repository belong to the source
+				delete(repoConfig, "members")
+			}
+			action := entityCreated
+			if slices.Contains(targetRepos, repoKey) {
+				if !st.overwrite {
+					st.addResult(Repositories, repoKey, entitySkipped, "Already exists in the target", nil)
+					continue
+				}
+				action = entityOverwritten
+			}
+			var details string
+			if repoType == utils.Remote {
+				details = "The credentials of the remote repository should be verified"
+			}
+			if !st.dryRun {
+				err = sendRepositoryConfig(st.target, artifactoryUrl+"api/repositories/"+repoKey, repoConfig, action == entityOverwritten, rtDetails)
+			}
+			st.addResult(Repositories, repoKey, action, details, err)
+		}
+	}
+	return nil
+}
+
+func (st *selectiveTransfer) getTargetRepos() ([]string, error) {
+	repoDetailsList, err := st.target.GetAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+	var repoKeys []string
+	for _, repoDetails := range *repoDetailsList {
+		repoKeys = append(repoKeys, repoDetails.Key)
+	}
+	return repoKeys, nil
+}
+
+// Create or update a repository with its JSON configuration, as returned by the source Artifactory.
+func sendRepositoryConfig(servicesManager artifactory.ArtifactoryServicesManager, url string, repoConfig map[string]interface{}, update bool,
+	rtDetails *httputils.HttpClientDetails) error {
+	content, err := json.Marshal(repoConfig)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var resp *http.Response
+	var body []byte
+	if update {
+		resp, body, err = servicesManager.Client().SendPost(url, content, rtDetails)
+	} else {
+		resp, body, err = servicesManager.Client().SendPut(url, content, rtDetails)
+	}
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusCreated)
+}
+
+// Transfer the replications of the repositories that match the repositories filter.
+func (st *selectiveTransfer) transferReplications() error {
+	for _, repoType := range []utils.RepoType{utils.Local, utils.Remote} {
+		repoKeys, err := utils.GetFilteredRepositoriesByNameAndType(st.source, st.repoFilter.IncludePatterns, st.repoFilter.ExcludePatterns, repoType)
+		if err != nil {
+			return err
+		}
+		for _, repoKey := range repoKeys {
+			replications, err := getReplications(st.source, repoKey)
+			if err != nil {
+				return err
+			}
+			if len(replications) == 0 {
+				continue
+			}
+			targetReplications, err := getReplications(st.target, repoKey)
+			if err != nil {
+				return err
+			}
+			action := entityCreated
+			if len(targetReplications) > 0 {
+				if !st.overwrite {
+					st.addResult(Replications, repoKey, entitySkipped, "Already exists in the target", nil)
+					continue
+				}
+				action = entityOverwritten
+			}
+			if !st.dryRun {
+				err = st.createReplications(repoKey, replications, action == entityOverwritten)
+			}
+			st.addResult(Replications, repoKey, action, "The password of the replication should be verified", err)
+		}
+	}
+	return nil
+}
+
+// The multi-push replications of a local repository, which are created together in a single request.
+type multiPushReplicationsBody struct {
+	CronExp                string                               `json:"cronExp"`
+	EnableEventReplication bool                                 `json:"enableEventReplication"`
+	Replications           []*clientUtils.UpdateReplicationBody `json:"replications"`
+}
+
+// Create the replications of a repository in the target. When overwriting, the existing replications of the repository are deleted first.
+// A single replication is created by the replication command. Multi-push replications are created together, since creating a replication replaces the other replications of the repository.
+func (st *selectiveTransfer) createReplications(repoKey string, replications []clientUtils.ReplicationParams, overwrite bool) error {
+	if overwrite {
+		if err := st.target.DeleteReplication(repoKey); err != nil {
+			return err
+		}
+	}
+	if len(replications) == 1 {
+		replicationParams := &services.CreateReplicationParams{ReplicationParams: replications[0]}
+		return replication.NewReplicationCreateCommand().SetServerDetails(st.targetDetails).SetParams(replicationParams).Run()
+	}
+	multiPushReplications := multiPushReplicationsBody{CronExp: replications[0].CronExp, EnableEventReplication: replications[0].EnableEventReplication}
+	for _, replicationDetails := range replications {
+		multiPushReplications.Replications = append(multiPushReplications.Replications, clientUtils.CreateUpdateReplicationBody(replicationDetails))
+	}
+	content, err := json.Marshal(multiPushReplications)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	rtDetails, err := createArtifactoryClientDetails(st.target)
+	if err != nil {
+		return err
+	}
+	rtDetails.Headers = map[string]string{"Content-Type": "application/json"}
+	artifactoryUrl := clientutils.AddTrailingSlashIfNeeded(st.target.GetConfig().GetServiceDetails().GetUrl())
+	resp, body, err := st.target.Client().SendPut(artifactoryUrl+"api/replications/multiple/"+repoKey, content, rtDetails)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK, http.StatusCreated)
+}
+
+// Return the replications of a repository, or an empty slice if the repository has no replications.
+func getReplications(servicesManager artifactory.ArtifactoryServicesManager, repoKey string) ([]clientUtils.ReplicationParams, error) {
+	artifactoryUrl := clientutils.AddTrailingSlashIfNeeded(servicesManager.GetConfig().GetServiceDetails().GetUrl())
+	rtDetails, err := createArtifactoryClientDetails(servicesManager)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := servicesManager.Client().SendGet(artifactoryUrl+"api/replications/"+repoKey, true, rtDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var replicationBodies []clientUtils.GetReplicationBody
+	if err = json.Unmarshal(body, &replicationBodies); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var replications []clientUtils.ReplicationParams
+	for _, replicationBody := range replicationBodies {
+		replications = append(replications, *clientUtils.CreateReplicationParams(replicationBody))
+	}
+	return replications, nil
+}
+
+// Transfer the permission targets.
+// Existing permission targets are merged by adding the repositories, patterns and actions of the source permission target.
+func (st *selectiveTransfer) transferPermissionTargets() error {
+	permissionTargetNames, err := getAllPermissionTargetNames(st.source)
+	if err != nil {
+		return err
+	}
+	for _, name := range permissionTargetNames {
+		permissionTarget, err := st.source.GetPermissionTarget(name)
+		if err != nil {
+			return err
+		}
+		if permissionTarget == nil {
+			continue
+		}
+		targetPermissionTarget, err := st.target.GetPermissionTarget(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case targetPermissionTarget == nil:
+			if !st.dryRun {
+				err = permissiontarget.NewPermissionTargetCreateCommand().SetServerDetails(st.targetDetails).SetParams(permissionTarget).Run()
+			}
+			st.addResult(PermissionTargets, name, entityCreated, "", err)
+		case st.overwrite:
+			if !st.dryRun {
+				err = permissiontarget.NewPermissionTargetUpdateCommand().SetServerDetails(st.targetDetails).SetParams(permissionTarget).Run()
+			}
+			st.addResult(PermissionTargets, name, entityOverwritten, "", err)
+		default:
+			if !mergePermissionTargets(targetPermissionTarget, permissionTarget) {
+				st.addResult(PermissionTargets, name, entitySkipped, "Already exists in the target", nil)
+				continue
+			}
+			if !st.dryRun {
+				err = permissiontarget.NewPermissionTargetUpdateCommand().SetServerDetails(st.targetDetails).SetParams(targetPermissionTarget).Run()
+			}
+			st.addResult(PermissionTargets, name, entityMerged, "", err)
+		}
+	}
+	return nil
+}
+
+type permissionTargetName struct {
+	Name string `json:"name"`
+}
+
+func getAllPermissionTargetNames(servicesManager artifactory.ArtifactoryServicesManager) ([]string, error) {
+	artifactoryUrl := clientutils.AddTrailingSlashIfNeeded(servicesManager.GetConfig().GetServiceDetails().GetUrl())
+	rtDetails, err := createArtifactoryClientDetails(servicesManager)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, _, err := servicesManager.Client().SendGet(artifactoryUrl+"api/v2/security/permissions", true, rtDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var permissionTargets []permissionTargetName
+	if err = json.Unmarshal(body, &permissionTargets); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var names []string
+	for _, permissionTarget := range permissionTargets {
+		names = append(names, permissionTarget.Name)
+	}
+	return names, nil
+}
+
+// Merge the source permission target into the target permission target.
+// Return true if the target permission target was changed.
+func mergePermissionTargets(target, source *services.PermissionTargetParams) bool {
+	changed := mergePermissionTargetSection(&target.Repo, source.Repo)
+	changed = mergePermissionTargetSection(&target.Build, source.Build) || changed
+	return mergePermissionTargetSection(&target.ReleaseBundle, source.ReleaseBundle) || changed
+}
+
+func mergePermissionTargetSection(target **services.PermissionTargetSection, source *services.PermissionTargetSection) bool {
+	if source == nil {
+		return false
+	}
+	if *target == nil {
+		*target = source
+		return true
+	}
+	var changed, sectionChanged bool
+	(*target).Repositories, changed = mergeStringSlices((*target).Repositories, source.Repositories)
+	(*target).IncludePatterns, sectionChanged = mergeStringSlices((*target).IncludePatterns, source.IncludePatterns)
+	changed = changed || sectionChanged
+	(*target).ExcludePatterns, sectionChanged = mergeStringSlices((*target).ExcludePatterns, source.ExcludePatterns)
+	changed = changed || sectionChanged
+	if source.Actions == nil {
+		return changed
+	}
+	if (*target).Actions == nil {
+		(*target).Actions = &services.Actions{}
+	}
+	changed = mergeActions(&(*target).Actions.Users, source.Actions.Users) || changed
+	return mergeActions(&(*target).Actions.Groups, source.Actions.Groups) || changed
+}
+
+func mergeActions(target *map[string][]string, source map[string][]string) bool {
+	changed := false
+	for name, actions := range source {
+		if *target == nil {
+			*target = make(map[string][]string)
+		}
+		var actionsChanged bool
+		(*target)[name], actionsChanged = mergeStringSlices((*target)[name], actions)
+		changed = changed || actionsChanged
+	}
+	return changed
+}
+
+// Add the values of the source slice that are missing in the target slice.
+// Return the merged slice, and true if values were added.
+func mergeStringSlices(target, source []string) ([]string, bool) {
+	changed := false
+	for _, value := range source {
+		if !slices.Contains(target, value) {
+			target = append(target, value)
+			changed = true
+		}
+	}
+	return target, changed
+}
+
+// Return the groups of the source user, which the target user doesn't belong to.
+func getMissingGroups(target, source *[]string) []string {
+	if source == nil {
+		return nil
+	}
+	var missingGroups []string
+	for _, group := range *source {
+		if target == nil || !slices.Contains(*target, group) {
+			missingGroups = append(missingGroups, group)
+		}
+	}
+	return missingGroups
+}
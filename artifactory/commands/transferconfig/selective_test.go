@@ -0,0 +1,235 @@
+package transferconfig
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	commonTests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigEntities(t *testing.T) {
+	entities, err := ParseConfigEntities([]string{"Users", " repositories", "users"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigEntity{Users, Repositories}, entities)
+
+	_, err = ParseConfigEntities([]string{"builds"})
+	assert.ErrorContains(t, err, "unsupported config entity 'builds'")
+}
+
+func TestMergePermissionTargets(t *testing.T) {
+	target := &services.PermissionTargetParams{
+		Name: "permission",
+		Repo: &services.PermissionTargetSection{
+			Repositories:    []string{"repo1"},
+			IncludePatterns: []string{"**"},
+			Actions:         &services.Actions{Users: map[string][]string{"user1": {"read"}}},
+		},
+	}
+	source := &services.PermissionTargetParams{
+		Name: "permission",
+		Repo: &services.PermissionTargetSection{
+			Repositories:    []string{"repo1", "repo2"},
+			IncludePatterns: []string{"**"},
+			Actions: &services.Actions{
+				Users:  map[string][]string{"user1": {"read", "write"}, "user2": {"read"}},
+				Groups: map[string][]string{"group1": {"manage"}},
+			},
+		},
+		Build: &services.PermissionTargetSection{Repositories: []string{"artifactory-build-info"}},
+	}
+
+	assert.True(t, mergePermissionTargets(target, source))
+	assert.Equal(t, []string{"repo1", "repo2"}, target.Repo.Repositories)
+	assert.Equal(t, []string{"**"}, target.Repo.IncludePatterns)
+	assert.Equal(t, map[string][]string{"user1": {"read", "write"}, "user2": {"read"}}, target.Repo.Actions.Users)
+	assert.Equal(t, map[string][]string{"group1": {"manage"}}, target.Repo.Actions.Groups)
+	assert.Equal(t, source.Build, target.Build)
+	assert.Nil(t, target.ReleaseBundle)
+
+	// Merging again changes nothing
+	assert.False(t, mergePermissionTargets(target, source))
+}
+
+func TestGetMissingGroups(t *testing.T) {
+	assert.Equal(t, []string{"deployers"}, getMissingGroups(&[]string{"readers"}, &[]string{"readers", "deployers"}))
+	assert.Equal(t, []string{"readers"}, getMissingGroups(nil, &[]string{"readers"}))
+	assert.Empty(t, getMissingGroups(&[]string{"readers"}, nil))
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := generatePassword()
+	assert.NoError(t, err)
+	otherPassword, err := generatePassword()
+	assert.NoError(t, err)
+	assert.NotEqual(t, password, otherPassword)
+	assert.Greater(t, len(password), generatedPasswordBytes)
+}
+
+func TestSelectiveTransferGroups(t *testing.T) {
+	sourceServer, _, sourceServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var content []byte
+		switch r.URL.Path {
+		case "/api/security/groups":
+			content = []byte(`[{"name":"new-group"},{"name":"existing-group"}]`)
+		case "/api/security/groups/new-group":
+			content = []byte(`{"name":"new-group","description":"new","userNames":["user1","user2"]}`)
+		case "/api/security/groups/existing-group":
+			content = []byte(`{"name":"existing-group","userNames":["user1","user2"]}`)
+		}
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+	})
+	defer sourceServer.Close()
+
+	var createdGroups, updatedGroups []services.Group
+	targetServer, targetDetails, targetServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut || r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var group services.Group
+			assert.NoError(t, json.Unmarshal(body, &group))
+			if r.Method == http.MethodPut {
+				createdGroups = append(createdGroups, group)
+				w.WriteHeader(http.StatusCreated)
+			} else {
+				updatedGroups = append(updatedGroups, group)
+			}
+		case r.URL.Path == "/api/security/users":
+			_, err := w.Write([]byte(`[{"name":"user1"},{"name":"user3"}]`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/api/security/groups/existing-group":
+			_, err := w.Write([]byte(`{"name":"existing-group","userNames":["user3"]}`))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer targetServer.Close()
+
+	st := &selectiveTransfer{source: sourceServicesManager, target: targetServicesManager, targetDetails: targetDetails}
+	assert.NoError(t, st.run([]ConfigEntity{Groups}))
+	assert.False(t, st.failuresFound)
+
+	// Only members which exist in the target are added to the groups. The existing group keeps its members.
+	assert.Equal(t, []services.Group{{Name: "new-group", Description: "new", UsersNames: []string{"user1"}}}, createdGroups)
+	assert.Equal(t, []services.Group{{Name: "existing-group", UsersNames: []string{"user3", "user1"}}}, updatedGroups)
+	assert.Equal(t, []selectiveTransferResult{
+		{Entity: string(Groups), Name: "new-group", Action: entityCreated},
+		{Entity: string(Groups), Name: "existing-group", Action: entityMerged, Details: "Added the members of the source group"},
+	}, st.results)
+
+	// With overwrite, the existing group is replaced
+	createdGroups, updatedGroups = nil, nil
+	st = &selectiveTransfer{source: sourceServicesManager, target: targetServicesManager, targetDetails: targetDetails, overwrite: true}
+	assert.NoError(t, st.run([]ConfigEntity{Groups}))
+	assert.Len(t, createdGroups, 2)
+	assert.Empty(t, updatedGroups)
+	assert.Equal(t, entityOverwritten, st.results[1].Action)
+}
+
+func TestSelectiveTransferUsers(t *testing.T) {
+	sourceServer, _, sourceServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var content []byte
+		switch r.URL.Path {
+		case "/api/security/users":
+			content = []byte(`[{"name":"new-user"},{"name":"existing-user"}]`)
+		case "/api/security/users/new-user":
+			content = []byte(`{"name":"new-user","email":"new@example.com"}`)
+		case "/api/security/users/existing-user":
+			content = []byte(`{"name":"existing-user","email":"existing@example.com"}`)
+		}
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+	})
+	defer sourceServer.Close()
+
+	var createdUsers, updatedUsers []map[string]interface{}
+	targetServer, targetDetails, targetServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut || r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var user map[string]interface{}
+			assert.NoError(t, json.Unmarshal(body, &user))
+			if r.Method == http.MethodPut {
+				createdUsers = append(createdUsers, user)
+				w.WriteHeader(http.StatusCreated)
+			} else {
+				updatedUsers = append(updatedUsers, user)
+			}
+		case r.URL.Path == "/api/security/users/existing-user":
+			_, err := w.Write([]byte(`{"name":"existing-user","email":"old@example.com"}`))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer targetServer.Close()
+
+	// With overwrite, the existing user is updated without changing its password
+	st := &selectiveTransfer{source: sourceServicesManager, target: targetServicesManager, targetDetails: targetDetails, overwrite: true}
+	assert.NoError(t, st.run([]ConfigEntity{Users}))
+	assert.False(t, st.failuresFound)
+	assert.Len(t, createdUsers, 1)
+	assert.Equal(t, "new-user", createdUsers[0]["name"])
+	assert.NotEmpty(t, createdUsers[0]["password"])
+	assert.Equal(t, []map[string]interface{}{{"name": "existing-user", "email": "existing@example.com"}}, updatedUsers)
+	assert.Equal(t, entityCreated, st.results[0].Action)
+	assert.Equal(t, entityOverwritten, st.results[1].Action)
+}
+
+func TestSelectiveTransferMultiPushReplications(t *testing.T) {
+	sourceServer, _, sourceServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var content []byte
+		switch {
+		case r.URL.Path == "/api/repositories" && r.URL.Query().Get("type") == "local":
+			content = []byte(`[{"key":"local-repo"}]`)
+		case r.URL.Path == "/api/repositories":
+			content = []byte(`[]`)
+		case r.URL.Path == "/api/replications/local-repo":
+			content = []byte(`[{"url":"https://first/local-repo","cronExp":"0 0 12 * * ?","repoKey":"local-repo"},` +
+				`{"url":"https://second/local-repo","cronExp":"0 0 12 * * ?","repoKey":"local-repo"}]`)
+		}
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+	})
+	defer sourceServer.Close()
+
+	var requests []string
+	var multiPushReplications multiPushReplicationsBody
+	targetServer, targetDetails, targetServicesManager := commonTests.CreateRtRestsMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, err := w.Write([]byte(`[{"url":"https://existing/local-repo","repoKey":"local-repo"}]`))
+			assert.NoError(t, err)
+			return
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(body, &multiPushReplications))
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+	})
+	defer targetServer.Close()
+
+	// With overwrite, the existing replications are deleted, and the replications are created in a single request
+	st := &selectiveTransfer{source: sourceServicesManager, target: targetServicesManager, targetDetails: targetDetails,
+		repoFilter: &utils.RepositoryFilter{}, overwrite: true}
+	assert.NoError(t, st.run([]ConfigEntity{Replications}))
+	assert.False(t, st.failuresFound)
+	assert.Equal(t, []string{"DELETE /api/replications/local-repo", "PUT /api/replications/multiple/local-repo"}, requests)
+	assert.Equal(t, "0 0 12 * * ?", multiPushReplications.CronExp)
+	if assert.Len(t, multiPushReplications.Replications, 2) {
+		assert.Equal(t, "https://first/local-repo", multiPushReplications.Replications[0].URL)
+		assert.Equal(t, "https://second/local-repo", multiPushReplications.Replications[1].URL)
+	}
+	assert.Equal(t, []selectiveTransferResult{
+		{Entity: string(Replications), Name: "local-repo", Action: entityOverwritten, Details: "The password of the replication should be verified"},
+	}, st.results)
+}
@@ -42,6 +42,10 @@ type TransferConfigCommand struct {
 	verbose              bool
 	includeReposPatterns []string
 	excludeReposPatterns []string
+	// If not empty, only these entities are transferred using the REST API, and merged into the existing data in the target
+	entities []ConfigEntity
+	// If true, the selective transfer overwrites entities which already exist in the target, instead of merging into them.
+	overwrite bool
 }
 
 func NewTransferConfigCommand(sourceServer, targetServer *config.ServerDetails) *TransferConfigCommand {
@@ -62,6 +66,11 @@ func (tcc *TransferConfigCommand) SetForce(force bool) *TransferConfigCommand {
 	return tcc
 }
 
+func (tcc *TransferConfigCommand) SetOverwrite(overwrite bool) *TransferConfigCommand {
+	tcc.overwrite = overwrite
+	return tcc
+}
+
 func (tcc *TransferConfigCommand) SetVerbose(verbose bool) *TransferConfigCommand {
 	tcc.verbose = verbose
 	return tcc
@@ -77,7 +86,15 @@ func (tcc *TransferConfigCommand) SetExcludeReposPatterns(excludeReposPatterns [
 	return tcc
 }
 
+func (tcc *TransferConfigCommand) SetEntities(entities []ConfigEntity) *TransferConfigCommand {
+	tcc.entities = entities
+	return tcc
+}
+
 func (tcc *TransferConfigCommand) Run() (err error) {
+	if len(tcc.entities) > 0 {
+		return tcc.runSelective()
+	}
 	sourceServicesManager, err := utils.CreateServiceManager(tcc.sourceServerDetails, -1, 0, tcc.dryRun)
 	if err != nil {
 		return err
@@ -11,6 +11,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/content"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -293,6 +294,17 @@ func createErrorsCsvSummary(sourceRepos []string, timeStarted time.Time) (string
 // Gets a list of all errors files from the CLI's cache.
 // Errors-files contain files that were failed to upload or actions that were skipped because of known limitations.
 func getErrorsFiles(repoKeys []string, isRetry bool) (filesPaths []string, err error) {
+	return listErrorsFiles(isRetry, func(repoKey string) bool {
+		return slices.Contains(repoKeys, repoKey)
+	})
+}
+
+// Gets a list of the errors files of all repositories from the CLI's cache.
+func getAllErrorsFiles(isRetry bool) (filesPaths []string, err error) {
+	return listErrorsFiles(isRetry, func(string) bool { return true })
+}
+
+func listErrorsFiles(isRetry bool, includeRepo func(repoKey string) bool) (filesPaths []string, err error) {
 	var dirPath string
 	if isRetry {
 		dirPath, err = coreutils.GetJfrogTransferRetryableDir()
@@ -313,23 +325,29 @@ func getErrorsFiles(repoKeys []string, isRetry bool) (filesPaths []string, err e
 	}
 
 	for _, file := range files {
-		matchAndGroups := errorsFilesRegexp.FindStringSubmatch(filepath.Base(file))
-		// Expecting a match and 4 groups. A total of 5 results.
-		if len(matchAndGroups) != 5 {
+		repoKey := getErrorsFileRepoKey(file)
+		if repoKey == "" {
 			log.Error("unexpected errors file file-name:", file)
 			continue
 		}
-		// Append the errors file if the first group matches any of the requested repo keys.
-		for _, repoKey := range repoKeys {
-			if matchAndGroups[1] == repoKey {
-				filesPaths = append(filesPaths, file)
-				break
-			}
+		// Append the errors file if its repo key is requested.
+		if includeRepo(repoKey) {
+			filesPaths = append(filesPaths, file)
 		}
 	}
 	return
 }
 
+// Returns the repository key of an errors file, or an empty string if the file name is unexpected.
+func getErrorsFileRepoKey(errorsFilePath string) string {
+	matchAndGroups := errorsFilesRegexp.FindStringSubmatch(filepath.Base(errorsFilePath))
+	// Expecting a match and 4 groups. A total of 5 results.
+	if len(matchAndGroups) != 5 {
+		return ""
+	}
+	return matchAndGroups[1]
+}
+
 // Count the number of transfer failures of a given subset of repositories
 func getRetryErrorCount(repoKeys []string) (int, error) {
 	files, err := getErrorsFiles(repoKeys, true)
@@ -408,8 +408,10 @@ func updateProgress(phase *phaseBase, progressbar *TransferProgressMng, timeEstM
 		if err != nil {
 			return err
 		}
-		progressbar.increaseTotalSize(int(chunnkSizeInBytes))
-		phase.progressBar.phases[phase.phaseId].GetTasksProgressBar().GetBar().IncrBy(int(chunnkSizeInBytes))
+		if progressbar != nil {
+			progressbar.increaseTotalSize(int(chunnkSizeInBytes))
+			progressbar.phases[phase.phaseId].GetTasksProgressBar().GetBar().IncrBy(int(chunnkSizeInBytes))
+		}
 	}
 	if timeEstMng != nil {
 		timeEstMng.AddChunkStatus(chunk, time.Since(chunkSentTime).Milliseconds())
@@ -0,0 +1,131 @@
+package transferfiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	metricsPath              = "/metrics"
+	metricsPrefix            = "jfrog_transfer_"
+	metricsShutdownTimeout   = 5 * time.Second
+	prometheusTextFormatType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var metricsLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Serves the status of the running transfer as Prometheus metrics.
+// The status is read from the state files the transfer persists, and not from its state manager, which the transfer keeps updating while the metrics are served.
+type metricsServer struct {
+	server    *http.Server
+	startTime time.Time
+	// The address the server listens on
+	address string
+}
+
+// Start an HTTP server exposing the transfer metrics on the given address, for example ':9090'.
+func startMetricsServer(address string, startTime time.Time) (*metricsServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	ms := &metricsServer{startTime: startTime, address: listener.Addr().String()}
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, ms.handleMetrics)
+	ms.server = &http.Server{Handler: mux, ReadHeaderTimeout: metricsShutdownTimeout}
+	go func() {
+		if e := ms.server.Serve(listener); e != nil && !errors.Is(e, http.ErrServerClosed) {
+			log.Error("Transfer metrics server stopped unexpectedly:", e.Error())
+		}
+	}()
+	log.Info("Serving transfer metrics on http://" + ms.address + metricsPath)
+	return ms, nil
+}
+
+func (ms *metricsServer) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+	return errorutils.CheckError(ms.server.Shutdown(ctx))
+}
+
+func (ms *metricsServer) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	stateManager, err := state.NewTransferStateManager(true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	transferStatus, err := newTransferStatus(stateManager, int64(time.Since(ms.startTime).Seconds()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", prometheusTextFormatType)
+	if _, err = w.Write([]byte(formatMetrics(transferStatus))); err != nil {
+		log.Debug("Couldn't write transfer metrics:", err.Error())
+	}
+}
+
+// Format the transfer status in the Prometheus text exposition format.
+func formatMetrics(transferStatus *TransferStatus) string {
+	var output strings.Builder
+	addGauge(&output, "running_time_seconds", "The running time of the transfer.", float64(transferStatus.RunningTimeSecs))
+	addGauge(&output, "total_bytes", "The total size of the transferred repositories.", float64(transferStatus.TotalSizeBytes))
+	addGauge(&output, "transferred_bytes", "The size of the data transferred so far.", float64(transferStatus.TransferredSizeBytes))
+	addGauge(&output, "total_repositories", "The number of repositories to transfer.", float64(transferStatus.TotalRepositories))
+	addGauge(&output, "transferred_repositories", "The number of repositories transferred so far.", float64(transferStatus.TransferredRepositories))
+	addGauge(&output, "working_threads", "The number of working threads.", float64(transferStatus.WorkingThreads))
+	addGauge(&output, "failures", "The number of files that failed to transfer.", float64(transferStatus.TransferFailures))
+	if transferStatus.SpeedAvailable {
+		addGauge(&output, "speed_megabytes_per_second", "The transfer speed.", transferStatus.SpeedMBps)
+	}
+	if transferStatus.EstimationAvailable {
+		addGauge(&output, "estimated_remaining_seconds", "The estimated time remaining to complete the transfer.", float64(transferStatus.EstimatedRemainingSecs))
+	}
+	if transferStatus.CurrentRepository != nil && transferStatus.CurrentRepository.Phase != nil {
+		addMetricHeader(&output, "current_phase", "The phase running on the current repository: 0 - full transfer, 1 - files diff, 2 - errors retry.")
+		addRepoMetric(&output, "current_phase", transferStatus.CurrentRepository.Name, float64(*transferStatus.CurrentRepository.Phase))
+	}
+
+	repoMetrics := []struct {
+		name     string
+		help     string
+		getValue func(repo RepositoryStatus) int64
+	}{
+		{"repository_total_bytes", "The total size of the repository.", func(repo RepositoryStatus) int64 { return repo.TotalSizeBytes }},
+		{"repository_transferred_bytes", "The size of the repository data transferred so far.", func(repo RepositoryStatus) int64 { return repo.TransferredSizeBytes }},
+		{"repository_total_files", "The number of files in the repository.", func(repo RepositoryStatus) int64 { return repo.TotalFiles }},
+		{"repository_transferred_files", "The number of repository files transferred so far.", func(repo RepositoryStatus) int64 { return repo.TransferredFiles }},
+	}
+	for _, repoMetric := range repoMetrics {
+		if len(transferStatus.Repositories) == 0 {
+			break
+		}
+		addMetricHeader(&output, repoMetric.name, repoMetric.help)
+		for _, repo := range transferStatus.Repositories {
+			addRepoMetric(&output, repoMetric.name, repo.Name, float64(repoMetric.getValue(repo)))
+		}
+	}
+	return output.String()
+}
+
+func addGauge(output *strings.Builder, name, help string, value float64) {
+	addMetricHeader(output, name, help)
+	output.WriteString(fmt.Sprintf("%s%s %v\n", metricsPrefix, name, value))
+}
+
+func addMetricHeader(output *strings.Builder, name, help string) {
+	output.WriteString(fmt.Sprintf("# HELP %s%s %s\n# TYPE %s%s gauge\n", metricsPrefix, name, help, metricsPrefix, name))
+}
+
+func addRepoMetric(output *strings.Builder, name, repoKey string, value float64) {
+	output.WriteString(fmt.Sprintf("%s%s{repo=\"%s\"} %v\n", metricsPrefix, name, metricsLabelValueReplacer.Replace(repoKey), value))
+}
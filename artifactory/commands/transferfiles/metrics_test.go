@@ -0,0 +1,69 @@
+package transferfiles
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMetrics(t *testing.T) {
+	phase := api.FilesDiffPhase
+	transferStatus := &TransferStatus{
+		Running:                 true,
+		TotalSizeBytes:          2048,
+		TransferredSizeBytes:    1024,
+		TotalRepositories:       2,
+		TransferredRepositories: 1,
+		WorkingThreads:          8,
+		TransferFailures:        3,
+		SpeedAvailable:          true,
+		SpeedMBps:               1.5,
+		CurrentRepository:       &RepositoryStatus{Name: "repo2", Phase: &phase},
+		Repositories: []RepositoryStatus{
+			{Name: "repo1", TotalSizeBytes: 1024, TransferredSizeBytes: 1024, TotalFiles: 2, TransferredFiles: 2},
+			{Name: `repo"2`, TotalSizeBytes: 1024, TotalFiles: 4},
+		},
+	}
+	metrics := formatMetrics(transferStatus)
+	assert.Contains(t, metrics, "# TYPE jfrog_transfer_transferred_bytes gauge\njfrog_transfer_transferred_bytes 1024\n")
+	assert.Contains(t, metrics, "jfrog_transfer_working_threads 8\n")
+	assert.Contains(t, metrics, "jfrog_transfer_failures 3\n")
+	assert.Contains(t, metrics, "jfrog_transfer_speed_megabytes_per_second 1.5\n")
+	assert.NotContains(t, metrics, "jfrog_transfer_estimated_remaining_seconds")
+	assert.Contains(t, metrics, "jfrog_transfer_current_phase{repo=\"repo2\"} 1\n")
+	assert.Contains(t, metrics, "jfrog_transfer_repository_transferred_files{repo=\"repo1\"} 2\n")
+	assert.Contains(t, metrics, "jfrog_transfer_repository_total_files{repo=\"repo\\\"2\"} 4\n")
+}
+
+func TestMetricsServer(t *testing.T) {
+	stateManager, cleanUp := state.InitStateTest(t)
+	defer cleanUp()
+	// Persist the run status on every change, because the metrics are read from the persisted state.
+	previousSaveInterval := state.SaveIntervalSecs
+	state.SaveIntervalSecs = 0
+	defer func() {
+		state.SaveIntervalSecs = previousSaveInterval
+	}()
+	assert.NoError(t, stateManager.SetWorkingThreads(4))
+
+	server, err := startMetricsServer("127.0.0.1:0", time.Now())
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, server.stop())
+	}()
+
+	resp, err := http.Get("http://" + server.address + metricsPath)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, resp.Body.Close())
+	}()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "jfrog_transfer_working_threads 4\n")
+}
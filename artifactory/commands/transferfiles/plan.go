@@ -0,0 +1,253 @@
+package transferfiles
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	estimationNotAvailable = "Not available - no transfer speed was recorded yet"
+)
+
+// TransferPlan describes what a transfer-files run would do, without uploading anything.
+// It is calculated from the source storage info and the existing transfer state, so that an interrupted transfer is planned from where it stopped.
+type TransferPlan struct {
+	Repositories []RepoTransferPlan `json:"repositories"`
+	// True if the transfer would check the existence of the files in the target's filestore before uploading them.
+	CheckExistenceInFilestore bool  `json:"check_existence_in_filestore"`
+	TotalFiles                int64 `json:"total_files"`
+	TotalSizeBytes            int64 `json:"total_size_bytes"`
+	RemainingSizeBytes        int64 `json:"remaining_size_bytes"`
+	// The estimated time is available only if a transfer speed was recorded by a previous run.
+	EstimationAvailable bool   `json:"estimation_available"`
+	EstimatedTimeSec    int64  `json:"estimated_time_sec"`
+	EstimatedTime       string `json:"estimated_time"`
+}
+
+type RepoTransferPlan struct {
+	Name          string `json:"name"`
+	BuildInfoRepo bool   `json:"build_info_repo"`
+	// Repositories missing in the target are skipped by the transfer.
+	ExistsInTarget bool  `json:"exists_in_target"`
+	Files          int64 `json:"files"`
+	SizeBytes      int64 `json:"size_bytes"`
+	// The size left to transfer in the phase the repository starts in. Unknown for the files diff phase, and therefore zero.
+	RemainingSizeBytes  int64  `json:"remaining_size_bytes"`
+	StartPhase          int    `json:"start_phase"`
+	StartPhaseName      string `json:"start_phase_name"`
+	RetryErrors         int    `json:"retry_errors"`
+	EstimationAvailable bool   `json:"estimation_available"`
+	EstimatedTimeSec    int64  `json:"estimated_time_sec"`
+}
+
+type repoTransferPlanRow struct {
+	Name          string `col-name:"Repository"`
+	Files         string `col-name:"Files"`
+	Size          string `col-name:"Size"`
+	RemainingSize string `col-name:"Remaining\nSize"`
+	StartPhase    string `col-name:"Starting\nPhase"`
+	RetryErrors   string `col-name:"Retry\nErrors"`
+	EstimatedTime string `col-name:"Estimated\nTime"`
+}
+
+// Calculate the transfer plan and print it in the requested format.
+func (tdc *TransferFilesCommand) showPlan() error {
+	plan, err := tdc.createTransferPlan()
+	if err != nil {
+		return err
+	}
+	return plan.print(tdc.planFormat)
+}
+
+func (tdc *TransferFilesCommand) createTransferPlan() (*TransferPlan, error) {
+	if err := tdc.initStorageInfoManagers(); err != nil {
+		return nil, err
+	}
+	sourceLocalRepos, sourceBuildInfoRepos, err := tdc.getAllLocalRepos(tdc.sourceServerDetails, tdc.sourceStorageInfoManager)
+	if err != nil {
+		return nil, err
+	}
+	targetLocalRepos, targetBuildInfoRepos, err := tdc.getAllLocalRepos(tdc.targetServerDetails, tdc.targetStorageInfoManager)
+	if err != nil {
+		return nil, err
+	}
+	// Load the run status too, to use the transfer speed recorded by the last run.
+	stateManager, err := state.NewTransferStateManager(true)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := utils.LoadTransferSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &TransferPlan{CheckExistenceInFilestore: tdc.checkExistenceInFilestore}
+	addRepos := func(sourceRepos, targetRepos []string, buildInfoRepo bool) error {
+		threads := utils.DefaultThreads
+		if settings != nil {
+			threads = settings.CalcNumberOfThreads(buildInfoRepo)
+		}
+		for _, repoKey := range sourceRepos {
+			sizeBytes, files, err := tdc.sourceStorageInfoManager.GetReposTotalSizeAndFiles(repoKey)
+			if err != nil {
+				return err
+			}
+			retryErrors := 0
+			if !tdc.ignoreState {
+				if retryErrors, err = getRetryErrorCount([]string{repoKey}); err != nil {
+					return err
+				}
+			}
+			repoPlan := newRepoTransferPlan(repoKey, buildInfoRepo, slices.Contains(targetRepos, repoKey), sizeBytes, files,
+				tdc.getRepoStateForPlan(stateManager, repoKey), &stateManager.TimeEstimationManager, threads)
+			repoPlan.RetryErrors = retryErrors
+			plan.addRepository(repoPlan)
+		}
+		return nil
+	}
+	if err = addRepos(sourceLocalRepos, targetLocalRepos, false); err != nil {
+		return nil, err
+	}
+	if err = addRepos(sourceBuildInfoRepos, targetBuildInfoRepos, true); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Return the state of the repository from previous runs, or nil if the state should be ignored or the repository was never handled.
+func (tdc *TransferFilesCommand) getRepoStateForPlan(stateManager *state.TransferStateManager, repoKey string) *state.Repository {
+	if tdc.ignoreState {
+		return nil
+	}
+	for i := range stateManager.Repositories {
+		if stateManager.Repositories[i].Name == repoKey {
+			return &stateManager.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// Create the plan of a single repository.
+// repoState - The state of the repository from previous runs. Nil if the repository should be transferred from scratch.
+func newRepoTransferPlan(repoKey string, buildInfoRepo, existsInTarget bool, sizeBytes, files int64, repoState *state.Repository,
+	timeEstimationManager *state.TimeEstimationManager, threads int) RepoTransferPlan {
+	repoPlan := RepoTransferPlan{
+		Name:           repoKey,
+		BuildInfoRepo:  buildInfoRepo,
+		ExistsInTarget: existsInTarget,
+		Files:          files,
+		SizeBytes:      sizeBytes,
+		StartPhase:     api.FullTransferPhase,
+	}
+	var remainingFiles int64
+	switch {
+	case repoState == nil:
+		repoPlan.RemainingSizeBytes = sizeBytes
+		remainingFiles = files
+	case repoState.FullTransfer.Ended == "":
+		// The full transfer phase of this repository was interrupted - it will continue from where it stopped.
+		if sizeBytes > repoState.TransferredSizeBytes {
+			repoPlan.RemainingSizeBytes = sizeBytes - repoState.TransferredSizeBytes
+		}
+		if files > repoState.TransferredUnits {
+			remainingFiles = files - repoState.TransferredUnits
+		}
+	default:
+		repoPlan.StartPhase = api.FilesDiffPhase
+	}
+	repoPlan.StartPhaseName = createTransferPhase(repoPlan.StartPhase).getPhaseName()
+	if !existsInTarget {
+		repoPlan.EstimationAvailable = true
+		return repoPlan
+	}
+
+	if buildInfoRepo {
+		repoPlan.EstimatedTimeSec, repoPlan.EstimationAvailable = timeEstimationManager.EstimateTransferTime(0, remainingFiles, threads)
+	} else {
+		repoPlan.EstimatedTimeSec, repoPlan.EstimationAvailable = timeEstimationManager.EstimateTransferTime(repoPlan.RemainingSizeBytes, 0, threads)
+	}
+	return repoPlan
+}
+
+func (tp *TransferPlan) addRepository(repoPlan RepoTransferPlan) {
+	if len(tp.Repositories) == 0 {
+		tp.EstimationAvailable = true
+	}
+	tp.Repositories = append(tp.Repositories, repoPlan)
+	if repoPlan.ExistsInTarget {
+		tp.TotalFiles += repoPlan.Files
+		tp.TotalSizeBytes += repoPlan.SizeBytes
+		tp.RemainingSizeBytes += repoPlan.RemainingSizeBytes
+		tp.EstimationAvailable = tp.EstimationAvailable && repoPlan.EstimationAvailable
+		tp.EstimatedTimeSec += repoPlan.EstimatedTimeSec
+	}
+	tp.EstimatedTime = estimatedTimeToString(tp.EstimatedTimeSec, tp.EstimationAvailable)
+}
+
+func (tp *TransferPlan) print(format coreutils.OutputFormat) error {
+	switch format {
+	case coreutils.JsonFormat:
+		content, err := json.MarshalIndent(tp, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(string(content))
+		return nil
+	case coreutils.TableFormat, "":
+		return tp.printTable()
+	default:
+		return errorutils.CheckErrorf("unsupported transfer plan format: '%s'", format)
+	}
+}
+
+func (tp *TransferPlan) printTable() error {
+	var rows []repoTransferPlanRow
+	for _, repo := range tp.Repositories {
+		row := repoTransferPlanRow{
+			Name:          repo.Name,
+			Files:         strconv.FormatInt(repo.Files, 10),
+			Size:          sizeToString(repo.SizeBytes),
+			RemainingSize: sizeToString(repo.RemainingSizeBytes),
+			StartPhase:    repo.StartPhaseName,
+			RetryErrors:   strconv.Itoa(repo.RetryErrors),
+			EstimatedTime: estimatedTimeToString(repo.EstimatedTimeSec, repo.EstimationAvailable),
+		}
+		if repo.StartPhase == api.FilesDiffPhase {
+			row.RemainingSize = "Unknown"
+		}
+		if !repo.ExistsInTarget {
+			row.StartPhase = "Skipped - missing in target"
+			row.EstimatedTime = "-"
+		}
+		rows = append(rows, row)
+	}
+	if err := coreutils.PrintTable(rows, "Transfer Plan", "No repositories to transfer", false); err != nil {
+		return err
+	}
+
+	var output strings.Builder
+	output.WriteString("\n")
+	addTitle(&output, "Overall Transfer Plan")
+	addString(&output, "📦", "Repositories", strconv.Itoa(len(tp.Repositories)), 2)
+	addString(&output, "📄", "Files", strconv.FormatInt(tp.TotalFiles, 10), 3)
+	addString(&output, "🗄 ", "Storage", sizeToString(tp.RemainingSizeBytes)+" / "+sizeToString(tp.TotalSizeBytes)+" left to transfer", 3)
+	addString(&output, "⌛", "Estimated time", tp.EstimatedTime, 2)
+	log.Output(output.String())
+	return nil
+}
+
+func estimatedTimeToString(estimatedTimeSec int64, estimationAvailable bool) string {
+	if !estimationAvailable {
+		return estimationNotAvailable
+	}
+	return state.EstimatedTimeToString(estimatedTimeSec)
+}
@@ -0,0 +1,64 @@
+package transferfiles
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
+	"github.com/stretchr/testify/assert"
+)
+
+const bytesInMB = 1024 * 1024
+
+func TestNewRepoTransferPlan(t *testing.T) {
+	// 1 MB/s, in bytes/ms
+	timeEstimationManager := &state.TimeEstimationManager{SpeedsAverage: bytesInMB / 1000.0}
+
+	// New repository - transferred from scratch
+	repoPlan := newRepoTransferPlan(repo1Key, false, true, 120*bytesInMB, 10, nil, timeEstimationManager, 8)
+	assert.Equal(t, api.FullTransferPhase, repoPlan.StartPhase)
+	assert.Equal(t, "Full Transfer Phase", repoPlan.StartPhaseName)
+	assert.Equal(t, int64(120*bytesInMB), repoPlan.RemainingSizeBytes)
+	assert.True(t, repoPlan.EstimationAvailable)
+	assert.Equal(t, int64(120), repoPlan.EstimatedTimeSec)
+
+	// Interrupted full transfer - continues from where it stopped
+	repoState := &state.Repository{Name: repo1Key}
+	repoState.TransferredSizeBytes = 60 * bytesInMB
+	repoState.FullTransfer.Started = "2022-10-10T10:00:00Z"
+	repoPlan = newRepoTransferPlan(repo1Key, false, true, 120*bytesInMB, 10, repoState, timeEstimationManager, 8)
+	assert.Equal(t, api.FullTransferPhase, repoPlan.StartPhase)
+	assert.Equal(t, int64(60*bytesInMB), repoPlan.RemainingSizeBytes)
+	assert.Equal(t, int64(60), repoPlan.EstimatedTimeSec)
+
+	// Completed full transfer - starts in the files diff phase
+	repoState.FullTransfer.Ended = "2022-10-10T11:00:00Z"
+	repoPlan = newRepoTransferPlan(repo1Key, false, true, 120*bytesInMB, 10, repoState, timeEstimationManager, 8)
+	assert.Equal(t, api.FilesDiffPhase, repoPlan.StartPhase)
+	assert.Equal(t, "Files Diff Handling Phase", repoPlan.StartPhaseName)
+	assert.Zero(t, repoPlan.RemainingSizeBytes)
+
+	// No speed was recorded yet
+	repoPlan = newRepoTransferPlan(repo1Key, false, true, 120*bytesInMB, 10, nil, &state.TimeEstimationManager{}, 8)
+	assert.False(t, repoPlan.EstimationAvailable)
+
+	// Build-info repository - estimated by the number of files
+	repoPlan = newRepoTransferPlan(repo1Key, true, true, bytesInMB, 8, nil, &state.TimeEstimationManager{}, 8)
+	assert.True(t, repoPlan.EstimationAvailable)
+	assert.Equal(t, int64(1), repoPlan.EstimatedTimeSec)
+}
+
+func TestTransferPlanAddRepository(t *testing.T) {
+	plan := &TransferPlan{}
+	plan.addRepository(RepoTransferPlan{Name: "repo1", ExistsInTarget: true, Files: 10, SizeBytes: 100, RemainingSizeBytes: 50, EstimationAvailable: true, EstimatedTimeSec: 3600})
+	plan.addRepository(RepoTransferPlan{Name: "repo2", ExistsInTarget: false, Files: 20, SizeBytes: 200, RemainingSizeBytes: 200})
+	assert.Len(t, plan.Repositories, 2)
+	assert.Equal(t, int64(10), plan.TotalFiles)
+	assert.Equal(t, int64(100), plan.TotalSizeBytes)
+	assert.Equal(t, int64(50), plan.RemainingSizeBytes)
+	assert.Equal(t, "About 1 hour", plan.EstimatedTime)
+
+	plan.addRepository(RepoTransferPlan{Name: "repo3", ExistsInTarget: true, Files: 1, SizeBytes: 1, RemainingSizeBytes: 1})
+	assert.False(t, plan.EstimationAvailable)
+	assert.Equal(t, estimationNotAvailable, plan.EstimatedTime)
+}
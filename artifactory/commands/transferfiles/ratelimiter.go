@@ -0,0 +1,90 @@
+package transferfiles
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const waitTimeBetweenRateLimitChecksSeconds = 1
+
+// Limits the rate of the files and bytes sent to the source Artifactory instance to be transferred.
+// The limits are taken from the transfer settings, and are updated on runtime together with the number of threads.
+type transferRateLimiter struct {
+	mutex          sync.Mutex
+	maxBytesPerSec int64
+	maxFilesPerSec int64
+	// The time from which the next chunk is allowed to be sent
+	nextAllowedTime time.Time
+}
+
+var rateLimiter = &transferRateLimiter{}
+
+func (trl *transferRateLimiter) setLimits(maxBytesPerSec, maxFilesPerSec int64) {
+	trl.mutex.Lock()
+	defer trl.mutex.Unlock()
+	if trl.maxBytesPerSec == maxBytesPerSec && trl.maxFilesPerSec == maxFilesPerSec {
+		return
+	}
+	trl.maxBytesPerSec = maxBytesPerSec
+	trl.maxFilesPerSec = maxFilesPerSec
+	log.Info("Transfer rate limits have been updated to " + rateLimitToString(maxBytesPerSec, "bytes") + " and " + rateLimitToString(maxFilesPerSec, "files"))
+}
+
+func rateLimitToString(limit int64, unit string) string {
+	if limit <= 0 {
+		return "unlimited " + unit + " per second"
+	}
+	return strconv.FormatInt(limit, 10) + " " + unit + " per second"
+}
+
+// Reserves sending a chunk with the given number of files and bytes.
+// Returns the duration to wait before the chunk can be sent.
+func (trl *transferRateLimiter) reserve(files int, sizeBytes int64, now time.Time) time.Duration {
+	trl.mutex.Lock()
+	defer trl.mutex.Unlock()
+	var chunkDuration time.Duration
+	if trl.maxFilesPerSec > 0 {
+		chunkDuration = time.Duration(float64(files) / float64(trl.maxFilesPerSec) * float64(time.Second))
+	}
+	if trl.maxBytesPerSec > 0 {
+		bytesDuration := time.Duration(float64(sizeBytes) / float64(trl.maxBytesPerSec) * float64(time.Second))
+		if bytesDuration > chunkDuration {
+			chunkDuration = bytesDuration
+		}
+	}
+	if chunkDuration == 0 {
+		return 0
+	}
+	if trl.nextAllowedTime.Before(now) {
+		trl.nextAllowedTime = now
+	}
+	waitTime := trl.nextAllowedTime.Sub(now)
+	trl.nextAllowedTime = trl.nextAllowedTime.Add(chunkDuration)
+	return waitTime
+}
+
+// Waits until the chunk is allowed to be sent according to the rate limits.
+// Returns true if the transfer should be stopped while waiting.
+func waitForRateLimit(phaseBase *phaseBase, chunk api.UploadChunk, errorsChannelMng *ErrorsChannelMng) (stopped bool) {
+	var chunkSizeBytes int64
+	for _, file := range chunk.UploadCandidates {
+		chunkSizeBytes += file.Size
+	}
+	waitTime := rateLimiter.reserve(len(chunk.UploadCandidates), chunkSizeBytes, time.Now())
+	for waitTime > 0 {
+		if ShouldStop(phaseBase, nil, errorsChannelMng) {
+			return true
+		}
+		sleepTime := waitTime
+		if sleepTime > waitTimeBetweenRateLimitChecksSeconds*time.Second {
+			sleepTime = waitTimeBetweenRateLimitChecksSeconds * time.Second
+		}
+		time.Sleep(sleepTime)
+		waitTime -= sleepTime
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package transferfiles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := &transferRateLimiter{}
+	now := time.Now()
+
+	// No limits
+	assert.Zero(t, limiter.reserve(16, 1024, now))
+	assert.Zero(t, limiter.reserve(16, 1024, now))
+
+	// 8 files per second - a chunk of 16 files takes 2 seconds
+	limiter.setLimits(0, 8)
+	assert.Zero(t, limiter.reserve(16, 1024, now))
+	assert.Equal(t, 2*time.Second, limiter.reserve(16, 1024, now))
+	assert.Equal(t, 3*time.Second, limiter.reserve(16, 1024, now.Add(time.Second)))
+
+	// The bytes limit is stricter - a chunk of 1024 bytes takes 4 seconds
+	limiter = &transferRateLimiter{}
+	limiter.setLimits(256, 8)
+	assert.Zero(t, limiter.reserve(16, 1024, now))
+	assert.Equal(t, 4*time.Second, limiter.reserve(16, 1024, now))
+
+	// No waiting after the reserved time has passed
+	assert.Zero(t, limiter.reserve(16, 1024, now.Add(time.Minute)))
+}
@@ -90,6 +90,9 @@ func loadTransferRunStatus() (*TransferRunStatus, error) {
 		return transferRunStatus, nil
 	}
 
+	// Avoid reading the file while it is written by a transfer running in this process.
+	saveRunStatusMutex.Lock()
+	defer saveRunStatusMutex.Unlock()
 	content, err := fileutils.ReadFile(statusFilePath)
 	if err != nil {
 		return nil, err
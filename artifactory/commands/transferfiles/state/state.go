@@ -119,6 +119,9 @@ func loadTransferState() (*TransferState, error) {
 		return transferState, nil
 	}
 
+	// Avoid reading the file while it is written by a transfer running in this process.
+	saveStateMutex.Lock()
+	defer saveStateMutex.Unlock()
 	content, err := fileutils.ReadFile(stateFilePath)
 	if err != nil {
 		return nil, err
@@ -370,12 +370,20 @@ func getStartTimestamp() (int64, error) {
 }
 
 func GetRunningTime() (runningTime string, isRunning bool, err error) {
+	runningSecs, isRunning, err := GetRunningTimeSecs()
+	if err != nil || !isRunning {
+		return
+	}
+	return secondsToLiteralTime(runningSecs, ""), true, nil
+}
+
+// GetRunningTimeSecs gets the running time of the transfer in seconds, and whether it is running.
+func GetRunningTimeSecs() (runningSecs int64, isRunning bool, err error) {
 	startTimestamp, err := getStartTimestamp()
 	if err != nil || startTimestamp == 0 {
 		return
 	}
-	runningSecs := int64(time.Since(time.Unix(0, startTimestamp)).Seconds())
-	return secondsToLiteralTime(runningSecs, ""), true, nil
+	return int64(time.Since(time.Unix(0, startTimestamp)).Seconds()), true, nil
 }
 
 func UpdateChunkInState(stateManager *TransferStateManager, repoKey string, chunk *api.ChunkStatus) (chunkTotalSizeInBytes int64, err error) {
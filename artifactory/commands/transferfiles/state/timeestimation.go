@@ -91,6 +91,14 @@ func (tem *TimeEstimationManager) getSpeed() float64 {
 	return tem.SpeedsAverage * bytesPerMilliSecToMBPerSec
 }
 
+// GetSpeed gets the transfer speed in MB/s, and whether it is available.
+func (tem *TimeEstimationManager) GetSpeed() (float64, bool) {
+	if tem.stateManager.BuildInfoRepo || len(tem.LastSpeeds) == 0 {
+		return 0, false
+	}
+	return tem.getSpeed(), true
+}
+
 // GetSpeedString gets the transfer speed in an easy-to-read string.
 func (tem *TimeEstimationManager) GetSpeedString() string {
 	if tem.stateManager.BuildInfoRepo {
@@ -188,6 +196,44 @@ func (tem *TimeEstimationManager) GetEstimatedRemainingTimeString() string {
 	return secondsToLiteralTime(remainingTimeSec, "About ")
 }
 
+// GetEstimatedRemainingTime gets the estimated remaining time in seconds, and whether it is available.
+func (tem *TimeEstimationManager) GetEstimatedRemainingTime() (int64, bool, error) {
+	if !tem.isTimeEstimationAvailable() || (!tem.stateManager.BuildInfoRepo && len(tem.LastSpeeds) == 0) {
+		return 0, false, nil
+	}
+	remainingTimeSec, err := tem.getEstimatedRemainingTime()
+	return remainingTimeSec, err == nil, err
+}
+
 func (tem *TimeEstimationManager) isTimeEstimationAvailable() bool {
 	return tem.stateManager.CurrentRepoPhase == api.FullTransferPhase || tem.stateManager.CurrentRepoPhase == api.ErrorsPhase
 }
+
+// EstimateTransferTime estimates the time in seconds it would take to transfer the given amount of data and build-info files,
+// based on the average speed recorded by the last transfer run.
+// Returns false if the data size is positive and no speed was recorded yet.
+func (tem *TimeEstimationManager) EstimateTransferTime(dataSizeBytes, buildInfoFiles int64, workingThreads int) (int64, bool) {
+	var estimatedTimeSec int64
+	if dataSizeBytes > 0 {
+		if tem.SpeedsAverage == 0 {
+			return 0, false
+		}
+		// Convert from milliseconds to seconds.
+		estimatedTimeSec = int64(float64(dataSizeBytes)/tem.SpeedsAverage) / milliSecsInSecond
+	}
+	if buildInfoFiles > 0 {
+		if workingThreads <= 0 {
+			workingThreads = 1
+		}
+		if workingThreads > utils.MaxBuildInfoThreads {
+			workingThreads = utils.MaxBuildInfoThreads
+		}
+		estimatedTimeSec += int64(float64(buildInfoFiles) * buildInfoAverageIndexTimeSec / float64(workingThreads))
+	}
+	return estimatedTimeSec, true
+}
+
+// EstimatedTimeToString converts an estimated time in seconds to an easy-to-read string.
+func EstimatedTimeToString(estimatedTimeSec int64) string {
+	return secondsToLiteralTime(estimatedTimeSec, "About ")
+}
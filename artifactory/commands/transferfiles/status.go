@@ -1,10 +1,12 @@
 package transferfiles
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"strconv"
 	"strings"
@@ -37,6 +39,96 @@ func ShowStatus() error {
 	return nil
 }
 
+// TransferStatus is the machine-readable status of the running transfer.
+type TransferStatus struct {
+	Running                 bool               `json:"running"`
+	RunningTimeSecs         int64              `json:"running_time_secs,omitempty"`
+	TotalSizeBytes          int64              `json:"total_size_bytes"`
+	TransferredSizeBytes    int64              `json:"transferred_size_bytes"`
+	TotalRepositories       int64              `json:"total_repositories"`
+	TransferredRepositories int64              `json:"transferred_repositories"`
+	WorkingThreads          int                `json:"working_threads"`
+	TransferFailures        uint               `json:"transfer_failures"`
+	SpeedAvailable          bool               `json:"speed_available"`
+	SpeedMBps               float64            `json:"speed_mbps,omitempty"`
+	EstimationAvailable     bool               `json:"estimation_available"`
+	EstimatedRemainingSecs  int64              `json:"estimated_remaining_secs,omitempty"`
+	CurrentRepository       *RepositoryStatus  `json:"current_repository,omitempty"`
+	Repositories            []RepositoryStatus `json:"repositories,omitempty"`
+}
+
+type RepositoryStatus struct {
+	Name                 string `json:"name"`
+	TotalSizeBytes       int64  `json:"total_size_bytes"`
+	TransferredSizeBytes int64  `json:"transferred_size_bytes"`
+	TotalFiles           int64  `json:"total_files"`
+	TransferredFiles     int64  `json:"transferred_files"`
+	// The phase currently running. Set for the current repository only.
+	Phase         *int `json:"phase,omitempty"`
+	BuildInfoRepo bool `json:"build_info_repo,omitempty"`
+}
+
+// ShowStatusJson prints the status of the running transfer in JSON format.
+func ShowStatusJson() error {
+	transferStatus := &TransferStatus{}
+	runningTimeSecs, isRunning, err := state.GetRunningTimeSecs()
+	if err != nil {
+		return err
+	}
+	if isRunning {
+		stateManager, err := state.NewTransferStateManager(true)
+		if err != nil {
+			return err
+		}
+		if transferStatus, err = newTransferStatus(stateManager, runningTimeSecs); err != nil {
+			return err
+		}
+	}
+	content, err := json.MarshalIndent(transferStatus, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Output(string(content))
+	return nil
+}
+
+func newTransferStatus(stateManager *state.TransferStateManager, runningTimeSecs int64) (*TransferStatus, error) {
+	transferStatus := &TransferStatus{
+		Running:                 true,
+		RunningTimeSecs:         runningTimeSecs,
+		TotalSizeBytes:          stateManager.OverallTransfer.TotalSizeBytes,
+		TransferredSizeBytes:    stateManager.OverallTransfer.TransferredSizeBytes,
+		TotalRepositories:       stateManager.TotalRepositories.TotalUnits,
+		TransferredRepositories: stateManager.TotalRepositories.TransferredUnits,
+		WorkingThreads:          stateManager.WorkingThreads,
+		TransferFailures:        stateManager.TransferFailures,
+	}
+	transferStatus.SpeedMBps, transferStatus.SpeedAvailable = stateManager.GetSpeed()
+	var err error
+	transferStatus.EstimatedRemainingSecs, transferStatus.EstimationAvailable, err = stateManager.GetEstimatedRemainingTime()
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range stateManager.Repositories {
+		repoStatus := RepositoryStatus{
+			Name:                 repo.Name,
+			TotalSizeBytes:       repo.TotalSizeBytes,
+			TransferredSizeBytes: repo.TransferredSizeBytes,
+			TotalFiles:           repo.TotalUnits,
+			TransferredFiles:     repo.TransferredUnits,
+		}
+		if repo.Name == stateManager.CurrentRepo {
+			phase := stateManager.CurrentRepoPhase
+			repoStatus.Phase = &phase
+			repoStatus.BuildInfoRepo = stateManager.BuildInfoRepo
+			currentRepo := repoStatus
+			transferStatus.CurrentRepository = &currentRepo
+		}
+		transferStatus.Repositories = append(transferStatus.Repositories, repoStatus)
+	}
+	return transferStatus, nil
+}
+
 func addOverallStatus(stateManager *state.TransferStateManager, output *strings.Builder, runningTime string) {
 	addTitle(output, "Overall Transfer Status")
 	addString(output, "🟢", "Status", "Running", 3)
@@ -2,6 +2,7 @@ package transferfiles
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
@@ -138,6 +139,37 @@ func TestShowBuildInfoRepo(t *testing.T) {
 	assert.Contains(t, results, "Files:		500 / 10000 (5.0%)")
 }
 
+func TestShowStatusJson(t *testing.T) {
+	buffer, cleanUp := initStatusTest(t)
+	defer cleanUp()
+
+	// Not running
+	assert.NoError(t, ShowStatusJson())
+	var transferStatus TransferStatus
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &transferStatus))
+	assert.False(t, transferStatus.Running)
+	buffer.Reset()
+
+	// Create state manager and persist to file system
+	createStateManager(t, api.FullTransferPhase, false)
+	assert.NoError(t, ShowStatusJson())
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &transferStatus))
+	assert.True(t, transferStatus.Running)
+	assert.Equal(t, int64(11111), transferStatus.TotalSizeBytes)
+	assert.Equal(t, int64(5000), transferStatus.TransferredSizeBytes)
+	assert.Equal(t, int64(1111), transferStatus.TotalRepositories)
+	assert.Equal(t, int64(15), transferStatus.TransferredRepositories)
+	assert.Equal(t, 16, transferStatus.WorkingThreads)
+	assert.Equal(t, uint(223), transferStatus.TransferFailures)
+	assert.True(t, transferStatus.SpeedAvailable)
+	assert.True(t, transferStatus.EstimationAvailable)
+	assert.NotNil(t, transferStatus.CurrentRepository)
+	assert.Equal(t, repo1Key, transferStatus.CurrentRepository.Name)
+	assert.Equal(t, api.FullTransferPhase, *transferStatus.CurrentRepository.Phase)
+	assert.Equal(t, int64(500), transferStatus.CurrentRepository.TransferredFiles)
+	assert.Len(t, transferStatus.Repositories, 1)
+}
+
 // Create state manager and persist in the file system.
 // t     - The testing object
 // phase - Phase ID
@@ -49,6 +49,10 @@ type TransferFilesCommand struct {
 	ignoreState               bool
 	proxyKey                  string
 	status                    bool
+	statusFormat              coreutils.OutputFormat
+	metricsAddress            string
+	dryRun                    bool
+	planFormat                coreutils.OutputFormat
 	stateManager              *state.TransferStateManager
 }
 
@@ -96,10 +100,34 @@ func (tdc *TransferFilesCommand) SetStatus(status bool) {
 	tdc.status = status
 }
 
+func (tdc *TransferFilesCommand) SetStatusFormat(statusFormat coreutils.OutputFormat) {
+	tdc.statusFormat = statusFormat
+}
+
+// When the metrics address is set, the transfer metrics are served in the Prometheus format on this address while the transfer is running.
+func (tdc *TransferFilesCommand) SetMetricsAddress(metricsAddress string) {
+	tdc.metricsAddress = metricsAddress
+}
+
+// When dry run is set, the command prints the transfer plan without transferring any file.
+func (tdc *TransferFilesCommand) SetDryRun(dryRun bool) {
+	tdc.dryRun = dryRun
+}
+
+func (tdc *TransferFilesCommand) SetPlanFormat(planFormat coreutils.OutputFormat) {
+	tdc.planFormat = planFormat
+}
+
 func (tdc *TransferFilesCommand) Run() (err error) {
 	if tdc.status {
+		if tdc.statusFormat == coreutils.JsonFormat {
+			return ShowStatusJson()
+		}
 		return ShowStatus()
 	}
+	if tdc.dryRun {
+		return tdc.showPlan()
+	}
 	if err := tdc.stateManager.TryLockTransferStateManager(); err != nil {
 		return err
 	}
@@ -153,6 +181,19 @@ func (tdc *TransferFilesCommand) Run() (err error) {
 		return err
 	}
 
+	if tdc.metricsAddress != "" {
+		var metrics *metricsServer
+		metrics, err = startMetricsServer(tdc.metricsAddress, tdc.timeStarted)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if e := metrics.stop(); err == nil {
+				err = e
+			}
+		}()
+	}
+
 	// Init and Set progress bar with the length of the source local and build info repositories
 	err = initTransferProgressMng(allSourceLocalRepos, tdc, 0)
 	if err != nil {
@@ -296,7 +337,7 @@ func (tdc *TransferFilesCommand) transferSingleRepo(sourceRepoKey string, target
 		}
 	}()
 
-	if err = tdc.initCurThreads(buildInfoRepo); err != nil {
+	if err = initCurThreads(buildInfoRepo); err != nil {
 		return
 	}
 	for currentPhaseId := 0; currentPhaseId < NumberOfPhases; currentPhaseId++ {
@@ -451,15 +492,20 @@ func (tdc *TransferFilesCommand) initNewPhase(newPhase transferPhase, srcUpServi
 // serverDetails      - Source or target server details
 // storageInfoManager - Source or target storage info manager
 func (tdc *TransferFilesCommand) getAllLocalRepos(serverDetails *config.ServerDetails, storageInfoManager *utils.StorageInfoManager) ([]string, []string, error) {
-	serviceManager, err := createTransferServiceManager(tdc.context, serverDetails)
+	return getAllLocalRepos(tdc.context, serverDetails, storageInfoManager, tdc.includeReposPatterns, tdc.excludeReposPatterns)
+}
+
+func getAllLocalRepos(ctx context.Context, serverDetails *config.ServerDetails, storageInfoManager *utils.StorageInfoManager,
+	includeReposPatterns, excludeReposPatterns []string) ([]string, []string, error) {
+	serviceManager, err := createTransferServiceManager(ctx, serverDetails)
 	if err != nil {
 		return []string{}, []string{}, err
 	}
-	localRepos, err := utils.GetFilteredRepositoriesByNameAndType(serviceManager, tdc.includeReposPatterns, tdc.excludeReposPatterns, utils.Local)
+	localRepos, err := utils.GetFilteredRepositoriesByNameAndType(serviceManager, includeReposPatterns, excludeReposPatterns, utils.Local)
 	if err != nil {
 		return []string{}, []string{}, err
 	}
-	federatedRepos, err := utils.GetFilteredRepositoriesByNameAndType(serviceManager, tdc.includeReposPatterns, tdc.excludeReposPatterns, utils.Federated)
+	federatedRepos, err := utils.GetFilteredRepositoriesByNameAndType(serviceManager, includeReposPatterns, excludeReposPatterns, utils.Federated)
 	if err != nil {
 		return []string{}, []string{}, err
 	}
@@ -469,7 +515,7 @@ func (tdc *TransferFilesCommand) getAllLocalRepos(serverDetails *config.ServerDe
 		return []string{}, []string{}, err
 	}
 
-	buildInfoRepoKeys, err := utils.GetFilteredBuildInfoRepositories(storageInfo, tdc.includeReposPatterns, tdc.excludeReposPatterns)
+	buildInfoRepoKeys, err := utils.GetFilteredBuildInfoRepositories(storageInfo, includeReposPatterns, excludeReposPatterns)
 	if err != nil {
 		return []string{}, []string{}, err
 	}
@@ -477,7 +523,7 @@ func (tdc *TransferFilesCommand) getAllLocalRepos(serverDetails *config.ServerDe
 	return append(localRepos, federatedRepos...), buildInfoRepoKeys, err
 }
 
-func (tdc *TransferFilesCommand) initCurThreads(buildInfoRepo bool) error {
+func initCurThreads(buildInfoRepo bool) error {
 	// Use default threads if settings file doesn't exist or an error occurred.
 	curThreads = utils.DefaultThreads
 	settings, err := utils.LoadTransferSettings()
@@ -485,7 +531,12 @@ func (tdc *TransferFilesCommand) initCurThreads(buildInfoRepo bool) error {
 		return err
 	}
 	if settings != nil {
-		curThreads = settings.CalcNumberOfThreads(buildInfoRepo)
+		rateLimiter.setLimits(settings.MaxBytesPerSec, settings.MaxFilesPerSec)
+		curThreads = settings.CalcCurrentNumberOfThreads(buildInfoRepo, time.Now())
+		if curThreads == 0 {
+			log.Info("The transfer is outside of the allowed time windows. Pausing until the next time window starts...")
+			return nil
+		}
 		if buildInfoRepo && curThreads < settings.ThreadsNumber {
 			log.Info("Build info transferring - using reduced number of threads")
 		}
@@ -0,0 +1,478 @@
+package transferfiles
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/state"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+// TransferErrorsCommand summarises the transfer failures collected in the transfer errors directory,
+// and optionally retries a subset of them without running the transfer phases.
+type TransferErrorsCommand struct {
+	context              context.Context
+	cancelFunc           context.CancelFunc
+	sourceServerDetails  *config.ServerDetails
+	targetServerDetails  *config.ServerDetails
+	includeReposPatterns []string
+	excludeReposPatterns []string
+	// Status codes to include. Each status code may contain 'x' as a wildcard digit, for example '5xx'.
+	statusCodes []string
+	retry       bool
+	format      coreutils.OutputFormat
+	proxyKey    string
+}
+
+func NewTransferErrorsCommand(sourceServer, targetServer *config.ServerDetails) *TransferErrorsCommand {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	return &TransferErrorsCommand{
+		context:             ctx,
+		cancelFunc:          cancelFunc,
+		sourceServerDetails: sourceServer,
+		targetServerDetails: targetServer,
+	}
+}
+
+func (tec *TransferErrorsCommand) CommandName() string {
+	return "rt_transfer_errors"
+}
+
+func (tec *TransferErrorsCommand) ServerDetails() (*config.ServerDetails, error) {
+	return tec.sourceServerDetails, nil
+}
+
+func (tec *TransferErrorsCommand) SetIncludeReposPatterns(includeReposPatterns []string) *TransferErrorsCommand {
+	tec.includeReposPatterns = includeReposPatterns
+	return tec
+}
+
+func (tec *TransferErrorsCommand) SetExcludeReposPatterns(excludeReposPatterns []string) *TransferErrorsCommand {
+	tec.excludeReposPatterns = excludeReposPatterns
+	return tec
+}
+
+func (tec *TransferErrorsCommand) SetStatusCodes(statusCodes []string) *TransferErrorsCommand {
+	tec.statusCodes = statusCodes
+	return tec
+}
+
+func (tec *TransferErrorsCommand) SetRetry(retry bool) *TransferErrorsCommand {
+	tec.retry = retry
+	return tec
+}
+
+func (tec *TransferErrorsCommand) SetFormat(format coreutils.OutputFormat) *TransferErrorsCommand {
+	tec.format = format
+	return tec
+}
+
+func (tec *TransferErrorsCommand) SetProxyKey(proxyKey string) *TransferErrorsCommand {
+	tec.proxyKey = proxyKey
+	return tec
+}
+
+// TransferErrorsSummary groups the transfer failures by repository, status code and reason.
+type TransferErrorsSummary struct {
+	TotalRetryable int                   `json:"total_retryable"`
+	TotalSkipped   int                   `json:"total_skipped"`
+	Groups         []TransferErrorsGroup `json:"groups"`
+}
+
+type TransferErrorsGroup struct {
+	Repo       string `json:"repo"`
+	StatusCode int    `json:"status_code"`
+	Reason     string `json:"reason"`
+	// Skipped errors are caused by known limitations, and are not retried by the transfer.
+	Retryable bool `json:"retryable"`
+	Count     int  `json:"count"`
+}
+
+type transferErrorsGroupRow struct {
+	Repo       string `col-name:"Repository"`
+	StatusCode string `col-name:"Status\nCode"`
+	Reason     string `col-name:"Reason"`
+	Retryable  string `col-name:"Retryable"`
+	Count      string `col-name:"Count"`
+}
+
+type errorsGroupKey struct {
+	repo       string
+	statusCode int
+	reason     string
+	retryable  bool
+}
+
+func (tec *TransferErrorsCommand) Run() error {
+	if tec.retry {
+		if err := tec.retryErrors(); err != nil {
+			return err
+		}
+	}
+	summary, err := tec.createErrorsSummary()
+	if err != nil {
+		return err
+	}
+	return summary.print(tec.format)
+}
+
+func (tec *TransferErrorsCommand) createErrorsSummary() (*TransferErrorsSummary, error) {
+	summary := &TransferErrorsSummary{}
+	groups := make(map[errorsGroupKey]int)
+	for _, retryable := range []bool{true, false} {
+		errorsFiles, err := getAllErrorsFiles(retryable)
+		if err != nil {
+			return nil, err
+		}
+		for _, errorsFile := range errorsFiles {
+			failedFiles, err := readErrorFile(errorsFile)
+			if err != nil {
+				return nil, err
+			}
+			for _, failedFile := range failedFiles.Errors {
+				include, err := tec.shouldIncludeError(failedFile)
+				if err != nil {
+					return nil, err
+				}
+				if !include {
+					continue
+				}
+				if retryable {
+					summary.TotalRetryable++
+				} else {
+					summary.TotalSkipped++
+				}
+				groups[errorsGroupKey{repo: failedFile.Repo, statusCode: failedFile.StatusCode, reason: failedFile.Reason, retryable: retryable}]++
+			}
+		}
+	}
+	for key, count := range groups {
+		summary.Groups = append(summary.Groups, TransferErrorsGroup{Repo: key.repo, StatusCode: key.statusCode, Reason: key.reason, Retryable: key.retryable, Count: count})
+	}
+	// Sort by repository, and then by the number of errors in descending order
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		if summary.Groups[i].Repo != summary.Groups[j].Repo {
+			return summary.Groups[i].Repo < summary.Groups[j].Repo
+		}
+		if summary.Groups[i].Count != summary.Groups[j].Count {
+			return summary.Groups[i].Count > summary.Groups[j].Count
+		}
+		return summary.Groups[i].Reason < summary.Groups[j].Reason
+	})
+	return summary, nil
+}
+
+func (tec *TransferErrorsCommand) shouldIncludeError(failedFile ExtendedFileUploadStatusResponse) (bool, error) {
+	repoFilter := &utils.RepositoryFilter{IncludePatterns: tec.includeReposPatterns, ExcludePatterns: tec.excludeReposPatterns}
+	include, err := repoFilter.ShouldIncludeRepository(failedFile.Repo)
+	if err != nil || !include {
+		return false, err
+	}
+	return matchStatusCode(tec.statusCodes, failedFile.StatusCode), nil
+}
+
+// Return true if no status codes patterns are provided, or if the status code matches one of the patterns.
+// In the patterns, 'x' matches any digit. For example, '5xx' matches all server errors.
+func matchStatusCode(statusCodesPatterns []string, statusCode int) bool {
+	if len(statusCodesPatterns) == 0 {
+		return true
+	}
+	statusCodeStr := strconv.Itoa(statusCode)
+	for _, pattern := range statusCodesPatterns {
+		pattern = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(pattern)), "x", "[0-9]")
+		if matched, err := path.Match(pattern, statusCodeStr); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry the retryable errors that match the filters.
+// The retried errors are removed from the errors files, and new failures are written to new errors files.
+func (tec *TransferErrorsCommand) retryErrors() (err error) {
+	stateManager, err := state.NewTransferStateManager(true)
+	if err != nil {
+		return err
+	}
+	// Make sure the transfer-files command isn't running while the errors are retried.
+	if err = stateManager.TryLockTransferStateManager(); err != nil {
+		return err
+	}
+	defer func() {
+		if e := stateManager.UnlockTransferStateManager(); err == nil {
+			err = e
+		}
+	}()
+
+	errorsToRetry, err := tec.collectErrorsToRetry()
+	if err != nil || len(errorsToRetry) == 0 {
+		if err == nil {
+			log.Info("No transfer errors to retry.")
+		}
+		return err
+	}
+
+	srcUpService, err := createSrcRtUserPluginServiceManager(tec.context, tec.sourceServerDetails)
+	if err != nil {
+		return err
+	}
+	if err = getAndValidateDataTransferPlugin(srcUpService); err != nil {
+		return err
+	}
+	storageInfoManager, err := utils.NewStorageInfoManager(tec.context, tec.sourceServerDetails)
+	if err != nil {
+		return err
+	}
+	if err = storageInfoManager.CalculateStorageInfo(); err != nil {
+		return err
+	}
+
+	finishStopping := tec.handleStop(srcUpService)
+	defer finishStopping()
+
+	repoKeys := make([]string, 0, len(errorsToRetry))
+	for repoKey := range errorsToRetry {
+		repoKeys = append(repoKeys, repoKey)
+	}
+	sort.Strings(repoKeys)
+	for _, repoKey := range repoKeys {
+		if tec.context.Err() != nil {
+			return new(InterruptionErr)
+		}
+		if err = tec.retryRepoErrors(repoKey, errorsToRetry[repoKey], srcUpService, storageInfoManager, stateManager); err != nil {
+			return err
+		}
+	}
+	return stateManager.SaveState()
+}
+
+// The errors to retry of a single repository
+type repoErrorsToRetry struct {
+	// The errors files that contain errors to retry, mapped to the errors in the file that should not be retried
+	errorsFiles map[string][]ExtendedFileUploadStatusResponse
+	errors      []ExtendedFileUploadStatusResponse
+}
+
+// Read the retryable errors files, and collect the errors that match the filters by repository.
+func (tec *TransferErrorsCommand) collectErrorsToRetry() (map[string]*repoErrorsToRetry, error) {
+	errorsFiles, err := getAllErrorsFiles(true)
+	if err != nil {
+		return nil, err
+	}
+	errorsToRetry := make(map[string]*repoErrorsToRetry)
+	for _, errorsFile := range errorsFiles {
+		failedFiles, err := readErrorFile(errorsFile)
+		if err != nil {
+			return nil, err
+		}
+		var remaining []ExtendedFileUploadStatusResponse
+		var toRetry []ExtendedFileUploadStatusResponse
+		for _, failedFile := range failedFiles.Errors {
+			include, err := tec.shouldIncludeError(failedFile)
+			if err != nil {
+				return nil, err
+			}
+			if include {
+				toRetry = append(toRetry, failedFile)
+			} else {
+				remaining = append(remaining, failedFile)
+			}
+		}
+		if len(toRetry) == 0 {
+			continue
+		}
+		repoKey := getErrorsFileRepoKey(errorsFile)
+		if _, exist := errorsToRetry[repoKey]; !exist {
+			errorsToRetry[repoKey] = &repoErrorsToRetry{errorsFiles: make(map[string][]ExtendedFileUploadStatusResponse)}
+		}
+		errorsToRetry[repoKey].errorsFiles[errorsFile] = remaining
+		errorsToRetry[repoKey].errors = append(errorsToRetry[repoKey].errors, toRetry...)
+	}
+	return errorsToRetry, nil
+}
+
+func (tec *TransferErrorsCommand) retryRepoErrors(repoKey string, repoErrors *repoErrorsToRetry, srcUpService *srcUserPluginService,
+	storageInfoManager *utils.StorageInfoManager, stateManager *state.TransferStateManager) error {
+	repoSummary, err := storageInfoManager.GetRepoSummary(repoKey)
+	if err != nil {
+		return err
+	}
+	storageInfo, err := storageInfoManager.GetStorageInfo()
+	if err != nil {
+		return err
+	}
+	buildInfoRepos, err := utils.GetFilteredBuildInfoRepositories(storageInfo, []string{repoKey}, nil)
+	if err != nil {
+		return err
+	}
+	buildInfoRepo := len(buildInfoRepos) > 0
+	if err = initCurThreads(buildInfoRepo); err != nil {
+		return err
+	}
+	if err = stateManager.SetRepoPhase(api.ErrorsPhase); err != nil {
+		return err
+	}
+
+	base := phaseBase{
+		context:         tec.context,
+		repoKey:         repoKey,
+		buildInfoRepo:   buildInfoRepo,
+		packageType:     repoSummary.PackageType,
+		phaseId:         api.ErrorsPhase,
+		startTime:       time.Now(),
+		srcUpService:    srcUpService,
+		srcRtDetails:    tec.sourceServerDetails,
+		targetRtDetails: tec.targetServerDetails,
+		repoSummary:     *repoSummary,
+		proxyKey:        tec.proxyKey,
+		stateManager:    stateManager,
+	}
+	// The errors files that exist before the retry. Errors files created during the retry hold the errors that failed again.
+	previousErrorsFiles, err := getErrorsFiles([]string{repoKey}, true)
+	if err != nil {
+		return err
+	}
+	printPhaseChange("Retrying " + strconv.Itoa(len(repoErrors.errors)) + " transfer errors of repo '" + repoKey + "'...")
+	base.transferManager = newTransferManager(base, getDelayUploadComparisonFunctions(repoSummary.PackageType))
+	var shouldStop bool
+	action := func(pcWrapper *producerConsumerWrapper, uploadChunkChan chan UploadedChunk, delayHelper delayUploadHelper, errorsChannelMng *ErrorsChannelMng) error {
+		_, err := pcWrapper.chunkBuilderProducerConsumer.AddTaskWithError(func(int) error {
+			var err error
+			shouldStop, err = uploadByChunks(convertUploadStatusToFileRepresentation(repoErrors.errors), uploadChunkChan, base, delayHelper, errorsChannelMng, pcWrapper)
+			return err
+		}, pcWrapper.errorsQueue.AddError)
+		return err
+	}
+	if err = base.transferManager.doTransferWithProducerConsumer(action, consumeAllDelayFiles); err != nil {
+		return err
+	}
+	if shouldStop || tec.context.Err() != nil {
+		// Keep the errors files as they are, since some of the errors were not retried.
+		return nil
+	}
+	failedAgain, err := countNewErrors(repoKey, previousErrorsFiles)
+	if err != nil {
+		return err
+	}
+	// The errors that failed again were written to new errors files. The rest were healed.
+	if healed := len(repoErrors.errors) - failedAgain; healed > 0 {
+		if err = stateManager.ChangeTransferFailureCountBy(uint(healed), false); err != nil {
+			return err
+		}
+	}
+	if err = removeRetriedErrors(repoErrors.errorsFiles); err != nil {
+		return err
+	}
+	printPhaseChange("Done retrying transfer errors of repo '" + repoKey + "'.")
+	return nil
+}
+
+// Count the errors in the errors files of the repository, which are not included in previousErrorsFiles.
+func countNewErrors(repoKey string, previousErrorsFiles []string) (count int, err error) {
+	errorsFiles, err := getErrorsFiles([]string{repoKey}, true)
+	if err != nil {
+		return 0, err
+	}
+	for _, errorsFile := range errorsFiles {
+		if slices.Contains(previousErrorsFiles, errorsFile) {
+			continue
+		}
+		failedFiles, err := readErrorFile(errorsFile)
+		if err != nil {
+			return 0, err
+		}
+		count += len(failedFiles.Errors)
+	}
+	return count, nil
+}
+
+// Rewrite the errors files with the errors that were not retried. Errors files left with no errors are deleted.
+// errorsFiles - The errors files, mapped to the errors in the file that were not retried
+func removeRetriedErrors(errorsFiles map[string][]ExtendedFileUploadStatusResponse) error {
+	for errorsFile, remaining := range errorsFiles {
+		if len(remaining) == 0 {
+			if err := os.Remove(errorsFile); err != nil {
+				return errorutils.CheckError(err)
+			}
+			continue
+		}
+		content, err := json.Marshal(FilesErrors{Errors: remaining})
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if err = os.WriteFile(errorsFile, content, 0600); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+// Cancel the context and stop the transfer in Artifactory when the command is interrupted.
+// Returns a cleanup function.
+func (tec *TransferErrorsCommand) handleStop(srcUpService *srcUserPluginService) func() {
+	finishStop := make(chan bool)
+	stopSignal := make(chan os.Signal, 1)
+	signal.Notify(stopSignal, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer close(finishStop)
+		if <-stopSignal == nil {
+			return
+		}
+		tec.cancelFunc()
+		log.Info("Gracefully stopping transfer errors retry...")
+		if err := stopTransferInArtifactory(tec.sourceServerDetails, srcUpService); err != nil {
+			log.Error(err)
+		}
+	}()
+	return func() {
+		signal.Stop(stopSignal)
+		close(stopSignal)
+		if tec.context.Err() != nil {
+			<-finishStop
+		}
+	}
+}
+
+func (tes *TransferErrorsSummary) print(format coreutils.OutputFormat) error {
+	switch format {
+	case coreutils.JsonFormat:
+		content, err := json.MarshalIndent(tes, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(string(content))
+		return nil
+	case coreutils.TableFormat, "":
+		var rows []transferErrorsGroupRow
+		for _, group := range tes.Groups {
+			rows = append(rows, transferErrorsGroupRow{
+				Repo:       group.Repo,
+				StatusCode: strconv.Itoa(group.StatusCode),
+				Reason:     group.Reason,
+				Retryable:  strconv.FormatBool(group.Retryable),
+				Count:      strconv.Itoa(group.Count),
+			})
+		}
+		if err := coreutils.PrintTable(rows, "Transfer Errors", "No transfer errors were found", false); err != nil {
+			return err
+		}
+		log.Output("Retryable errors: " + strconv.Itoa(tes.TotalRetryable) + ", skipped errors: " + strconv.Itoa(tes.TotalSkipped))
+		return nil
+	default:
+		return errorutils.CheckErrorf("unsupported transfer errors format: '%s'", format)
+	}
+}
@@ -0,0 +1,116 @@
+package transferfiles
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/transferfiles/api"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchStatusCode(t *testing.T) {
+	assert.True(t, matchStatusCode(nil, 404))
+	assert.True(t, matchStatusCode([]string{"5xx"}, 502))
+	assert.True(t, matchStatusCode([]string{"404", "5XX"}, 404))
+	assert.False(t, matchStatusCode([]string{"5xx"}, 404))
+	assert.False(t, matchStatusCode([]string{"40"}, 404))
+}
+
+func TestTransferErrorsSummaryAndRetryCollection(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+	assert.NoError(t, initTransferErrorsDir())
+
+	retryableDir, err := coreutils.GetJfrogTransferRetryableDir()
+	assert.NoError(t, err)
+	skippedDir, err := coreutils.GetJfrogTransferSkippedDir()
+	assert.NoError(t, err)
+	repo1File := filepath.Join(retryableDir, getErrorsFileName("repo1", api.FullTransferPhase, "1665000000000", 0))
+	writeErrorsFile(t, repo1File,
+		createTestError("repo1", "a", 500, "Internal Server Error"),
+		createTestError("repo1", "b", 503, "Service Unavailable"),
+		createTestError("repo1", "c", 404, "Not Found"))
+	repo2File := filepath.Join(retryableDir, getErrorsFileName("repo2", api.FilesDiffPhase, "1665000000000", 0))
+	writeErrorsFile(t, repo2File, createTestError("repo2", "d", 500, "Internal Server Error"))
+	writeErrorsFile(t, filepath.Join(skippedDir, getErrorsFileName("repo1", api.FullTransferPhase, "1665000000000", 0)),
+		createTestError("repo1", "e", 0, "Properties too large"))
+
+	// Summary of all errors
+	summary, err := NewTransferErrorsCommand(nil, nil).createErrorsSummary()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, summary.TotalRetryable)
+	assert.Equal(t, 1, summary.TotalSkipped)
+	assert.Len(t, summary.Groups, 5)
+	assert.Equal(t, "repo1", summary.Groups[0].Repo)
+	assert.Equal(t, "repo2", summary.Groups[4].Repo)
+
+	// Collect the 5xx errors of repo1 only
+	command := NewTransferErrorsCommand(nil, nil).SetIncludeReposPatterns([]string{"repo1"}).SetStatusCodes([]string{"5xx"})
+	summary, err = command.createErrorsSummary()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.TotalRetryable)
+	assert.Zero(t, summary.TotalSkipped)
+
+	errorsToRetry, err := command.collectErrorsToRetry()
+	assert.NoError(t, err)
+	assert.Len(t, errorsToRetry, 1)
+	assert.Len(t, errorsToRetry["repo1"].errors, 2)
+	assert.Len(t, errorsToRetry["repo1"].errorsFiles[repo1File], 1)
+
+	// The errors that were not retried are kept in the errors file
+	assert.NoError(t, removeRetriedErrors(errorsToRetry["repo1"].errorsFiles))
+	failedFiles, err := readErrorFile(repo1File)
+	assert.NoError(t, err)
+	assert.Len(t, failedFiles.Errors, 1)
+	assert.Equal(t, 404, failedFiles.Errors[0].StatusCode)
+
+	// Errors files with no remaining errors are deleted
+	errorsToRetry, err = NewTransferErrorsCommand(nil, nil).SetIncludeReposPatterns([]string{"repo2"}).collectErrorsToRetry()
+	assert.NoError(t, err)
+	assert.NoError(t, removeRetriedErrors(errorsToRetry["repo2"].errorsFiles))
+	assert.NoFileExists(t, repo2File)
+}
+
+func TestCountNewErrors(t *testing.T) {
+	cleanUpJfrogHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer cleanUpJfrogHome()
+	assert.NoError(t, initTransferErrorsDir())
+
+	retryableDir, err := coreutils.GetJfrogTransferRetryableDir()
+	assert.NoError(t, err)
+	previousFile := filepath.Join(retryableDir, getErrorsFileName("repo1", api.FullTransferPhase, "1665000000000", 0))
+	writeErrorsFile(t, previousFile, createTestError("repo1", "a", 500, "Internal Server Error"))
+	previousErrorsFiles, err := getErrorsFiles([]string{"repo1"}, true)
+	assert.NoError(t, err)
+
+	// Only the errors written after the previous errors files were listed are counted, and only of the given repository
+	writeErrorsFile(t, filepath.Join(retryableDir, getErrorsFileName("repo1", api.ErrorsPhase, "1666000000000", 0)),
+		createTestError("repo1", "a", 502, "Bad Gateway"),
+		createTestError("repo1", "b", 503, "Service Unavailable"))
+	writeErrorsFile(t, filepath.Join(retryableDir, getErrorsFileName("repo2", api.ErrorsPhase, "1666000000000", 0)),
+		createTestError("repo2", "c", 500, "Internal Server Error"))
+	count, err := countNewErrors("repo1", previousErrorsFiles)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func createTestError(repoKey, name string, statusCode int, reason string) ExtendedFileUploadStatusResponse {
+	return ExtendedFileUploadStatusResponse{FileUploadStatusResponse: api.FileUploadStatusResponse{
+		FileRepresentation: api.FileRepresentation{Repo: repoKey, Path: "path", Name: name},
+		Status:             api.Fail,
+		StatusCode:         statusCode,
+		Reason:             reason,
+	}}
+}
+
+func writeErrorsFile(t *testing.T, path string, errors ...ExtendedFileUploadStatusResponse) {
+	content, err := json.Marshal(FilesErrors{Errors: errors})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, content, 0600))
+}
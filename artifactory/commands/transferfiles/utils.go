@@ -182,6 +182,9 @@ func handleFilesOfCompletedChunk(chunkFiles []api.FileUploadStatusResponse, erro
 // Uploads chunk when there is room in queue.
 // This is a blocking method.
 func uploadChunkWhenPossible(phaseBase *phaseBase, chunk api.UploadChunk, uploadTokensChan chan UploadedChunk, errorsChannelMng *ErrorsChannelMng) (stopped bool) {
+	if waitForRateLimit(phaseBase, chunk, errorsChannelMng) {
+		return true
+	}
 	for {
 		if ShouldStop(phaseBase, nil, errorsChannelMng) {
 			return true
@@ -250,8 +253,9 @@ func GetThreads() int {
 	return curThreads
 }
 
-// Periodically reads settings file and updates the number of threads.
+// Periodically reads settings file and updates the number of threads and the rate limits.
 // Number of threads in the settings files is expected to change by running a separate command.
+// Outside the allowed time windows the number of threads is set to zero, which pauses the transfer until the next time window starts.
 // The new number of threads should be almost immediately (checked every waitTimeBetweenThreadsUpdateSeconds) reflected on
 // the CLI side (by updating the producer consumer if used and the local variable) and as a result reflected on the Artifactory User Plugin side.
 func periodicallyUpdateThreads(pcWrapper *producerConsumerWrapper, doneChan chan bool, buildInfoRepo bool) {
@@ -272,14 +276,23 @@ func updateThreads(pcWrapper *producerConsumerWrapper, buildInfoRepo bool) error
 	if err != nil || settings == nil {
 		return err
 	}
-	calculatedNumberOfThreads := settings.CalcNumberOfThreads(buildInfoRepo)
+	rateLimiter.setLimits(settings.MaxBytesPerSec, settings.MaxFilesPerSec)
+	calculatedNumberOfThreads := settings.CalcCurrentNumberOfThreads(buildInfoRepo, time.Now())
 	if curThreads != calculatedNumberOfThreads {
+		previousThreads := curThreads
 		curThreads = calculatedNumberOfThreads
 		if pcWrapper != nil {
 			updateProducerConsumerMaxParallel(pcWrapper.chunkBuilderProducerConsumer, calculatedNumberOfThreads)
 			updateProducerConsumerMaxParallel(pcWrapper.chunkUploaderProducerConsumer, calculatedNumberOfThreads)
 		}
-		log.Info("Number of threads have been updated to " + strconv.Itoa(curThreads))
+		switch {
+		case curThreads == 0:
+			log.Info("The transfer is outside of the allowed time windows. Pausing until the next time window starts...")
+		case previousThreads == 0:
+			log.Info("The transfer is inside an allowed time window. Resuming with " + strconv.Itoa(curThreads) + " threads...")
+		default:
+			log.Info("Number of threads have been updated to " + strconv.Itoa(curThreads))
+		}
 	}
 	return nil
 }
@@ -313,7 +326,7 @@ func uploadByChunks(files []api.FileRepresentation, uploadTokensChan chan Upload
 	}
 
 	for _, item := range files {
-		file := api.FileRepresentation{Repo: item.Repo, Path: item.Path, Name: item.Name}
+		file := api.FileRepresentation{Repo: item.Repo, Path: item.Path, Name: item.Name, Size: item.Size}
 		var delayed bool
 		delayed, shouldStop = delayHelper.delayUploadIfNecessary(base, file)
 		if shouldStop {
@@ -0,0 +1,319 @@
+package transferfiles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	missingInTargetIssue = "Missing in target"
+	extraInTargetIssue   = "Extra in target"
+	mismatchIssue        = "Mismatch"
+)
+
+// TransferVerifyCommand compares the files in the source and target Artifactory servers after a transfer,
+// and reports the files that are missing in the target, the files that exist only in the target,
+// and the files with a different size or checksum.
+type TransferVerifyCommand struct {
+	context              context.Context
+	sourceServerDetails  *config.ServerDetails
+	targetServerDetails  *config.ServerDetails
+	includeReposPatterns []string
+	excludeReposPatterns []string
+	format               coreutils.OutputFormat
+}
+
+func NewTransferVerifyCommand(sourceServer, targetServer *config.ServerDetails) *TransferVerifyCommand {
+	return &TransferVerifyCommand{
+		context:             context.Background(),
+		sourceServerDetails: sourceServer,
+		targetServerDetails: targetServer,
+	}
+}
+
+func (tvc *TransferVerifyCommand) CommandName() string {
+	return "rt_transfer_verify"
+}
+
+func (tvc *TransferVerifyCommand) ServerDetails() (*config.ServerDetails, error) {
+	return tvc.sourceServerDetails, nil
+}
+
+func (tvc *TransferVerifyCommand) SetIncludeReposPatterns(includeReposPatterns []string) *TransferVerifyCommand {
+	tvc.includeReposPatterns = includeReposPatterns
+	return tvc
+}
+
+func (tvc *TransferVerifyCommand) SetExcludeReposPatterns(excludeReposPatterns []string) *TransferVerifyCommand {
+	tvc.excludeReposPatterns = excludeReposPatterns
+	return tvc
+}
+
+func (tvc *TransferVerifyCommand) SetFormat(format coreutils.OutputFormat) *TransferVerifyCommand {
+	tvc.format = format
+	return tvc
+}
+
+// TransferVerificationReport lists the differences between the source and the target, by repository.
+type TransferVerificationReport struct {
+	Repositories    []RepoVerificationResult `json:"repositories"`
+	TotalMissing    int                      `json:"total_missing"`
+	TotalExtra      int                      `json:"total_extra"`
+	TotalMismatched int                      `json:"total_mismatched"`
+}
+
+type RepoVerificationResult struct {
+	Name           string `json:"name"`
+	ExistsInTarget bool   `json:"exists_in_target"`
+	SourceFiles    int    `json:"source_files"`
+	TargetFiles    int    `json:"target_files"`
+	// Paths of files that exist in the source, but not in the target
+	Missing []string `json:"missing,omitempty"`
+	// Paths of files that exist in the target, but not in the source
+	Extra      []string           `json:"extra,omitempty"`
+	Mismatched []ArtifactMismatch `json:"mismatched,omitempty"`
+}
+
+type ArtifactMismatch struct {
+	Path   string           `json:"path"`
+	Source ArtifactChecksum `json:"source"`
+	Target ArtifactChecksum `json:"target"`
+}
+
+type ArtifactChecksum struct {
+	Size   int64  `json:"size"`
+	Sha1   string `json:"sha1"`
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+type repoVerificationRow struct {
+	Name        string `col-name:"Repository"`
+	SourceFiles string `col-name:"Source\nFiles"`
+	TargetFiles string `col-name:"Target\nFiles"`
+	Missing     string `col-name:"Missing"`
+	Extra       string `col-name:"Extra"`
+	Mismatched  string `col-name:"Mismatched"`
+}
+
+type verificationIssueRow struct {
+	Repo   string `col-name:"Repository"`
+	Path   string `col-name:"Path"`
+	Issue  string `col-name:"Issue"`
+	Source string `col-name:"Source"`
+	Target string `col-name:"Target"`
+}
+
+// Run the verification and print the report.
+// If any difference is found, a CliError is returned, so that the command fails in CI.
+func (tvc *TransferVerifyCommand) Run() error {
+	report, err := tvc.createVerificationReport()
+	if err != nil {
+		return err
+	}
+	if err = report.print(tvc.format); err != nil {
+		return err
+	}
+	if report.hasDifferences() {
+		return coreutils.CliError{ExitCode: coreutils.ExitCodeError, ErrorMsg: fmt.Sprintf(
+			"transfer verification failed: %d files are missing in the target, %d files exist only in the target and %d files have a different size or checksum",
+			report.TotalMissing, report.TotalExtra, report.TotalMismatched)}
+	}
+	log.Info("Transfer verification passed - the target matches the source.")
+	return nil
+}
+
+func (tvc *TransferVerifyCommand) createVerificationReport() (*TransferVerificationReport, error) {
+	sourceStorageInfoManager, err := utils.NewStorageInfoManager(tvc.context, tvc.sourceServerDetails)
+	if err != nil {
+		return nil, err
+	}
+	targetStorageInfoManager, err := utils.NewStorageInfoManager(tvc.context, tvc.targetServerDetails)
+	if err != nil {
+		return nil, err
+	}
+	sourceLocalRepos, sourceBuildInfoRepos, err := getAllLocalRepos(tvc.context, tvc.sourceServerDetails, sourceStorageInfoManager, tvc.includeReposPatterns, tvc.excludeReposPatterns)
+	if err != nil {
+		return nil, err
+	}
+	targetLocalRepos, targetBuildInfoRepos, err := getAllLocalRepos(tvc.context, tvc.targetServerDetails, targetStorageInfoManager, tvc.includeReposPatterns, tvc.excludeReposPatterns)
+	if err != nil {
+		return nil, err
+	}
+	targetRepos := append(targetLocalRepos, targetBuildInfoRepos...)
+
+	report := &TransferVerificationReport{}
+	for _, repoKey := range append(sourceLocalRepos, sourceBuildInfoRepos...) {
+		log.Info(fmt.Sprintf("Verifying repository '%s'...", repoKey))
+		sourceFiles, err := tvc.getRepoFiles(tvc.sourceServerDetails, repoKey)
+		if err != nil {
+			return nil, err
+		}
+		existsInTarget := slices.Contains(targetRepos, repoKey)
+		var targetFiles map[string]ArtifactChecksum
+		if existsInTarget {
+			if targetFiles, err = tvc.getRepoFiles(tvc.targetServerDetails, repoKey); err != nil {
+				return nil, err
+			}
+		}
+		report.addRepository(compareRepoFiles(repoKey, existsInTarget, sourceFiles, targetFiles))
+	}
+	return report, nil
+}
+
+// Return the files of the repository, mapped by their path relative to the repository.
+func (tvc *TransferVerifyCommand) getRepoFiles(serverDetails *config.ServerDetails, repoKey string) (map[string]ArtifactChecksum, error) {
+	files := make(map[string]ArtifactChecksum)
+	for paginationI := 0; ; paginationI++ {
+		result, err := runAql(tvc.context, serverDetails, generateVerifyRepoAqlQuery(repoKey, paginationI))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Results {
+			files[getItemRelativePath(item)] = ArtifactChecksum{Size: item.Size, Sha1: item.Actual_Sha1, Sha256: item.Sha256}
+		}
+		if len(result.Results) < AqlPaginationLimit {
+			return files, nil
+		}
+	}
+}
+
+func generateVerifyRepoAqlQuery(repoKey string, paginationOffset int) string {
+	query := fmt.Sprintf(`items.find({"type":"file","repo":"%s","path":{"$match":"*"},"name":{"$match":"*"}})`, repoKey)
+	query += `.include("repo","path","name","size","actual_sha1","sha256")`
+	query += fmt.Sprintf(`.sort({"$asc":["path","name"]}).offset(%d).limit(%d)`, paginationOffset*AqlPaginationLimit, AqlPaginationLimit)
+	return query
+}
+
+func getItemRelativePath(item servicesUtils.ResultItem) string {
+	if item.Path == "." {
+		return item.Name
+	}
+	return path.Join(item.Path, item.Name)
+}
+
+// Compare the files of a repository in the source and the target.
+// The results are sorted by path, to keep the report stable between runs.
+func compareRepoFiles(repoKey string, existsInTarget bool, sourceFiles, targetFiles map[string]ArtifactChecksum) RepoVerificationResult {
+	result := RepoVerificationResult{Name: repoKey, ExistsInTarget: existsInTarget, SourceFiles: len(sourceFiles), TargetFiles: len(targetFiles)}
+	for filePath, sourceFile := range sourceFiles {
+		targetFile, exists := targetFiles[filePath]
+		if !exists {
+			result.Missing = append(result.Missing, filePath)
+			continue
+		}
+		if !sourceFile.matches(targetFile) {
+			result.Mismatched = append(result.Mismatched, ArtifactMismatch{Path: filePath, Source: sourceFile, Target: targetFile})
+		}
+	}
+	for filePath := range targetFiles {
+		if _, exists := sourceFiles[filePath]; !exists {
+			result.Extra = append(result.Extra, filePath)
+		}
+	}
+	sort.Strings(result.Missing)
+	sort.Strings(result.Extra)
+	sort.Slice(result.Mismatched, func(i, j int) bool {
+		return result.Mismatched[i].Path < result.Mismatched[j].Path
+	})
+	return result
+}
+
+// The SHA-256 checksum may be missing for files that were deployed to old Artifactory versions.
+// Therefore, it is compared only if it exists in both servers.
+func (ac ArtifactChecksum) matches(other ArtifactChecksum) bool {
+	if ac.Size != other.Size || ac.Sha1 != other.Sha1 {
+		return false
+	}
+	return ac.Sha256 == "" || other.Sha256 == "" || ac.Sha256 == other.Sha256
+}
+
+func (ac ArtifactChecksum) String() string {
+	return fmt.Sprintf("%s\nsha1: %s\nsha256: %s", sizeToString(ac.Size), ac.Sha1, ac.Sha256)
+}
+
+func (tvr *TransferVerificationReport) addRepository(result RepoVerificationResult) {
+	tvr.Repositories = append(tvr.Repositories, result)
+	tvr.TotalMissing += len(result.Missing)
+	tvr.TotalExtra += len(result.Extra)
+	tvr.TotalMismatched += len(result.Mismatched)
+}
+
+func (tvr *TransferVerificationReport) hasDifferences() bool {
+	return tvr.TotalMissing+tvr.TotalExtra+tvr.TotalMismatched > 0
+}
+
+func (tvr *TransferVerificationReport) print(format coreutils.OutputFormat) error {
+	switch format {
+	case coreutils.JsonFormat:
+		content, err := json.MarshalIndent(tvr, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(string(content))
+		return nil
+	case coreutils.TableFormat, "":
+		return tvr.printTable()
+	default:
+		return errorutils.CheckErrorf("unsupported transfer verification format: '%s'", format)
+	}
+}
+
+func (tvr *TransferVerificationReport) printTable() error {
+	var repoRows []repoVerificationRow
+	var issueRows []verificationIssueRow
+	for _, repo := range tvr.Repositories {
+		repoRow := repoVerificationRow{
+			Name:        repo.Name,
+			SourceFiles: strconv.Itoa(repo.SourceFiles),
+			TargetFiles: strconv.Itoa(repo.TargetFiles),
+			Missing:     strconv.Itoa(len(repo.Missing)),
+			Extra:       strconv.Itoa(len(repo.Extra)),
+			Mismatched:  strconv.Itoa(len(repo.Mismatched)),
+		}
+		if !repo.ExistsInTarget {
+			repoRow.TargetFiles = "Missing repository"
+		}
+		repoRows = append(repoRows, repoRow)
+		for _, filePath := range repo.Missing {
+			issueRows = append(issueRows, verificationIssueRow{Repo: repo.Name, Path: filePath, Issue: missingInTargetIssue, Target: "-"})
+		}
+		for _, filePath := range repo.Extra {
+			issueRows = append(issueRows, verificationIssueRow{Repo: repo.Name, Path: filePath, Issue: extraInTargetIssue, Source: "-"})
+		}
+		for _, mismatch := range repo.Mismatched {
+			issueRows = append(issueRows, verificationIssueRow{Repo: repo.Name, Path: mismatch.Path, Issue: mismatchIssue,
+				Source: mismatch.Source.String(), Target: mismatch.Target.String()})
+		}
+	}
+	if err := coreutils.PrintTable(repoRows, "Transfer Verification", "No repositories to verify", false); err != nil {
+		return err
+	}
+	if len(issueRows) > 0 {
+		if err := coreutils.PrintTable(issueRows, "Differences", "", false); err != nil {
+			return err
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString("\n")
+	addTitle(&output, "Overall Verification")
+	addString(&output, "📦", "Repositories", strconv.Itoa(len(tvr.Repositories)), 2)
+	addString(&output, "❌", "Missing in target", strconv.Itoa(tvr.TotalMissing), 2)
+	addString(&output, "➕", "Extra in target", strconv.Itoa(tvr.TotalExtra), 2)
+	addString(&output, "⚠️", "Mismatched", strconv.Itoa(tvr.TotalMismatched), 2)
+	log.Output(output.String())
+	return nil
+}
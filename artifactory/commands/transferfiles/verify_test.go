@@ -0,0 +1,85 @@
+package transferfiles
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareRepoFiles(t *testing.T) {
+	sourceFiles := map[string]ArtifactChecksum{
+		"a/file1":   {Size: 1, Sha1: "sha1-1", Sha256: "sha256-1"},
+		"a/file2":   {Size: 2, Sha1: "sha1-2", Sha256: "sha256-2"},
+		"b/file3":   {Size: 3, Sha1: "sha1-3", Sha256: "sha256-3"},
+		"b/file4":   {Size: 4, Sha1: "sha1-4"},
+		"c/missing": {Size: 5, Sha1: "sha1-5"},
+	}
+	targetFiles := map[string]ArtifactChecksum{
+		"a/file1": {Size: 1, Sha1: "sha1-1", Sha256: "sha256-1"},
+		// Different size
+		"a/file2": {Size: 20, Sha1: "sha1-2", Sha256: "sha256-2"},
+		// Different SHA-256
+		"b/file3": {Size: 3, Sha1: "sha1-3", Sha256: "other"},
+		// SHA-256 is missing in the source only, so it is not compared
+		"b/file4": {Size: 4, Sha1: "sha1-4", Sha256: "sha256-4"},
+		"d/extra": {Size: 6, Sha1: "sha1-6"},
+	}
+
+	result := compareRepoFiles(repo1Key, true, sourceFiles, targetFiles)
+	assert.Equal(t, repo1Key, result.Name)
+	assert.Equal(t, 5, result.SourceFiles)
+	assert.Equal(t, 5, result.TargetFiles)
+	assert.Equal(t, []string{"c/missing"}, result.Missing)
+	assert.Equal(t, []string{"d/extra"}, result.Extra)
+	if assert.Len(t, result.Mismatched, 2) {
+		assert.Equal(t, "a/file2", result.Mismatched[0].Path)
+		assert.Equal(t, int64(20), result.Mismatched[0].Target.Size)
+		assert.Equal(t, "b/file3", result.Mismatched[1].Path)
+	}
+
+	// Repository missing in the target
+	result = compareRepoFiles(repo1Key, false, sourceFiles, nil)
+	assert.False(t, result.ExistsInTarget)
+	assert.Len(t, result.Missing, 5)
+	assert.Empty(t, result.Extra)
+	assert.Empty(t, result.Mismatched)
+}
+
+func TestGetItemRelativePath(t *testing.T) {
+	assert.Equal(t, "file", getItemRelativePath(servicesUtils.ResultItem{Path: ".", Name: "file"}))
+	assert.Equal(t, "a/b/file", getItemRelativePath(servicesUtils.ResultItem{Path: "a/b", Name: "file"}))
+}
+
+func TestGenerateVerifyRepoAqlQuery(t *testing.T) {
+	expected := `items.find({"type":"file","repo":"repo1","path":{"$match":"*"},"name":{"$match":"*"}})` +
+		`.include("repo","path","name","size","actual_sha1","sha256")` +
+		`.sort({"$asc":["path","name"]}).offset(20000).limit(10000)`
+	assert.Equal(t, expected, generateVerifyRepoAqlQuery(repo1Key, 2))
+}
+
+func TestTransferVerificationReport(t *testing.T) {
+	report := &TransferVerificationReport{}
+	report.addRepository(RepoVerificationResult{Name: "repo-ok", ExistsInTarget: true, SourceFiles: 1, TargetFiles: 1})
+	assert.False(t, report.hasDifferences())
+
+	report.addRepository(RepoVerificationResult{Name: repo1Key, ExistsInTarget: true, Missing: []string{"a"}, Extra: []string{"b", "c"},
+		Mismatched: []ArtifactMismatch{{Path: "d"}}})
+	assert.True(t, report.hasDifferences())
+	assert.Equal(t, 1, report.TotalMissing)
+	assert.Equal(t, 2, report.TotalExtra)
+	assert.Equal(t, 1, report.TotalMismatched)
+
+	buffer, _, previousLog := tests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+	assert.NoError(t, report.print(coreutils.JsonFormat))
+	var printedReport TransferVerificationReport
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &printedReport))
+	assert.Equal(t, *report, printedReport)
+
+	assert.Error(t, report.print("xml"))
+}
@@ -10,6 +10,8 @@ type GroupCreateCommand struct {
 	rtDetails       *config.ServerDetails
 	name            string
 	replaceIfExists bool
+	// Optional details of the group, such as its description and members.
+	details *services.Group
 }
 
 func NewGroupCreateCommand() *GroupCreateCommand {
@@ -39,6 +41,11 @@ func (gcc *GroupCreateCommand) SetReplaceIfExists(replaceIfExists bool) *GroupCr
 	return gcc
 }
 
+func (gcc *GroupCreateCommand) SetDetails(details *services.Group) *GroupCreateCommand {
+	gcc.details = details
+	return gcc
+}
+
 func (gcc *GroupCreateCommand) ReplaceIfExists() bool {
 	return gcc.replaceIfExists
 }
@@ -53,6 +60,9 @@ func (gcc *GroupCreateCommand) Run() error {
 		return err
 	}
 	group := new(services.Group)
+	if gcc.details != nil {
+		*group = *gcc.details
+	}
 	group.Name = gcc.Name()
 	params := new(services.GroupParams)
 	params.GroupDetails = *group
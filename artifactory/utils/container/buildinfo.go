@@ -317,7 +317,7 @@ func (builder *buildInfoBuilder) createBuildInfo(commandType CommandType, manife
 			return nil, err
 		}
 	case Push:
-		artifacts, dependencies, builder.imageLayers, err = builder.createPushBuildProperties(manifest, candidateLayers)
+		artifacts, dependencies, builder.imageLayers, err = builder.createImageBuildProperties(builder.imageSha2, manifest, candidateLayers)
 		if err != nil {
 			return nil, err
 		}
@@ -339,25 +339,10 @@ func (builder *buildInfoBuilder) createBuildInfo(commandType CommandType, manife
 
 // Create the image's build info from list.manifest.json.
 func (builder *buildInfoBuilder) createMultiPlatformBuildInfo(fatManifest *FatManifest, searchRultFatManifest *utils.ResultItem, candidateimages map[string][]*utils.ResultItem, module string) (*buildinfo.BuildInfo, error) {
-	imageProperties := map[string]string{
-		"docker.image.tag": builder.image.Name(),
-	}
-	if module == "" {
-		imageName, err := builder.image.GetImageShortNameWithTag()
-		if err != nil {
-			return nil, err
-		}
-		module = imageName
+	buildInfo, module, err := builder.createFatManifestBuildInfo(searchRultFatManifest, module)
+	if err != nil {
+		return nil, err
 	}
-	// Add layers.
-	builder.imageLayers = append(builder.imageLayers, *searchRultFatManifest)
-	// Create fat-manifest module
-	buildInfo := &buildinfo.BuildInfo{Modules: []buildinfo.Module{{
-		Id:         module,
-		Type:       buildinfo.Docker,
-		Properties: imageProperties,
-		Artifacts:  []buildinfo.Artifact{getFatManifestArtifact(searchRultFatManifest)},
-	}}}
 	// Create all image arch modules
 	for _, manifest := range fatManifest.Manifests {
 		image := candidateimages[manifest.Digest]
@@ -379,16 +364,136 @@ func (builder *buildInfoBuilder) createMultiPlatformBuildInfo(fatManifest *FatMa
 	return buildInfo, setBuildProperties(builder.buildName, builder.buildNumber, builder.project, builder.imageLayers, builder.serviceManager)
 }
 
-func (builder *buildInfoBuilder) createPushBuildProperties(imageManifest *manifest, candidateLayers map[string]*utils.ResultItem) (artifacts []buildinfo.Artifact, dependencies []buildinfo.Dependency, imageLayers []utils.ResultItem, err error) {
+// Create the build info of a manifest list pushed by a local agent, with a module for each of its platform images.
+// Unlike the modules created by 'build-docker-create', the modules have the dependencies and the image ID of their platform image,
+// and their IDs include the variant of the platform, such as linux/arm64/v8/hello:1.0.
+func (builder *buildInfoBuilder) createPushedMultiPlatformBuildInfo(fatManifest *FatManifest, searchRultFatManifest *utils.ResultItem, candidateimages map[string][]*utils.ResultItem, module string) (*buildinfo.BuildInfo, error) {
+	buildInfo, module, err := builder.createFatManifestBuildInfo(searchRultFatManifest, module)
+	if err != nil {
+		return nil, err
+	}
+	// Layers which are shared by several platforms may be stored in the folder of one of them only.
+	sharedLayers := getSharedLayers(fatManifest, candidateimages)
+	for _, manifestDetails := range fatManifest.Manifests {
+		// Attestation manifests, such as those created by buildx, have an unknown platform.
+		if manifestDetails.Platform.Os == "unknown" {
+			continue
+		}
+		image, ok := candidateimages[manifestDetails.Digest]
+		if !ok {
+			log.Warn("Couldn't find the " + manifestDetails.Platform.String() + " image " + manifestDetails.Digest + " in Artifactory, and therefore it will not be added to the build-info.")
+			continue
+		}
+		candidateLayers := mergeCandidateLayers(sharedLayers, image)
+		imageManifest, err := getManifest(candidateLayers, builder.serviceManager, builder.repositoryDetails.key)
+		if err != nil {
+			return nil, err
+		}
+		if imageManifest == nil {
+			return nil, errorutils.CheckErrorf("couldn't find the manifest.json of the " + manifestDetails.Platform.String() + " image " + manifestDetails.Digest + " in Artifactory")
+		}
+		// Manifest may hold 'empty layers'. As a result, promotion will fail to promote the same layer more than once.
+		imageManifest.Layers = removeDuplicateLayers(imageManifest.Layers)
+		artifacts, dependencies, imageLayers, err := builder.createImageBuildProperties(imageManifest.Config.Digest, imageManifest, candidateLayers)
+		if err != nil {
+			return nil, err
+		}
+		builder.imageLayers = append(builder.imageLayers, imageLayers...)
+		buildInfo.Modules = append(buildInfo.Modules, buildinfo.Module{
+			Id:   manifestDetails.Platform.String() + "/" + module,
+			Type: buildinfo.Docker,
+			Properties: map[string]string{
+				"docker.image.id":  imageManifest.Config.Digest,
+				"docker.image.tag": builder.image.Name(),
+			},
+			Artifacts:    artifacts,
+			Dependencies: dependencies,
+		})
+	}
+	// Shared layers are tagged once.
+	builder.imageLayers = removeDuplicateResultItems(builder.imageLayers)
+	if builder.skipTaggingLayers {
+		return buildInfo, nil
+	}
+	return buildInfo, setBuildProperties(builder.buildName, builder.buildNumber, builder.project, builder.imageLayers, builder.serviceManager)
+}
+
+// Create the build info with the module of the fat-manifest. Returns the build info and the module name.
+func (builder *buildInfoBuilder) createFatManifestBuildInfo(searchRultFatManifest *utils.ResultItem, module string) (*buildinfo.BuildInfo, string, error) {
+	imageProperties := map[string]string{
+		"docker.image.tag": builder.image.Name(),
+	}
+	if module == "" {
+		imageName, err := builder.image.GetImageShortNameWithTag()
+		if err != nil {
+			return nil, "", err
+		}
+		module = imageName
+	}
+	// Add layers.
+	builder.imageLayers = append(builder.imageLayers, *searchRultFatManifest)
+	// Create fat-manifest module
+	return &buildinfo.BuildInfo{Modules: []buildinfo.Module{{
+		Id:         module,
+		Type:       buildinfo.Docker,
+		Properties: imageProperties,
+		Artifacts:  []buildinfo.Artifact{getFatManifestArtifact(searchRultFatManifest)},
+	}}}, module, nil
+}
+
+// Returns a map of: layer-file-name -> layer-search-result, of the layers of all the platform images in the fat manifest.
+func getSharedLayers(fatManifest *FatManifest, candidateimages map[string][]*utils.ResultItem) map[string]*utils.ResultItem {
+	sharedLayers := make(map[string]*utils.ResultItem)
+	for _, manifestDetails := range fatManifest.Manifests {
+		for _, layer := range candidateimages[manifestDetails.Digest] {
+			if _, exists := sharedLayers[layer.Name]; !exists && layer.Name != "manifest.json" {
+				sharedLayers[layer.Name] = layer
+			}
+		}
+	}
+	return sharedLayers
+}
+
+// Returns the candidate layers of a platform image. Layers in the image's folder take precedence over the shared layers.
+func mergeCandidateLayers(sharedLayers map[string]*utils.ResultItem, image []*utils.ResultItem) map[string]*utils.ResultItem {
+	candidateLayers := make(map[string]*utils.ResultItem, len(sharedLayers)+len(image))
+	for name, layer := range sharedLayers {
+		candidateLayers[name] = layer
+	}
+	for _, layer := range image {
+		candidateLayers[layer.Name] = layer
+	}
+	return candidateLayers
+}
+
+func removeDuplicateResultItems(items []utils.ResultItem) []utils.ResultItem {
+	res := items[:0]
+	encountered := map[string]bool{}
+	for _, item := range items {
+		itemPath := item.GetItemRelativePath()
+		if !encountered[itemPath] {
+			res = append(res, item)
+			encountered[itemPath] = true
+		}
+	}
+	return res
+}
+
+// Create the artifacts and dependencies of a pushed image, identified by the digest of its config layer.
+func (builder *buildInfoBuilder) createImageBuildProperties(configDigest string, imageManifest *manifest, candidateLayers map[string]*utils.ResultItem) (artifacts []buildinfo.Artifact, dependencies []buildinfo.Dependency, imageLayers []utils.ResultItem, err error) {
+	configItem, ok := candidateLayers[digestToLayer(configDigest)]
+	if !ok {
+		return nil, nil, nil, errorutils.CheckErrorf("Could not find the config layer: " + digestToLayer(configDigest) + " in Artifactory")
+	}
 	// Add artifacts.
 	artifacts = append(artifacts, getManifestArtifact(candidateLayers["manifest.json"]))
-	artifacts = append(artifacts, candidateLayers[digestToLayer(builder.imageSha2)].ToArtifact())
+	artifacts = append(artifacts, configItem.ToArtifact())
 	// Add layers.
 	imageLayers = append(imageLayers, *candidateLayers["manifest.json"])
-	imageLayers = append(imageLayers, *candidateLayers[digestToLayer(builder.imageSha2)])
+	imageLayers = append(imageLayers, *configItem)
 
 	totalLayers := len(imageManifest.Layers)
-	totalDependencies, err := builder.totalDependencies(candidateLayers[digestToLayer(builder.imageSha2)])
+	totalDependencies, err := builder.totalDependencies(configItem)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,3 +43,43 @@ func TestGetImageWithDigest(t *testing.T) {
 	assert.Equal(t, "my-image-tag", tag.name)
 	assert.Equal(t, "sha256:12345", sha256)
 }
+
+func TestPlatformString(t *testing.T) {
+	assert.Equal(t, "linux/amd64", Platform{Os: "linux", Architecture: "amd64"}.String())
+	assert.Equal(t, "linux/arm64/v8", Platform{Os: "linux", Architecture: "arm64", Variant: "v8"}.String())
+}
+
+func TestMergeCandidateLayers(t *testing.T) {
+	amd64Manifest := &utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__amd64", Name: "manifest.json"}
+	amd64SharedLayer := &utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__amd64", Name: "sha256__shared"}
+	arm64Manifest := &utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__arm64", Name: "manifest.json"}
+	arm64Config := &utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__arm64", Name: "sha256__arm64config"}
+	otherTagLayer := &utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__other", Name: "sha256__other"}
+	fatManifest := &FatManifest{Manifests: []ManifestDetails{
+		{Digest: "sha256:amd64", Platform: Platform{Os: "linux", Architecture: "amd64"}},
+		{Digest: "sha256:arm64", Platform: Platform{Os: "linux", Architecture: "arm64"}},
+	}}
+	candidateImages := map[string][]*utils.ResultItem{
+		"sha256:amd64": {amd64Manifest, amd64SharedLayer},
+		"sha256:arm64": {arm64Manifest, arm64Config},
+		"sha256:other": {otherTagLayer},
+	}
+
+	sharedLayers := getSharedLayers(fatManifest, candidateImages)
+	// Manifests and images which are not in the fat manifest are not shared.
+	assert.Equal(t, map[string]*utils.ResultItem{"sha256__shared": amd64SharedLayer, "sha256__arm64config": arm64Config}, sharedLayers)
+
+	// The arm64 image finds the layer which is stored in the amd64 folder, and keeps its own manifest.
+	candidateLayers := mergeCandidateLayers(sharedLayers, candidateImages["sha256:arm64"])
+	assert.Len(t, candidateLayers, 3)
+	assert.Same(t, arm64Manifest, candidateLayers["manifest.json"])
+	assert.Same(t, amd64SharedLayer, candidateLayers["sha256__shared"])
+	assert.Same(t, arm64Config, candidateLayers["sha256__arm64config"])
+}
+
+func TestRemoveDuplicateResultItems(t *testing.T) {
+	layer := utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__amd64", Name: "sha256__shared"}
+	manifest := utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__amd64", Name: "manifest.json"}
+	otherManifest := utils.ResultItem{Repo: "docker-local", Path: "hello/sha256__arm64", Name: "manifest.json"}
+	assert.Equal(t, []utils.ResultItem{layer, manifest, otherManifest}, removeDuplicateResultItems([]utils.ResultItem{layer, manifest, layer, otherManifest, manifest}))
+}
@@ -27,6 +27,10 @@ const MinSupportedApiVersion string = "1.31"
 const LoginFailureMessage string = "%s login failed for: %s.\n %s image must be in the form: registry-domain/path-in-repository/image-name:version."
 
 func NewManager(containerManagerType ContainerManagerType) ContainerManager {
+	if containerManagerType == Oci {
+		// With no login config, the registry is accessed anonymously.
+		return NewOciManager(nil)
+	}
 	return &containerManager{Type: containerManagerType}
 }
 
@@ -35,10 +39,12 @@ type ContainerManagerType int
 const (
 	DockerClient ContainerManagerType = iota
 	Podman
+	// Daemonless client of the OCI Distribution API
+	Oci
 )
 
 func (cmt ContainerManagerType) String() string {
-	return [...]string{"docker", "podman"}[cmt]
+	return [...]string{"docker", "podman", "oci"}[cmt]
 }
 
 // Container image
@@ -68,7 +74,14 @@ func (containerManager *containerManager) RunNativeCmd(cmdParams []string) error
 func (containerManager *containerManager) Id(image *Image) (string, error) {
 	cmd := &getImageIdCmd{image: image, containerManager: containerManager.Type}
 	content, err := cmd.RunCmd()
-	return content[:strings.Index(content, "\n")], err
+	if err != nil {
+		return "", err
+	}
+	id, _, _ := strings.Cut(content, "\n")
+	if id == "" {
+		return "", errorutils.CheckErrorf("couldn't find the ID of image: " + image.name)
+	}
+	return id, nil
 }
 
 // Return the OS and architecture on which the image runs e.g. (linux, amd64, nil).
@@ -189,16 +202,10 @@ func ContainerManagerLogin(image *Image, config *ContainerManagerLoginConfig, co
 	if err != nil {
 		return err
 	}
-	username := config.ServerDetails.User
-	password := config.ServerDetails.Password
-	// If access-token exists, perform login with it.
 	if config.ServerDetails.AccessToken != "" {
 		log.Debug("Using access-token details in " + containerManager.String() + "-login command.")
-		if username == "" {
-			username = auth.ExtractUsernameFromAccessToken(config.ServerDetails.AccessToken)
-		}
-		password = config.ServerDetails.AccessToken
 	}
+	username, password := getRegistryCredentials(config)
 	// Perform login.
 	cmd := &LoginCmd{DockerRegistry: imageRegistry, Username: username, Password: password, containerManager: containerManager}
 	err = cmd.RunCmd()
@@ -222,6 +229,19 @@ func ContainerManagerLogin(image *Image, config *ContainerManagerLoginConfig, co
 	return nil
 }
 
+// Returns the username and password for the container registry. If access-token exists, it is used as the password.
+func getRegistryCredentials(config *ContainerManagerLoginConfig) (username, password string) {
+	username = config.ServerDetails.User
+	password = config.ServerDetails.Password
+	if config.ServerDetails.AccessToken != "" {
+		if username == "" {
+			username = auth.ExtractUsernameFromAccessToken(config.ServerDetails.AccessToken)
+		}
+		password = config.ServerDetails.AccessToken
+	}
+	return
+}
+
 // Version command
 // Docker-client provides an API for interacting with the Docker daemon. This cmd should be used for docker client only.
 type VersionCmd struct{}
@@ -0,0 +1,535 @@
+package container
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The artifact type of cosign image signatures, stored as OCI referrers.
+	CosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// The artifact type of in-toto attestations, stored as OCI referrers.
+	InTotoArtifactType          = "application/vnd.in-toto+json"
+	SlsaProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	simpleSigningMediaType      = "application/vnd.dev.cosign.simplesigning.v1+json"
+	dsseEnvelopeMediaType       = "application/vnd.dsse.envelope.v1+json"
+	ociEmptyMediaType           = "application/vnd.oci.empty.v1+json"
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	predicateTypeAnnotation     = "in-toto.io/predicate-type"
+	ociCreatedAnnotation        = "org.opencontainers.image.created"
+	cosignSignatureType         = "cosign container image signature"
+	inTotoStatementType         = "https://in-toto.io/Statement/v1"
+	provenanceBuilderId         = "https://github.com/jfrog/jfrog-cli"
+	provenanceBuildType         = "https://github.com/jfrog/jfrog-cli/container-build/v1"
+)
+
+// The config of OCI artifacts, which have no config.
+var ociEmptyConfig = []byte("{}")
+
+// Cosign's simple signing payload, which binds the signature to the image's manifest digest.
+type simpleSigningPayload struct {
+	Critical simpleSigningCritical `json:"critical"`
+	Optional map[string]string     `json:"optional,omitempty"`
+}
+
+type simpleSigningCritical struct {
+	Identity simpleSigningIdentity `json:"identity"`
+	Image    simpleSigningImage    `json:"image"`
+	Type     string                `json:"type"`
+}
+
+type simpleSigningIdentity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+type simpleSigningImage struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// In-toto statement, as described in https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     interface{}     `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// SLSA provenance predicate, as described in https://slsa.dev/spec/v1.0/provenance
+type slsaProvenance struct {
+	BuildDefinition slsaBuildDefinition `json:"buildDefinition"`
+	RunDetails      slsaRunDetails      `json:"runDetails"`
+}
+
+type slsaBuildDefinition struct {
+	BuildType          string            `json:"buildType"`
+	ExternalParameters map[string]string `json:"externalParameters"`
+	InternalParameters map[string]string `json:"internalParameters,omitempty"`
+}
+
+type slsaRunDetails struct {
+	Builder  slsaBuilder  `json:"builder"`
+	Metadata slsaMetadata `json:"metadata"`
+}
+
+type slsaBuilder struct {
+	Id      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type slsaMetadata struct {
+	InvocationId string `json:"invocationId,omitempty"`
+	StartedOn    string `json:"startedOn,omitempty"`
+}
+
+// DSSE envelope, which holds a signed attestation, as described in https://github.com/secure-systems-lab/dsse/blob/master/envelope.md
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	KeyId string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Signs images in the registry, and attaches their signatures and SLSA provenance attestations to them as OCI referrers.
+// The signatures are compatible with cosign's key based signatures.
+type ImageSigner struct {
+	image       *Image
+	loginConfig *ContainerManagerLoginConfig
+	// PEM encoded ECDSA or Ed25519 private key, which isn't encrypted.
+	privateKeyPath string
+	// The digest of the signed manifest. If empty, the manifest currently tagged by the image's tag is signed.
+	manifestDigest string
+	repo           string
+	buildName      string
+	buildNumber    string
+	project        string
+}
+
+func NewImageSigner(image *Image, loginConfig *ContainerManagerLoginConfig, privateKeyPath string) *ImageSigner {
+	return &ImageSigner{image: image, loginConfig: loginConfig, privateKeyPath: privateKeyPath}
+}
+
+func (signer *ImageSigner) SetManifestDigest(manifestDigest string) *ImageSigner {
+	signer.manifestDigest = manifestDigest
+	return signer
+}
+
+// Set the image's repository in Artifactory, which is used for the paths of the build-info artifacts.
+func (signer *ImageSigner) SetRepo(repo string) *ImageSigner {
+	signer.repo = repo
+	return signer
+}
+
+// Set the build, which is recorded in the signature and the provenance.
+func (signer *ImageSigner) SetBuildDetails(buildName, buildNumber, project string) *ImageSigner {
+	signer.buildName = buildName
+	signer.buildNumber = buildNumber
+	signer.project = project
+	return signer
+}
+
+// Sign the image's manifest, and attach the signature and a SLSA provenance attestation to it.
+// Returns the signature and attestation manifests as build-info artifacts.
+func (signer *ImageSigner) Sign() ([]buildinfo.Artifact, error) {
+	privateKey, err := readSigningKey(signer.privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	client, repository, tag, err := createRegistryClient(signer.image, signer.loginConfig)
+	if err != nil {
+		return nil, err
+	}
+	reference := signer.manifestDigest
+	if reference == "" {
+		reference = tag
+	}
+	content, mediaType, err := client.getManifest(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	subject := ociDescriptor{MediaType: mediaType, Digest: calcSha256Digest(content), Size: int64(len(content))}
+	if err = pushBlobContentIfMissing(client, repository, ociDescriptor{MediaType: ociEmptyMediaType, Digest: calcSha256Digest(ociEmptyConfig), Size: int64(len(ociEmptyConfig))}, ociEmptyConfig); err != nil {
+		return nil, err
+	}
+	registry, err := signer.image.GetRegistry()
+	if err != nil {
+		return nil, err
+	}
+	dockerReference := registry + "/" + repository
+	log.Info(fmt.Sprintf("Signing image %s with digest %s...", signer.image.Name(), subject.Digest))
+	signature, err := signer.pushSignature(client, repository, dockerReference, subject, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	attestation, err := signer.pushProvenance(client, repository, dockerReference, subject, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Attached the signature " + signature.Digest + " and the provenance attestation " + attestation.Digest + " to the image.")
+	return []buildinfo.Artifact{signer.toArtifact(repository, signature, "signature"), signer.toArtifact(repository, attestation, "attestation")}, nil
+}
+
+func (signer *ImageSigner) pushSignature(client *ociRegistryClient, repository, dockerReference string, subject ociDescriptor, privateKey crypto.Signer) (*ociDescriptor, error) {
+	payload, err := json.Marshal(simpleSigningPayload{
+		Critical: simpleSigningCritical{
+			Identity: simpleSigningIdentity{DockerReference: dockerReference},
+			Image:    simpleSigningImage{DockerManifestDigest: subject.Digest},
+			Type:     cosignSignatureType,
+		},
+		Optional: signer.getBuildDetails(),
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	signature, err := signPayload(privateKey, payload)
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)}
+	return pushReferrer(client, repository, subject, CosignSignatureArtifactType, simpleSigningMediaType, payload, annotations)
+}
+
+func (signer *ImageSigner) pushProvenance(client *ociRegistryClient, repository, dockerReference string, subject ociDescriptor, privateKey crypto.Signer) (*ociDescriptor, error) {
+	statement, err := json.Marshal(inTotoStatement{
+		Type:          inTotoStatementType,
+		Subject:       []inTotoSubject{{Name: dockerReference, Digest: map[string]string{sha256DigestAlgorithm: strings.TrimPrefix(subject.Digest, sha256DigestAlgorithm+":")}}},
+		PredicateType: SlsaProvenancePredicateType,
+		Predicate: slsaProvenance{
+			BuildDefinition: slsaBuildDefinition{
+				BuildType:          provenanceBuildType,
+				ExternalParameters: map[string]string{"image": signer.image.Name()},
+				InternalParameters: signer.getBuildDetails(),
+			},
+			RunDetails: slsaRunDetails{
+				Builder:  slsaBuilder{Id: provenanceBuilderId, Version: map[string]string{coreutils.GetCliUserAgentName(): coreutils.GetCliUserAgentVersion()}},
+				Metadata: slsaMetadata{InvocationId: corelog.GetCorrelationId(), StartedOn: time.Now().UTC().Format(time.RFC3339)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	signature, err := signPayload(privateKey, dssePreAuthEncoding(InTotoArtifactType, statement))
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := json.Marshal(dsseEnvelope{
+		PayloadType: InTotoArtifactType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures:  []dsseSignature{{Sig: base64.StdEncoding.EncodeToString(signature)}},
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	annotations := map[string]string{predicateTypeAnnotation: SlsaProvenancePredicateType}
+	return pushReferrer(client, repository, subject, InTotoArtifactType, dsseEnvelopeMediaType, envelope, annotations)
+}
+
+func (signer *ImageSigner) getBuildDetails() map[string]string {
+	if signer.buildName == "" {
+		return nil
+	}
+	details := map[string]string{"buildName": signer.buildName, "buildNumber": signer.buildNumber}
+	if signer.project != "" {
+		details["project"] = signer.project
+	}
+	return details
+}
+
+// Artifactory stores manifests pushed by digest under <image-path>/sha256__<digest-hex>/manifest.json
+func (signer *ImageSigner) toArtifact(repository string, referrer *ociDescriptor, artifactType string) buildinfo.Artifact {
+	imagePath := repository
+	if signer.repo != "" {
+		imagePath = strings.TrimPrefix(repository, signer.repo+"/")
+	}
+	return buildinfo.Artifact{
+		Name:     "manifest.json",
+		Type:     artifactType,
+		Path:     path.Join(imagePath, digestToLayer(referrer.Digest), "manifest.json"),
+		Checksum: buildinfo.Checksum{Sha256: strings.TrimPrefix(referrer.Digest, sha256DigestAlgorithm+":")},
+	}
+}
+
+// Push an OCI artifact with a single layer, which references the subject manifest.
+// If the registry doesn't support the referrers API, the artifact is added to the referrers tag schema index.
+func pushReferrer(client *ociRegistryClient, repository string, subject ociDescriptor, artifactType, layerMediaType string, layerContent []byte, annotations map[string]string) (*ociDescriptor, error) {
+	layer := ociDescriptor{MediaType: layerMediaType, Digest: calcSha256Digest(layerContent), Size: int64(len(layerContent)), Annotations: annotations}
+	if err := pushBlobContentIfMissing(client, repository, layer, layerContent); err != nil {
+		return nil, err
+	}
+	manifestAnnotations := map[string]string{ociCreatedAnnotation: time.Now().UTC().Format(time.RFC3339)}
+	content, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     OciImageManifestMediaType,
+		ArtifactType:  artifactType,
+		Config:        ociDescriptor{MediaType: ociEmptyMediaType, Digest: calcSha256Digest(ociEmptyConfig), Size: int64(len(ociEmptyConfig))},
+		Layers:        []ociDescriptor{layer},
+		Subject:       &subject,
+		Annotations:   manifestAnnotations,
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	digest, indexed, err := client.pushReferrerManifest(repository, OciImageManifestMediaType, content)
+	if err != nil {
+		return nil, err
+	}
+	referrer := &ociDescriptor{MediaType: OciImageManifestMediaType, Digest: digest, Size: int64(len(content)), ArtifactType: artifactType, Annotations: manifestAnnotations}
+	if !indexed {
+		log.Debug("The registry didn't index the referrer " + digest + ". Adding it to the referrers tag schema index.")
+		if err = client.addToReferrersTagIndex(repository, subject.Digest, *referrer); err != nil {
+			return nil, err
+		}
+	}
+	return referrer, nil
+}
+
+func pushBlobContentIfMissing(client *ociRegistryClient, repository string, blob ociDescriptor, content []byte) error {
+	exists, err := client.blobExists(repository, blob.Digest)
+	if err != nil || exists {
+		return err
+	}
+	return client.pushBlob(repository, blob, bytesBody(content))
+}
+
+// The result of verifying the signatures and attestations of an image.
+type ImageVerificationResult struct {
+	ManifestDigest       string
+	VerifiedSignatures   int
+	VerifiedAttestations int
+}
+
+// VerifyImage verifies the signatures and SLSA provenance attestations attached to the image, with a PEM encoded ECDSA or Ed25519 public key.
+// Signatures and attestations which fail the verification are logged and not counted.
+func VerifyImage(image *Image, loginConfig *ContainerManagerLoginConfig, publicKeyPath string) (*ImageVerificationResult, error) {
+	publicKey, err := readVerificationKey(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	client, repository, tag, err := createRegistryClient(image, loginConfig)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := client.getManifest(repository, tag)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImageVerificationResult{ManifestDigest: calcSha256Digest(content)}
+	signatures, err := getReferrersLayers(client, repository, result.ManifestDigest, CosignSignatureArtifactType, simpleSigningMediaType)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range signatures {
+		if err = verifySignatureLayer(client, repository, result.ManifestDigest, signature, publicKey); err != nil {
+			log.Warn(fmt.Sprintf("The signature %s of image %s could not be verified: %s", signature.Digest, image.Name(), err.Error()))
+			continue
+		}
+		result.VerifiedSignatures++
+	}
+	attestations, err := getReferrersLayers(client, repository, result.ManifestDigest, InTotoArtifactType, dsseEnvelopeMediaType)
+	if err != nil {
+		return nil, err
+	}
+	for _, attestation := range attestations {
+		if err = verifyProvenanceLayer(client, repository, result.ManifestDigest, attestation, publicKey); err != nil {
+			log.Warn(fmt.Sprintf("The attestation %s of image %s could not be verified: %s", attestation.Digest, image.Name(), err.Error()))
+			continue
+		}
+		result.VerifiedAttestations++
+	}
+	return result, nil
+}
+
+// Returns the layers of the given media type, of the referrers of the given artifact type.
+func getReferrersLayers(client *ociRegistryClient, repository, subjectDigest, artifactType, layerMediaType string) ([]ociDescriptor, error) {
+	referrers, err := client.getReferrers(repository, subjectDigest, artifactType)
+	if err != nil {
+		return nil, err
+	}
+	var layers []ociDescriptor
+	for _, referrer := range referrers {
+		content, _, err := client.getManifest(repository, referrer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		referrerManifest := new(ociManifest)
+		if err = json.Unmarshal(content, referrerManifest); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, layer := range referrerManifest.Layers {
+			if layer.MediaType == layerMediaType {
+				layers = append(layers, layer)
+			}
+		}
+	}
+	return layers, nil
+}
+
+func verifySignatureLayer(client *ociRegistryClient, repository, manifestDigest string, layer ociDescriptor, publicKey crypto.PublicKey) error {
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return errorutils.CheckErrorf("the signature annotation is missing or isn't base64 encoded")
+	}
+	payload, err := client.readBlob(repository, layer.Digest)
+	if err != nil {
+		return err
+	}
+	if err = verifyPayload(publicKey, payload, signature); err != nil {
+		return err
+	}
+	signedPayload := new(simpleSigningPayload)
+	if err = json.Unmarshal(payload, signedPayload); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if signedPayload.Critical.Image.DockerManifestDigest != manifestDigest {
+		return errorutils.CheckErrorf("the signature is of the manifest %s", signedPayload.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+func verifyProvenanceLayer(client *ociRegistryClient, repository, manifestDigest string, layer ociDescriptor, publicKey crypto.PublicKey) error {
+	content, err := client.readBlob(repository, layer.Digest)
+	if err != nil {
+		return err
+	}
+	envelope := new(dsseEnvelope)
+	if err = json.Unmarshal(content, envelope); err != nil {
+		return errorutils.CheckError(err)
+	}
+	statement, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return errorutils.CheckErrorf("the attestation's payload isn't base64 encoded")
+	}
+	verified := false
+	for _, dsseSig := range envelope.Signatures {
+		signature, err := base64.StdEncoding.DecodeString(dsseSig.Sig)
+		if err == nil && verifyPayload(publicKey, dssePreAuthEncoding(envelope.PayloadType, statement), signature) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return errorutils.CheckErrorf("none of the attestation's signatures match the public key")
+	}
+	signedStatement := new(inTotoStatement)
+	if err = json.Unmarshal(statement, signedStatement); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if signedStatement.PredicateType != SlsaProvenancePredicateType {
+		return errorutils.CheckErrorf("unexpected predicate type '%s'", signedStatement.PredicateType)
+	}
+	for _, subject := range signedStatement.Subject {
+		if sha256DigestAlgorithm+":"+subject.Digest[sha256DigestAlgorithm] == manifestDigest {
+			return nil
+		}
+	}
+	return errorutils.CheckErrorf("the attestation's subjects don't include the manifest %s", manifestDigest)
+}
+
+// The pre-authentication encoding, which is signed in DSSE envelopes.
+func dssePreAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Reads a PEM encoded ECDSA or Ed25519 private key, in PKCS #8 or SEC 1 form.
+func readSigningKey(privateKeyPath string) (crypto.Signer, error) {
+	content, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errorutils.CheckErrorf("the private key %s is not PEM encoded", privateKeyPath)
+	}
+	var privateKey interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, errorutils.CheckErrorf("unsupported private key type '%s' in %s. Encrypted keys are not supported", block.Type, privateKeyPath)
+	}
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed parsing the private key %s: %s", privateKeyPath, err.Error())
+	}
+	switch key := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, errorutils.CheckErrorf("unsupported private key in %s. Supported types are ECDSA and Ed25519", privateKeyPath)
+}
+
+// ECDSA signatures are of the sha256 digest of the payload. Ed25519 signatures are of the payload itself.
+func signPayload(privateKey crypto.Signer, payload []byte) ([]byte, error) {
+	if _, isEd25519 := privateKey.(ed25519.PrivateKey); isEd25519 {
+		signature, err := privateKey.Sign(rand.Reader, payload, crypto.Hash(0))
+		return signature, errorutils.CheckError(err)
+	}
+	digest := sha256.Sum256(payload)
+	signature, err := privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	return signature, errorutils.CheckError(err)
+}
+
+func readVerificationKey(publicKeyPath string) (crypto.PublicKey, error) {
+	content, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errorutils.CheckErrorf("the public key %s is not PEM encoded", publicKeyPath)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed parsing the public key %s: %s", publicKeyPath, err.Error())
+	}
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return publicKey, nil
+	}
+	return nil, errorutils.CheckErrorf("unsupported public key in %s. Supported types are ECDSA and Ed25519", publicKeyPath)
+}
+
+func verifyPayload(publicKey crypto.PublicKey, payload, signature []byte) error {
+	verified := false
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		verified = ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		verified = ed25519.Verify(key, payload, signature)
+	}
+	if !verified {
+		return errorutils.CheckErrorf("the signature doesn't match the public key")
+	}
+	return nil
+}
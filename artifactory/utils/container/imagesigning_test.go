@@ -0,0 +1,138 @@
+package container
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Writes the private and public keys as PEM files, and returns their paths.
+func writeTestKeyPair(t *testing.T, privateKey crypto.Signer) (privateKeyPath, publicKeyPath string) {
+	dir := t.TempDir()
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	privateKeyPath = filepath.Join(dir, "signing.key")
+	require.NoError(t, os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}), 0600))
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	require.NoError(t, err)
+	publicKeyPath = filepath.Join(dir, "signing.pub")
+	require.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}), 0600))
+	return
+}
+
+// Pushes a test image to the registry, and returns its manifest digest.
+func pushTestImage(t *testing.T, registry *fakeOciRegistry, imageName string) string {
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	_, descriptor := writeTestImage(t, layout, "amd64", "layer")
+	require.NoError(t, layout.tagManifest(descriptor, "1.0"))
+	require.NoError(t, NewOciManager(registry.loginConfig()).RunNativeCmd([]string{"push", imageName, layout.path}))
+	return descriptor.Digest
+}
+
+func TestSignAndVerifyImage(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	tests := []struct {
+		name              string
+		privateKey        crypto.Signer
+		supportsReferrers bool
+	}{
+		{"ecdsaReferrersApi", ecdsaKey, true},
+		{"ecdsaReferrersTagSchema", ecdsaKey, false},
+		{"ed25519ReferrersApi", ed25519Key, true},
+		{"ed25519ReferrersTagSchema", ed25519Key, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := newFakeOciRegistry(t)
+			registry.supportsReferrers = test.supportsReferrers
+			imageName := registry.imageName("docker-local/hello:1.0")
+			manifestDigest := pushTestImage(t, registry, imageName)
+			privateKeyPath, publicKeyPath := writeTestKeyPair(t, test.privateKey)
+
+			artifacts, err := NewImageSigner(NewImage(imageName), registry.loginConfig(), privateKeyPath).
+				SetRepo("docker-local").
+				SetBuildDetails("build", "1", "").
+				Sign()
+			require.NoError(t, err)
+			require.Len(t, artifacts, 2)
+			assert.Equal(t, "signature", artifacts[0].Type)
+			assert.Equal(t, "attestation", artifacts[1].Type)
+			for _, artifact := range artifacts {
+				assert.True(t, strings.HasPrefix(artifact.Path, "hello/sha256__"), artifact.Path)
+				assert.Contains(t, registry.manifests, "docker-local/hello@sha256:"+artifact.Sha256)
+			}
+			// Without the referrers API, the referrers are listed in the index tagged by the referrers tag schema.
+			_, tagSchemaIndexExists := registry.manifests["docker-local/hello:"+getReferrersTag(manifestDigest)]
+			assert.Equal(t, !test.supportsReferrers, tagSchemaIndexExists)
+
+			result, err := VerifyImage(NewImage(imageName), registry.loginConfig(), publicKeyPath)
+			require.NoError(t, err)
+			assert.Equal(t, manifestDigest, result.ManifestDigest)
+			assert.Equal(t, 1, result.VerifiedSignatures)
+			assert.Equal(t, 1, result.VerifiedAttestations)
+
+			// Signatures of another key are not verified.
+			otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+			_, otherPublicKeyPath := writeTestKeyPair(t, otherKey)
+			result, err = VerifyImage(NewImage(imageName), registry.loginConfig(), otherPublicKeyPath)
+			require.NoError(t, err)
+			assert.Zero(t, result.VerifiedSignatures)
+			assert.Zero(t, result.VerifiedAttestations)
+		})
+	}
+}
+
+func TestVerifyImageRetagged(t *testing.T) {
+	registry := newFakeOciRegistry(t)
+	registry.supportsReferrers = true
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyPath, publicKeyPath := writeTestKeyPair(t, key)
+	imageName := registry.imageName("docker-local/hello:1.0")
+	pushTestImage(t, registry, imageName)
+	_, err = NewImageSigner(NewImage(imageName), registry.loginConfig(), privateKeyPath).Sign()
+	require.NoError(t, err)
+
+	// The tag is moved to an unsigned image.
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	_, descriptor := writeTestImage(t, layout, "amd64", "other-layer")
+	require.NoError(t, layout.tagManifest(descriptor, "1.0"))
+	require.NoError(t, NewOciManager(registry.loginConfig()).RunNativeCmd([]string{"push", imageName, layout.path}))
+	result, err := VerifyImage(NewImage(imageName), registry.loginConfig(), publicKeyPath)
+	require.NoError(t, err)
+	assert.Zero(t, result.VerifiedSignatures)
+	assert.Zero(t, result.VerifiedAttestations)
+}
+
+func TestReadSigningKeyUnsupported(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyPath, publicKeyPath := writeTestKeyPair(t, rsaKey)
+	_, err = readSigningKey(privateKeyPath)
+	assert.ErrorContains(t, err, "Supported types are ECDSA and Ed25519")
+	_, err = readVerificationKey(publicKeyPath)
+	assert.ErrorContains(t, err, "Supported types are ECDSA and Ed25519")
+
+	encryptedKeyPath := filepath.Join(t.TempDir(), "cosign.key")
+	require.NoError(t, os.WriteFile(encryptedKeyPath, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("encrypted")}), 0600))
+	_, err = readSigningKey(encryptedKeyPath)
+	assert.ErrorContains(t, err, "Encrypted keys are not supported")
+}
@@ -19,6 +19,15 @@ type localAgentbuildInfoBuilder struct {
 	// Name of the container CLI tool e.g. docker
 	containerManager ContainerManager
 	commandType      CommandType
+	// The digest of the image's manifest, or manifest list, found in Artifactory by Build
+	manifestDigest string
+	// The digest, which a manifest list found in Artifactory must have to be accepted as the pushed image
+	pushedDigest string
+}
+
+// Implemented by container managers, which know the digest of the manifest, or manifest list, they pushed.
+type pushedManifestDigestProvider interface {
+	PushedManifestDigest(image *Image) string
 }
 
 // Create new build info builder container CLI tool
@@ -32,10 +41,16 @@ func NewLocalAgentBuildInfoBuilder(image *Image, repository, buildName, buildNum
 		return nil, err
 	}
 	builder.setImageSha2(imageSha2)
+	// The ID of an image, which is stored locally as a manifest list, is the digest of the list.
+	pushedDigest := imageSha2
+	if provider, ok := containerManager.(pushedManifestDigestProvider); ok {
+		pushedDigest = provider.PushedManifestDigest(image)
+	}
 	return &localAgentbuildInfoBuilder{
 		buildInfoBuilder: builder,
 		containerManager: containerManager,
 		commandType:      commandType,
+		pushedDigest:     pushedDigest,
 	}, err
 }
 
@@ -43,6 +58,13 @@ func (labib *localAgentbuildInfoBuilder) GetLayers() *[]utils.ResultItem {
 	return &labib.buildInfoBuilder.imageLayers
 }
 
+// GetManifestDigest returns the digest of the image's manifest, or manifest list, found in Artifactory by Build.
+// An image manifest is verified by its config digest, which is the local image ID, and a manifest list by the pushed digest.
+// Returns an empty string if the image wasn't found.
+func (labib *localAgentbuildInfoBuilder) GetManifestDigest() string {
+	return labib.manifestDigest
+}
+
 func (labib *localAgentbuildInfoBuilder) SetSkipTaggingLayers(skipTaggingLayers bool) {
 	labib.buildInfoBuilder.skipTaggingLayers = skipTaggingLayers
 }
@@ -54,9 +76,14 @@ func (labib *localAgentbuildInfoBuilder) Build(module string) (*buildinfo.BuildI
 	if err != nil {
 		log.Warn(`Failed to collect build-info, couldn't find image "` + labib.buildInfoBuilder.image.name + `" in Artifactory`)
 		return nil, nil
-	} else {
-		log.Debug("Found manifest.json. Proceeding to create build-info.")
 	}
+	if fatManifestResult, ok := candidateLayers["list.manifest.json"]; manifest == nil && ok {
+		log.Debug("Found list.manifest.json. Proceeding to create build-info for all the image's platforms.")
+		labib.manifestDigest = "sha256:" + fatManifestResult.Sha256
+		return labib.buildMultiPlatform(candidateLayers, fatManifestResult, module)
+	}
+	log.Debug("Found manifest.json. Proceeding to create build-info.")
+	labib.manifestDigest = "sha256:" + candidateLayers["manifest.json"].Sha256
 	// Create build-info from search results.
 	return labib.buildInfoBuilder.createBuildInfo(labib.commandType, manifest, candidateLayers, module)
 }
@@ -76,6 +103,10 @@ func (labib *localAgentbuildInfoBuilder) searchImage() (map[string]*utils.Result
 		if err != nil {
 			return nil, nil, err
 		}
+		// A pushed manifest list is stored with its platform images, so build-info is created for all of them.
+		if fatManifestResult, ok := resultMap["list.manifest.json"]; labib.commandType == Push && ok && labib.isPushedManifestList(fatManifestResult) {
+			return resultMap, nil, nil
+		}
 		manifest, err := getManifest(resultMap, labib.buildInfoBuilder.serviceManager, labib.buildInfoBuilder.repositoryDetails.key)
 		if err != nil {
 			return nil, nil, err
@@ -87,6 +118,21 @@ func (labib *localAgentbuildInfoBuilder) searchImage() (map[string]*utils.Result
 	return nil, nil, errorutils.CheckErrorf(imageNotFoundErrorMessage, labib.buildInfoBuilder.image.name)
 }
 
+// Create build-info for a pushed manifest list, with a module for each of its platform images.
+func (labib *localAgentbuildInfoBuilder) buildMultiPlatform(resultMap map[string]*utils.ResultItem, fatManifestResult *utils.ResultItem, module string) (*buildinfo.BuildInfo, error) {
+	fatManifest, err := getFatManifest(resultMap, labib.buildInfoBuilder.serviceManager, labib.buildInfoBuilder.repositoryDetails.key)
+	if err != nil {
+		return nil, err
+	}
+	// The platform images are stored in folders named by their manifest digests, next to the folder of the tag.
+	fatManifestRootPath := getFatManifestRoot(fatManifestResult.GetItemRelativeLocation()) + "/*"
+	multiPlatformImages, err := performMultiPlatformImageSearch(fatManifestRootPath, labib.buildInfoBuilder.serviceManager)
+	if err != nil {
+		return nil, err
+	}
+	return labib.buildInfoBuilder.createPushedMultiPlatformBuildInfo(fatManifest, fatManifestResult, multiPlatformImages, module)
+}
+
 // Search image layers in artifactory by the provided image path in artifactory.
 // If fat-manifest is found, use it to find our image in Artifactory.
 func (labib *localAgentbuildInfoBuilder) search(imagePathPattern string) (resultMap map[string]*utils.ResultItem, err error) {
@@ -134,6 +180,15 @@ func (labib *localAgentbuildInfoBuilder) isVerifiedManifest(imageManifest *manif
 	return true
 }
 
+// Verify a manifest list by comparing its digest with the pushed digest, since the tag may have been pushed again since.
+func (labib *localAgentbuildInfoBuilder) isPushedManifestList(fatManifestResult *utils.ResultItem) bool {
+	if "sha256:"+fatManifestResult.Sha256 != labib.pushedDigest {
+		log.Debug(`Found incorrect list.manifest.json file. Expects digest "` + labib.pushedDigest + `" found "sha256:` + fatManifestResult.Sha256 + `"`)
+		return false
+	}
+	return true
+}
+
 func (labib *localAgentbuildInfoBuilder) getImageDigestFromFatManifest(fatManifest utils.ResultItem) (string, error) {
 	var fatManifestContent *FatManifest
 	if err := downloadLayer(fatManifest, &fatManifestContent, labib.buildInfoBuilder.serviceManager, labib.buildInfoBuilder.repositoryDetails.key); err != nil {
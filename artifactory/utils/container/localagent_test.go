@@ -0,0 +1,32 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type idErrorContainerManager struct {
+	containerManager
+}
+
+func (*idErrorContainerManager) Id(*Image) (string, error) {
+	return "", errors.New("no such image")
+}
+
+func TestNewLocalAgentBuildInfoBuilderIdError(t *testing.T) {
+	_, err := NewLocalAgentBuildInfoBuilder(NewImage("docker-local/hello:1.0"), "docker-local", "", "", "", nil, Push, &idErrorContainerManager{})
+	assert.EqualError(t, err, "no such image")
+}
+
+func TestIsPushedManifestList(t *testing.T) {
+	builder := &localAgentbuildInfoBuilder{pushedDigest: "sha256:abc"}
+	assert.True(t, builder.isPushedManifestList(&utils.ResultItem{Sha256: "abc"}))
+	// A manifest list pushed to the same tag by another client.
+	assert.False(t, builder.isPushedManifestList(&utils.ResultItem{Sha256: "def"}))
+	// The pushed image isn't a manifest list.
+	builder.pushedDigest = ""
+	assert.False(t, builder.isPushedManifestList(&utils.ResultItem{Sha256: "abc"}))
+}
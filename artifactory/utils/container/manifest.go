@@ -46,6 +46,16 @@ type ManifestDetails struct {
 type Platform struct {
 	Architecture string `json:"architecture"`
 	Os           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Return the platform in the os/arch[/variant] format, e.g. linux/arm64/v8.
+func (platform Platform) String() string {
+	platformString := platform.Os + "/" + platform.Architecture
+	if platform.Variant != "" {
+		platformString += "/" + platform.Variant
+	}
+	return platformString
 }
 
 // Return all the search patterns in which manifest can be found.
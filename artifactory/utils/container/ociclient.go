@@ -0,0 +1,415 @@
+package container
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Parses the parameters of a WWW-Authenticate challenge, e.g. Bearer realm="https://auth.example.com/token",service="registry"
+var authChallengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Client of the OCI Distribution API (https://github.com/opencontainers/distribution-spec/blob/main/spec.md).
+// The client authenticates with basic authentication or with a bearer token, according to the registry's challenge.
+type ociRegistryClient struct {
+	// The registry's URL, including the scheme, e.g. https://my-registry.jfrog.io
+	registryUrl string
+	username    string
+	password    string
+	httpClient  *httpclient.HttpClient
+	// The Authorization header value, set after the registry's challenge is answered.
+	authorization string
+}
+
+func newOciRegistryClient(registryUrl string, httpClient *httpclient.HttpClient, username, password string) *ociRegistryClient {
+	return &ociRegistryClient{
+		registryUrl: strings.TrimSuffix(registryUrl, "/"),
+		username:    username,
+		password:    password,
+		httpClient:  httpClient,
+	}
+}
+
+// The body of a request. Manifests are sent from memory, so their requests can be retried, while blobs are streamed from their files.
+type requestBody struct {
+	content  []byte
+	filePath string
+}
+
+func bytesBody(content []byte) *requestBody {
+	return &requestBody{content: content}
+}
+
+func fileBody(filePath string) *requestBody {
+	return &requestBody{filePath: filePath}
+}
+
+// Sends the request, and answers the registry's authentication challenge if needed.
+// The caller is responsible for closing the response's body.
+func (client *ociRegistryClient) send(method, requestUrl string, body *requestBody, contentLength int64, headers map[string]string) (*http.Response, error) {
+	resp, err := client.sendOnce(method, requestUrl, body, contentLength, headers)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	closeBody(resp)
+	if err = client.authenticate(challenge); err != nil {
+		return nil, err
+	}
+	return client.sendOnce(method, requestUrl, body, contentLength, headers)
+}
+
+// Requests without a file body are retried on server errors, like the requests of the JFrog services clients.
+func (client *ociRegistryClient) sendOnce(method, requestUrl string, body *requestBody, contentLength int64, headers map[string]string) (*http.Response, error) {
+	httpClientDetails := httputils.HttpClientDetails{Headers: make(map[string]string)}
+	for key, value := range headers {
+		httpClientDetails.Headers[key] = value
+	}
+	if client.authorization != "" {
+		httpClientDetails.Headers["Authorization"] = client.authorization
+	}
+	if body == nil || body.filePath == "" {
+		var content []byte
+		if body != nil {
+			content = body.content
+		}
+		resp, _, _, err := client.httpClient.Send(method, requestUrl, content, true, false, httpClientDetails, "")
+		return resp, err
+	}
+	if method != http.MethodPut {
+		return nil, errorutils.CheckErrorf("unexpected %s request with a file body to %s", method, requestUrl)
+	}
+	file, err := os.Open(body.filePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	// The response's body is read and closed by the upload.
+	resp, _, err := client.httpClient.UploadFileFromReader(file, requestUrl, httpClientDetails, contentLength)
+	if err != nil {
+		closeBody(resp)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Answers a WWW-Authenticate challenge of the registry.
+// A Basic challenge is answered with the credentials, and a Bearer challenge with a token requested from the challenge's realm.
+func (client *ociRegistryClient) authenticate(challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if client.username == "" && client.password == "" {
+			return errorutils.CheckErrorf("the registry %s requires authentication, but no credentials were provided", client.registryUrl)
+		}
+		client.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(client.username+":"+client.password))
+		return nil
+	case "bearer":
+		token, err := client.getBearerToken(params)
+		if err != nil {
+			return err
+		}
+		client.authorization = "Bearer " + token
+		return nil
+	}
+	return errorutils.CheckErrorf("the registry %s responded with an unsupported authentication challenge: '%s'", client.registryUrl, challenge)
+}
+
+func (client *ociRegistryClient) getBearerToken(challengeParams string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range authChallengeParamRegex.FindAllStringSubmatch(challengeParams, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", errorutils.CheckErrorf("the registry %s responded with a bearer challenge without a realm", client.registryUrl)
+	}
+	tokenUrl, err := url.Parse(realm)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	query := tokenUrl.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenUrl.RawQuery = query.Encode()
+	httpClientDetails := httputils.HttpClientDetails{User: client.username, Password: client.password}
+	log.Debug("Requesting a registry token from " + realm)
+	resp, body, _, err := client.httpClient.SendGet(tokenUrl.String(), true, httpClientDetails, "")
+	if err != nil {
+		return "", err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return "", err
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", errorutils.CheckErrorf("the token response of %s contains no token", realm)
+}
+
+func (client *ociRegistryClient) getRepositoryUrl(repository string) string {
+	return client.registryUrl + "/v2/" + repository
+}
+
+func (client *ociRegistryClient) blobExists(repository, digest string) (bool, error) {
+	resp, err := client.send(http.MethodHead, client.getRepositoryUrl(repository)+"/blobs/"+digest, nil, 0, nil)
+	if err != nil {
+		return false, err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return true, checkResponseStatus(resp, http.StatusOK)
+}
+
+// Uploads the blob in a single request, following a POST request which starts the upload session.
+func (client *ociRegistryClient) pushBlob(repository string, descriptor ociDescriptor, body *requestBody) error {
+	resp, err := client.send(http.MethodPost, client.getRepositoryUrl(repository)+"/blobs/uploads/", nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	err = checkResponseStatus(resp, http.StatusAccepted)
+	closeBody(resp)
+	if err != nil {
+		return err
+	}
+	uploadUrl, err := client.resolveLocation(resp)
+	if err != nil {
+		return err
+	}
+	query := uploadUrl.Query()
+	query.Set("digest", descriptor.Digest)
+	uploadUrl.RawQuery = query.Encode()
+	resp, err = client.send(http.MethodPut, uploadUrl.String(), body, descriptor.Size, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	return checkResponseStatus(resp, http.StatusCreated)
+}
+
+// The Location header of upload sessions may be relative to the registry's URL.
+func (client *ociRegistryClient) resolveLocation(resp *http.Response) (*url.URL, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errorutils.CheckErrorf("the registry %s didn't return the location of the upload session", client.registryUrl)
+	}
+	base, err := url.Parse(client.registryUrl + "/")
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	locationUrl, err := base.Parse(location)
+	return locationUrl, errorutils.CheckError(err)
+}
+
+// Returns the content of the blob. The caller is responsible for closing it.
+func (client *ociRegistryClient) getBlob(repository, digest string) (io.ReadCloser, error) {
+	resp, err := client.send(http.MethodGet, client.getRepositoryUrl(repository)+"/blobs/"+digest, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkResponseStatus(resp, http.StatusOK); err != nil {
+		closeBody(resp)
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Returns the content of a small blob, such as an image config, after verifying its digest.
+func (client *ociRegistryClient) readBlob(repository, digest string) (content []byte, err error) {
+	reader, err := client.getBlob(repository, digest)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := reader.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	if content, err = io.ReadAll(io.LimitReader(reader, maxManifestSize)); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if calcSha256Digest(content) != digest {
+		return nil, errorutils.CheckErrorf("the digest of the blob %s@%s returned by the registry doesn't match", repository, digest)
+	}
+	return
+}
+
+// Uploads the manifest under the reference, which is a tag or the manifest's digest. Returns the manifest's digest.
+func (client *ociRegistryClient) pushManifest(repository, reference, mediaType string, content []byte) (string, error) {
+	digest, _, err := client.putManifest(repository, reference, mediaType, content)
+	return digest, err
+}
+
+// Uploads a manifest, which references another manifest by its subject field, such as a signature.
+// Returns the manifest's digest, and whether the registry indexed the manifest in its referrers API.
+func (client *ociRegistryClient) pushReferrerManifest(repository, mediaType string, content []byte) (digest string, indexed bool, err error) {
+	digest, header, err := client.putManifest(repository, calcSha256Digest(content), mediaType, content)
+	// Registries which support the referrers API respond with the OCI-Subject header.
+	return digest, header.Get("OCI-Subject") != "", err
+}
+
+func (client *ociRegistryClient) putManifest(repository, reference, mediaType string, content []byte) (string, http.Header, error) {
+	resp, err := client.send(http.MethodPut, client.getRepositoryUrl(repository)+"/manifests/"+reference, bytesBody(content), int64(len(content)), map[string]string{"Content-Type": mediaType})
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeBody(resp)
+	if err = checkResponseStatus(resp, http.StatusCreated); err != nil {
+		return "", nil, err
+	}
+	return calcSha256Digest(content), resp.Header, nil
+}
+
+// Returns the descriptors of the manifests, which reference the manifest with the digest, and are of the artifact type.
+// If the registry doesn't support the referrers API, the referrers are read from the index tagged by the referrers tag schema.
+func (client *ociRegistryClient) getReferrers(repository, digest, artifactType string) ([]ociDescriptor, error) {
+	referrersUrl := client.getRepositoryUrl(repository) + "/referrers/" + digest + "?artifactType=" + url.QueryEscape(artifactType)
+	resp, err := client.send(http.MethodGet, referrersUrl, nil, 0, map[string]string{"Accept": OciImageIndexMediaType})
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+	var index *ociIndex
+	switch resp.StatusCode {
+	case http.StatusOK:
+		index = new(ociIndex)
+		if err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(index); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	case http.StatusNotFound:
+		log.Debug("The registry doesn't support the referrers API. Reading the referrers of " + digest + " by the referrers tag schema.")
+		if index, err = client.getReferrersTagIndex(repository, digest); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, checkResponseStatus(resp, http.StatusOK)
+	}
+	var referrers []ociDescriptor
+	for _, descriptor := range index.Manifests {
+		// Registries may ignore the artifactType filter.
+		if descriptor.ArtifactType == artifactType {
+			referrers = append(referrers, descriptor)
+		}
+	}
+	return referrers, nil
+}
+
+// Adds the referrer to the index tagged by the referrers tag schema, for registries which don't support the referrers API.
+func (client *ociRegistryClient) addToReferrersTagIndex(repository, subjectDigest string, referrer ociDescriptor) error {
+	index, err := client.getReferrersTagIndex(repository, subjectDigest)
+	if err != nil {
+		return err
+	}
+	for _, existing := range index.Manifests {
+		if existing.Digest == referrer.Digest {
+			return nil
+		}
+	}
+	index.Manifests = append(index.Manifests, referrer)
+	content, err := json.Marshal(index)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = client.pushManifest(repository, getReferrersTag(subjectDigest), OciImageIndexMediaType, content)
+	return err
+}
+
+func (client *ociRegistryClient) getReferrersTagIndex(repository, subjectDigest string) (*ociIndex, error) {
+	index := &ociIndex{SchemaVersion: 2, MediaType: OciImageIndexMediaType, Manifests: []ociDescriptor{}}
+	exists, err := client.manifestExists(repository, getReferrersTag(subjectDigest))
+	if err != nil || !exists {
+		return index, err
+	}
+	content, _, err := client.getManifest(repository, getReferrersTag(subjectDigest))
+	if err != nil {
+		return nil, err
+	}
+	return index, errorutils.CheckError(json.Unmarshal(content, index))
+}
+
+func (client *ociRegistryClient) manifestExists(repository, reference string) (bool, error) {
+	resp, err := client.send(http.MethodHead, client.getRepositoryUrl(repository)+"/manifests/"+reference, nil, 0, map[string]string{"Accept": manifestsAcceptHeaderValues})
+	if err != nil {
+		return false, err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return true, checkResponseStatus(resp, http.StatusOK)
+}
+
+// The tag of the referrers index, according to the referrers tag schema, e.g. sha256:abc -> sha256-abc
+func getReferrersTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+// Returns the content and media type of the manifest, by a tag or a digest.
+func (client *ociRegistryClient) getManifest(repository, reference string) (content []byte, mediaType string, err error) {
+	resp, err := client.send(http.MethodGet, client.getRepositoryUrl(repository)+"/manifests/"+reference, nil, 0, map[string]string{"Accept": manifestsAcceptHeaderValues})
+	if err != nil {
+		return
+	}
+	defer closeBody(resp)
+	if err = checkResponseStatus(resp, http.StatusOK); err != nil {
+		return
+	}
+	if content, err = io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1)); err != nil {
+		return nil, "", errorutils.CheckError(err)
+	}
+	if int64(len(content)) > maxManifestSize {
+		return nil, "", errorutils.CheckErrorf("the manifest %s:%s exceeds the maximum manifest size", repository, reference)
+	}
+	if strings.HasPrefix(reference, sha256DigestAlgorithm+":") && calcSha256Digest(content) != reference {
+		return nil, "", errorutils.CheckErrorf("the digest of the manifest %s@%s returned by the registry doesn't match", repository, reference)
+	}
+	mediaType, _, _ = strings.Cut(resp.Header.Get("Content-Type"), ";")
+	if mediaType == "" || mediaType == "application/json" {
+		mediaType = getManifestMediaType(content)
+	}
+	return
+}
+
+func checkResponseStatus(resp *http.Response, expectedStatus int) error {
+	if resp.StatusCode == expectedStatus {
+		return nil
+	}
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, 4096))
+	}
+	return errorutils.CheckErrorf("%s %s: the registry responded with %s\n%s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+}
+
+func closeBody(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+}
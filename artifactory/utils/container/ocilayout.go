@@ -0,0 +1,371 @@
+package container
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	OciImageManifestMediaType   = "application/vnd.oci.image.manifest.v1+json"
+	OciImageIndexMediaType      = "application/vnd.oci.image.index.v1+json"
+	DockerManifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+	DockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociLayoutFileName           = "oci-layout"
+	ociIndexFileName            = "index.json"
+	ociBlobsDirName             = "blobs"
+	ociRefNameAnnotation        = "org.opencontainers.image.ref.name"
+	ociLayoutVersion            = "1.0.0"
+	sha256DigestAlgorithm       = "sha256"
+	manifestsAcceptHeaderValues = OciImageManifestMediaType + ", " + OciImageIndexMediaType + ", " + DockerManifestMediaType + ", " + DockerManifestListMediaType
+	// Registries may reject larger manifests, according to the OCI Distribution spec.
+	maxManifestSize int64 = 4 * 1024 * 1024
+)
+
+// OCI content descriptor, which references a blob or a manifest by its digest.
+type ociDescriptor struct {
+	MediaType    string            `json:"mediaType,omitempty"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Platform     *Platform         `json:"platform,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// OCI image index or Docker manifest list.
+type ociIndex struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor   `json:"manifests"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// OCI image manifest or Docker image manifest v2.
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Subject       *ociDescriptor    `json:"subject,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// The OS and architecture of an image, as written in its config blob.
+type ociImageConfig struct {
+	Os           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+type ociLayoutFile struct {
+	ImageLayoutVersion string `json:"imageLayoutVersion"`
+}
+
+func isIndexMediaType(mediaType string) bool {
+	return mediaType == OciImageIndexMediaType || mediaType == DockerManifestListMediaType
+}
+
+// Returns the media type of the manifest's content, if the descriptor doesn't include it.
+func getManifestMediaType(content []byte) string {
+	var mediaType struct {
+		MediaType string `json:"mediaType"`
+		Manifests []json.RawMessage
+	}
+	if json.Unmarshal(content, &mediaType) == nil {
+		if mediaType.MediaType != "" {
+			return mediaType.MediaType
+		}
+		if mediaType.Manifests != nil {
+			return OciImageIndexMediaType
+		}
+	}
+	return OciImageManifestMediaType
+}
+
+func calcSha256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return sha256DigestAlgorithm + ":" + hex.EncodeToString(sum[:])
+}
+
+// Returns the hex encoded part of a sha256 digest, e.g. sha256:abc -> abc.
+func getDigestHex(digest string) (string, error) {
+	algorithm, encoded, found := strings.Cut(digest, ":")
+	if !found || algorithm != sha256DigestAlgorithm || len(encoded) != sha256.Size*2 {
+		return "", errorutils.CheckErrorf("unsupported digest '%s'. Only sha256 digests are supported", digest)
+	}
+	if _, err := hex.DecodeString(encoded); err != nil {
+		return "", errorutils.CheckErrorf("invalid digest '%s'", digest)
+	}
+	return encoded, nil
+}
+
+// OCI image layout directory, as described in https://github.com/opencontainers/image-spec/blob/main/image-layout.md
+//
+//	layout (dir)
+//		oci-layout (file)
+//		index.json (file)
+//		blobs (dir)
+//			sha256 (dir)
+//				<digest-hex> (file)
+type ociLayout struct {
+	path string
+}
+
+// Opens an OCI image layout directory, or a tar archive of one (optionally gzipped), such as those written by Kaniko, Buildah or 'docker save'.
+// An archive is extracted into a temp directory, which is removed by the returned cleanup function.
+func openOciLayout(source string) (layout *ociLayout, cleanup func() error, err error) {
+	cleanup = func() error { return nil }
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, cleanup, errorutils.CheckError(err)
+	}
+	layoutPath := source
+	if !info.IsDir() {
+		if layoutPath, err = fileutils.CreateTempDir(); err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() error {
+			return fileutils.RemoveTempDir(layoutPath)
+		}
+		log.Debug("Extracting the image archive " + source + "...")
+		if err = extractTar(source, layoutPath); err != nil {
+			return nil, cleanup, err
+		}
+	}
+	exists, err := fileutils.IsFileExists(filepath.Join(layoutPath, ociLayoutFileName), false)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	if !exists {
+		return nil, cleanup, errorutils.CheckErrorf("%s is not an OCI image layout: the '%s' file is missing", source, ociLayoutFileName)
+	}
+	return &ociLayout{path: layoutPath}, cleanup, nil
+}
+
+// Returns the OCI image layout in the directory, and creates an empty layout if the directory doesn't contain one.
+func createOciLayout(layoutPath string) (*ociLayout, error) {
+	layout := &ociLayout{path: layoutPath}
+	if err := os.MkdirAll(filepath.Join(layoutPath, ociBlobsDirName, sha256DigestAlgorithm), 0755); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	exists, err := fileutils.IsFileExists(filepath.Join(layoutPath, ociLayoutFileName), false)
+	if err != nil || exists {
+		return layout, err
+	}
+	if err = writeJsonFile(filepath.Join(layoutPath, ociLayoutFileName), ociLayoutFile{ImageLayoutVersion: ociLayoutVersion}); err != nil {
+		return nil, err
+	}
+	return layout, writeJsonFile(filepath.Join(layoutPath, ociIndexFileName), ociIndex{SchemaVersion: 2, MediaType: OciImageIndexMediaType, Manifests: []ociDescriptor{}})
+}
+
+func (layout *ociLayout) readIndex() (*ociIndex, error) {
+	content, err := os.ReadFile(filepath.Join(layout.path, ociIndexFileName))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	index := new(ociIndex)
+	return index, errorutils.CheckError(json.Unmarshal(content, index))
+}
+
+// Returns the descriptor of the image tagged in the layout's index.json.
+// If the index holds a single manifest, it is returned regardless of its tag.
+func (layout *ociLayout) getTaggedManifest(tag string) (*ociDescriptor, error) {
+	index, err := layout.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	for i, descriptor := range index.Manifests {
+		if descriptor.Annotations[ociRefNameAnnotation] == tag {
+			return &index.Manifests[i], nil
+		}
+	}
+	if len(index.Manifests) == 1 {
+		return &index.Manifests[0], nil
+	}
+	return nil, errorutils.CheckErrorf("the OCI image layout %s holds %d images, and none of them is tagged '%s'", layout.path, len(index.Manifests), tag)
+}
+
+// Adds the manifest to the layout's index.json, and tags it. An image previously tagged with the same tag is untagged.
+func (layout *ociLayout) tagManifest(descriptor ociDescriptor, tag string) error {
+	index, err := layout.readIndex()
+	if err != nil {
+		return err
+	}
+	manifests := []ociDescriptor{}
+	for _, existing := range index.Manifests {
+		if existing.Annotations[ociRefNameAnnotation] != tag {
+			manifests = append(manifests, existing)
+		}
+	}
+	descriptor.Annotations = map[string]string{ociRefNameAnnotation: tag}
+	index.Manifests = append(manifests, descriptor)
+	return writeJsonFile(filepath.Join(layout.path, ociIndexFileName), index)
+}
+
+func (layout *ociLayout) getBlobPath(digest string) (string, error) {
+	encoded, err := getDigestHex(digest)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(layout.path, ociBlobsDirName, sha256DigestAlgorithm, encoded), nil
+}
+
+func (layout *ociLayout) readBlob(digest string) ([]byte, error) {
+	blobPath, err := layout.getBlobPath(digest)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(blobPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if actual := calcSha256Digest(content); actual != digest {
+		return nil, errorutils.CheckErrorf("the digest of the blob %s in the OCI image layout is %s", digest, actual)
+	}
+	return content, nil
+}
+
+func (layout *ociLayout) hasBlob(digest string) (bool, error) {
+	blobPath, err := layout.getBlobPath(digest)
+	if err != nil {
+		return false, err
+	}
+	return fileutils.IsFileExists(blobPath, false)
+}
+
+func (layout *ociLayout) writeBlob(content []byte) (digest string, err error) {
+	digest = calcSha256Digest(content)
+	blobPath, err := layout.getBlobPath(digest)
+	if err != nil {
+		return
+	}
+	err = errorutils.CheckError(os.WriteFile(blobPath, content, 0644))
+	return
+}
+
+// Writes the blob from the reader into the layout, while verifying its digest.
+func (layout *ociLayout) writeBlobFromReader(digest string, reader io.Reader) error {
+	blobPath, err := layout.getBlobPath(digest)
+	if err != nil {
+		return err
+	}
+	// The blob is written to a temp file, so interrupted downloads don't leave corrupted blobs in the layout.
+	tempPath := blobPath + ".download"
+	actual, err := writeFileWithDigest(tempPath, reader)
+	if err == nil && actual != digest {
+		err = errorutils.CheckErrorf("the digest of the downloaded blob is %s, but %s was expected", actual, digest)
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	return errorutils.CheckError(os.Rename(tempPath, blobPath))
+}
+
+func writeFileWithDigest(filePath string, reader io.Reader) (digest string, err error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(file, hash), reader); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return sha256DigestAlgorithm + ":" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func writeJsonFile(filePath string, value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(filePath, content, 0644))
+}
+
+// Extracts a tar archive, which may be gzipped, into the destination directory.
+func extractTar(archivePath, destDir string) (err error) {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := archive.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	reader := bufio.NewReader(archive)
+	var archiveReader io.Reader = reader
+	// Gzip streams start with the 0x1f 0x8b magic number.
+	if magic, e := reader.Peek(2); e == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer func() {
+			if e := gzipReader.Close(); err == nil {
+				err = errorutils.CheckError(e)
+			}
+		}()
+		archiveReader = gzipReader
+	}
+	tarReader := tar.NewReader(archiveReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errorutils.CheckErrorf("failed reading the archive %s: %s", archivePath, err.Error())
+		}
+		targetPath := filepath.Join(destDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(targetPath, filepath.Clean(destDir)+string(os.PathSeparator)) {
+			return errorutils.CheckErrorf("the archive %s contains an illegal path: %s", archivePath, header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(targetPath, 0755); err != nil {
+				return errorutils.CheckError(err)
+			}
+		case tar.TypeReg:
+			if err = extractTarFile(tarReader, targetPath); err != nil {
+				return err
+			}
+		default:
+			log.Debug(fmt.Sprintf("Skipping the entry %s of type %c in the archive %s", header.Name, header.Typeflag, archivePath))
+		}
+	}
+}
+
+func extractTarFile(reader io.Reader, targetPath string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	file, err := os.Create(targetPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	_, err = io.Copy(file, reader)
+	return errorutils.CheckError(err)
+}
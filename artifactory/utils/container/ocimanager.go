@@ -0,0 +1,450 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	ociManagerUsage = "the supported OCI commands are: 'push <image-tag> <oci-layout-dir-or-tarball>' and 'pull <image-tag> <oci-layout-dir>'"
+	// The default number of retries of the JFrog services clients
+	registryHttpRetries = 3
+)
+
+// Container manager, which pushes and pulls images with the OCI Distribution API, without a container daemon or CLI.
+// It is used in rootless CI jobs, in which images are built by tools such as Kaniko or Buildah.
+// The commands supported by RunNativeCmd are:
+//
+//	push <image-tag> <oci-layout-dir-or-tarball>
+//	pull <image-tag> <oci-layout-dir>
+//
+// The image ID is the digest of the image's config, as with Docker and Podman.
+type ociManager struct {
+	loginConfig *ContainerManagerLoginConfig
+	// The images pushed or pulled by the manager, by their names.
+	images map[string]*ociImageDetails
+}
+
+type ociImageDetails struct {
+	manifestDigest string
+	configDigest   string
+	os             string
+	architecture   string
+}
+
+// Create a daemonless container manager. The registry credentials are taken from the login config, if provided.
+func NewOciManager(loginConfig *ContainerManagerLoginConfig) ContainerManager {
+	return &ociManager{loginConfig: loginConfig, images: make(map[string]*ociImageDetails)}
+}
+
+func (om *ociManager) GetContainerManagerType() ContainerManagerType {
+	return Oci
+}
+
+func (om *ociManager) RunNativeCmd(cmdParams []string) error {
+	if len(cmdParams) != 3 {
+		return errorutils.CheckErrorf("unexpected OCI command '%s': %s", strings.Join(cmdParams, " "), ociManagerUsage)
+	}
+	image := NewImage(cmdParams[1])
+	switch cmdParams[0] {
+	case string(Push):
+		return om.push(image, cmdParams[2])
+	case string(Pull):
+		return om.pull(image, cmdParams[2])
+	}
+	return errorutils.CheckErrorf("unsupported OCI command '%s': %s", cmdParams[0], ociManagerUsage)
+}
+
+// Get image ID, which is the digest of the image's config.
+// If the image wasn't pushed or pulled by the manager, its manifest is read from the registry.
+func (om *ociManager) Id(image *Image) (string, error) {
+	details, err := om.getImageDetails(image)
+	if err != nil {
+		return "", err
+	}
+	return details.configDigest, nil
+}
+
+// Get the digest of the manifest, or image index, pushed by the manager.
+// Returns an empty string if the image wasn't pushed by the manager.
+func (om *ociManager) PushedManifestDigest(image *Image) string {
+	// Resolve the image's tag first, since images with no tag are tagged as 'latest'.
+	if _, err := image.GetImageLongNameWithTag(); err != nil {
+		return ""
+	}
+	if details, exists := om.images[image.Name()]; exists {
+		return details.manifestDigest
+	}
+	return ""
+}
+
+// Return the OS and architecture on which the image runs e.g. (linux, amd64, nil).
+func (om *ociManager) OsCompatibility(image *Image) (string, string, error) {
+	details, err := om.getImageDetails(image)
+	if err != nil {
+		return "", "", err
+	}
+	if details.os == "" || details.architecture == "" {
+		return "", "", errorutils.CheckErrorf("couldn't find OS and architecture of image:" + image.name)
+	}
+	return details.os, details.architecture, nil
+}
+
+// Push an image from an OCI image layout, or a tar archive of one, to the registry.
+// If the image is multi-platform, the manifests of all platforms are pushed, followed by the image index.
+func (om *ociManager) push(image *Image, source string) (err error) {
+	client, repository, tag, err := om.createRegistryClient(image)
+	if err != nil {
+		return
+	}
+	layout, cleanup, err := openOciLayout(source)
+	defer func() {
+		if e := cleanup(); err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+	descriptor, err := layout.getTaggedManifest(tag)
+	if err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("Pushing image %s from %s...", image.Name(), source))
+	details, err := om.pushManifest(client, layout, repository, *descriptor, tag)
+	if err != nil {
+		return
+	}
+	om.images[image.Name()] = details
+	log.Info(fmt.Sprintf("Pushed image %s with digest %s", image.Name(), descriptor.Digest))
+	return
+}
+
+func (om *ociManager) pushManifest(client *ociRegistryClient, layout *ociLayout, repository string, descriptor ociDescriptor, reference string) (*ociImageDetails, error) {
+	content, err := layout.readBlob(descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+	mediaType := descriptor.MediaType
+	if mediaType == "" {
+		mediaType = getManifestMediaType(content)
+	}
+	var details *ociImageDetails
+	if isIndexMediaType(mediaType) {
+		details, err = om.pushIndexManifests(client, layout, repository, content)
+	} else {
+		details, err = om.pushImageBlobs(client, layout, repository, content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if details.manifestDigest, err = client.pushManifest(repository, reference, mediaType, content); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// Push the manifests referenced by the image index, by their digests.
+// Returns the details of the image, which matches the current platform.
+func (om *ociManager) pushIndexManifests(client *ociRegistryClient, layout *ociLayout, repository string, content []byte) (*ociImageDetails, error) {
+	index := new(ociIndex)
+	if err := json.Unmarshal(content, index); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if len(index.Manifests) == 0 {
+		return nil, errorutils.CheckErrorf("the image index references no manifests")
+	}
+	platformDescriptor, err := selectPlatformManifest(index.Manifests)
+	if err != nil {
+		// All the platforms are pushed anyway, and the first one represents the image.
+		log.Debug(err.Error())
+		platformDescriptor = &index.Manifests[0]
+	}
+	var platformDetails *ociImageDetails
+	for _, descriptor := range index.Manifests {
+		details, err := om.pushManifest(client, layout, repository, descriptor, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if descriptor.Digest == platformDescriptor.Digest {
+			platformDetails = details
+		}
+	}
+	return platformDetails, nil
+}
+
+// Push the config and layers of the image, which don't exist in the registry.
+func (om *ociManager) pushImageBlobs(client *ociRegistryClient, layout *ociLayout, repository string, content []byte) (*ociImageDetails, error) {
+	imageManifest := new(ociManifest)
+	if err := json.Unmarshal(content, imageManifest); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, blob := range append([]ociDescriptor{imageManifest.Config}, imageManifest.Layers...) {
+		if err := pushBlobIfMissing(client, layout, repository, blob); err != nil {
+			return nil, err
+		}
+	}
+	config, err := layout.readBlob(imageManifest.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return newOciImageDetails(imageManifest.Config.Digest, config)
+}
+
+func pushBlobIfMissing(client *ociRegistryClient, layout *ociLayout, repository string, blob ociDescriptor) error {
+	exists, err := client.blobExists(repository, blob.Digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		log.Debug("Blob " + blob.Digest + " already exists in the registry.")
+		return nil
+	}
+	inLayout, err := layout.hasBlob(blob.Digest)
+	if err != nil {
+		return err
+	}
+	if !inLayout {
+		// Foreign layers are not distributed with the image, and are pulled from their original location.
+		if isNonDistributableMediaType(blob.MediaType) {
+			log.Info(fmt.Sprintf("Foreign layer: %s is missing in the OCI image layout and therefore will not be pushed.", blob.Digest))
+			return nil
+		}
+		return errorutils.CheckErrorf("the blob %s is missing in the OCI image layout", blob.Digest)
+	}
+	blobPath, err := layout.getBlobPath(blob.Digest)
+	if err != nil {
+		return err
+	}
+	log.Debug("Pushing blob " + blob.Digest + "...")
+	return client.pushBlob(repository, blob, fileBody(blobPath))
+}
+
+// Pull an image from the registry into an OCI image layout directory, which is created if needed.
+// For a multi-platform image, the image matching the current platform is pulled.
+func (om *ociManager) pull(image *Image, layoutPath string) error {
+	client, repository, tag, err := om.createRegistryClient(image)
+	if err != nil {
+		return err
+	}
+	layout, err := createOciLayout(layoutPath)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Pulling image %s into %s...", image.Name(), layoutPath))
+	content, mediaType, err := getPlatformManifest(client, repository, tag)
+	if err != nil {
+		return err
+	}
+	imageManifest := new(ociManifest)
+	if err = json.Unmarshal(content, imageManifest); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for _, blob := range append([]ociDescriptor{imageManifest.Config}, imageManifest.Layers...) {
+		if err = pullBlobIfMissing(client, layout, repository, blob); err != nil {
+			return err
+		}
+	}
+	manifestDigest, err := layout.writeBlob(content)
+	if err != nil {
+		return err
+	}
+	if err = layout.tagManifest(ociDescriptor{MediaType: mediaType, Digest: manifestDigest, Size: int64(len(content))}, tag); err != nil {
+		return err
+	}
+	config, err := layout.readBlob(imageManifest.Config.Digest)
+	if err != nil {
+		return err
+	}
+	details, err := newOciImageDetails(imageManifest.Config.Digest, config)
+	if err != nil {
+		return err
+	}
+	details.manifestDigest = manifestDigest
+	om.images[image.Name()] = details
+	log.Info(fmt.Sprintf("Pulled image %s with digest %s", image.Name(), manifestDigest))
+	return nil
+}
+
+func pullBlobIfMissing(client *ociRegistryClient, layout *ociLayout, repository string, blob ociDescriptor) (err error) {
+	exists, err := layout.hasBlob(blob.Digest)
+	if err != nil || exists {
+		return
+	}
+	if isNonDistributableMediaType(blob.MediaType) {
+		log.Info(fmt.Sprintf("Foreign layer: %s is not distributed by the registry and therefore will not be pulled.", blob.Digest))
+		return
+	}
+	log.Debug("Pulling blob " + blob.Digest + "...")
+	reader, err := client.getBlob(repository, blob.Digest)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := reader.Close(); err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	return layout.writeBlobFromReader(blob.Digest, reader)
+}
+
+func (om *ociManager) getImageDetails(image *Image) (*ociImageDetails, error) {
+	// Resolve the image's tag first, since images with no tag are tagged as 'latest'.
+	if _, err := image.GetImageLongNameWithTag(); err != nil {
+		return nil, err
+	}
+	if details, exists := om.images[image.Name()]; exists {
+		return details, nil
+	}
+	client, repository, tag, err := om.createRegistryClient(image)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err := getPlatformManifest(client, repository, tag)
+	if err != nil {
+		return nil, err
+	}
+	imageManifest := new(ociManifest)
+	if err = json.Unmarshal(content, imageManifest); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	config, err := client.readBlob(repository, imageManifest.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	details, err := newOciImageDetails(imageManifest.Config.Digest, config)
+	if err != nil {
+		return nil, err
+	}
+	details.manifestDigest = calcSha256Digest(content)
+	om.images[image.Name()] = details
+	return details, nil
+}
+
+func (om *ociManager) createRegistryClient(image *Image) (client *ociRegistryClient, repository, tag string, err error) {
+	return createRegistryClient(image, om.loginConfig)
+}
+
+// Returns the registry client, and the image's repository and tag in the registry.
+// The registry credentials are taken from the login config, if provided.
+func createRegistryClient(image *Image, loginConfig *ContainerManagerLoginConfig) (client *ociRegistryClient, repository, tag string, err error) {
+	if repository, err = image.GetImageLongName(); err != nil {
+		return
+	}
+	if tag, err = image.GetImageTag(); err != nil {
+		return
+	}
+	registry, err := image.GetRegistry()
+	if err != nil {
+		return
+	}
+	var serverDetails *config.ServerDetails
+	var username, password string
+	if loginConfig != nil && loginConfig.ServerDetails != nil {
+		serverDetails = loginConfig.ServerDetails
+		username, password = getRegistryCredentials(loginConfig)
+	}
+	registryUrl, err := getRegistryUrl(registry, serverDetails)
+	if err != nil {
+		return
+	}
+	httpClient, err := createRegistryHttpClient(serverDetails)
+	if err != nil {
+		return
+	}
+	client = newOciRegistryClient(registryUrl, httpClient, username, password)
+	return
+}
+
+// Returns the URL of the registry. The registry is accessed over HTTPS,
+// unless it's served by the host of the JFrog Platform, which is accessed over HTTP.
+func getRegistryUrl(registry string, serverDetails *config.ServerDetails) (string, error) {
+	registryUrl, err := url.Parse("https://" + registry)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if serverDetails != nil {
+		for _, platformUrl := range []string{serverDetails.ArtifactoryUrl, serverDetails.Url} {
+			if platformUrl == "" {
+				continue
+			}
+			parsedUrl, err := url.Parse(platformUrl)
+			if err != nil {
+				return "", errorutils.CheckError(err)
+			}
+			if parsedUrl.Scheme == "http" && parsedUrl.Hostname() == registryUrl.Hostname() {
+				registryUrl.Scheme = "http"
+				break
+			}
+		}
+	}
+	return registryUrl.String(), nil
+}
+
+// The registry is accessed with the certificates and the client certificate of the server, and with the default retries of the JFrog services clients.
+func createRegistryHttpClient(serverDetails *config.ServerDetails) (*httpclient.HttpClient, error) {
+	certsPath, err := coreutils.GetJfrogCertsDir()
+	if err != nil {
+		return nil, err
+	}
+	builder := httpclient.ClientBuilder().SetCertificatesPath(certsPath).SetRetries(registryHttpRetries)
+	if serverDetails != nil {
+		builder.SetInsecureTls(serverDetails.InsecureTls).
+			SetClientCertPath(serverDetails.ClientCertPath).
+			SetClientCertKeyPath(serverDetails.ClientCertKeyPath)
+	}
+	return builder.Build()
+}
+
+// Returns the image manifest of a tag. If the tag references an image index, the manifest matching the current platform is returned.
+func getPlatformManifest(client *ociRegistryClient, repository, tag string) (content []byte, mediaType string, err error) {
+	if content, mediaType, err = client.getManifest(repository, tag); err != nil || !isIndexMediaType(mediaType) {
+		return
+	}
+	index := new(ociIndex)
+	if err = json.Unmarshal(content, index); err != nil {
+		return nil, "", errorutils.CheckError(err)
+	}
+	descriptor, err := selectPlatformManifest(index.Manifests)
+	if err != nil {
+		return
+	}
+	return client.getManifest(repository, descriptor.Digest)
+}
+
+// Selects the manifest of the linux image for the current architecture, as Docker does on all operating systems.
+// Manifests without a platform, such as attestations, are ignored.
+func selectPlatformManifest(manifests []ociDescriptor) (*ociDescriptor, error) {
+	var platforms []string
+	for i, descriptor := range manifests {
+		if descriptor.Platform == nil || descriptor.Platform.Os == "unknown" {
+			continue
+		}
+		if descriptor.Platform.Os == "linux" && descriptor.Platform.Architecture == runtime.GOARCH {
+			return &manifests[i], nil
+		}
+		platforms = append(platforms, descriptor.Platform.Os+"/"+descriptor.Platform.Architecture)
+	}
+	return nil, errorutils.CheckErrorf("the multi-platform image has no linux/%s image. Available platforms: %s", runtime.GOARCH, strings.Join(platforms, ", "))
+}
+
+func newOciImageDetails(configDigest string, config []byte) (*ociImageDetails, error) {
+	imageConfig := new(ociImageConfig)
+	if err := json.Unmarshal(config, imageConfig); err != nil {
+		return nil, errorutils.CheckErrorf("failed parsing the image config %s: %s", configDigest, err.Error())
+	}
+	return &ociImageDetails{configDigest: configDigest, os: imageConfig.Os, architecture: imageConfig.Architecture}, nil
+}
+
+func isNonDistributableMediaType(mediaType string) bool {
+	return mediaType == foreignLayerMediaType || strings.Contains(mediaType, ".nondistributable.")
+}
@@ -0,0 +1,407 @@
+package container
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fakeRegistryUser     = "user"
+	fakeRegistryPassword = "password"
+	fakeRegistryToken    = "registry-token"
+)
+
+// In-process registry, which implements the parts of the OCI Distribution API used by the OCI container manager.
+// The registry requires a bearer token, which is issued for the fake registry's credentials.
+type fakeOciRegistry struct {
+	server    *httptest.Server
+	mutex     sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]fakeManifest
+	uploads   int
+	// If false, the referrers API isn't supported, as in registries which implement OCI Distribution v1.0.
+	supportsReferrers bool
+	// The descriptors of the manifests with a subject, by their repository and subject digest.
+	referrers map[string][]ociDescriptor
+}
+
+type fakeManifest struct {
+	mediaType string
+	content   []byte
+}
+
+func newFakeOciRegistry(t *testing.T) *fakeOciRegistry {
+	registry := &fakeOciRegistry{blobs: make(map[string][]byte), manifests: make(map[string]fakeManifest), referrers: make(map[string][]ociDescriptor)}
+	registry.server = httptest.NewServer(http.HandlerFunc(registry.handle))
+	t.Cleanup(registry.server.Close)
+	return registry
+}
+
+// Returns the full name of an image in the registry, e.g. 127.0.0.1:1234/docker-local/hello:1.0
+func (registry *fakeOciRegistry) imageName(repositoryAndTag string) string {
+	return strings.TrimPrefix(registry.server.URL, "http://") + "/" + repositoryAndTag
+}
+
+func (registry *fakeOciRegistry) loginConfig() *ContainerManagerLoginConfig {
+	return &ContainerManagerLoginConfig{ServerDetails: &config.ServerDetails{ArtifactoryUrl: registry.server.URL + "/artifactory/", User: fakeRegistryUser, Password: fakeRegistryPassword}}
+}
+
+func (registry *fakeOciRegistry) handle(w http.ResponseWriter, r *http.Request) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if r.URL.Path == "/token" {
+		if user, password, ok := r.BasicAuth(); !ok || user != fakeRegistryUser || password != fakeRegistryPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"` + fakeRegistryToken + `"}`))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeRegistryToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake-registry",scope="repository:*:pull,push"`, registry.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(path, "/blobs/uploads/"):
+		registry.handleUpload(w, r, path)
+	case strings.Contains(path, "/blobs/"):
+		blob, exists := registry.blobs[path[strings.LastIndex(path, "/")+1:]]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(blob)
+		}
+	case strings.Contains(path, "/manifests/"):
+		registry.handleManifest(w, r, path)
+	case strings.Contains(path, "/referrers/") && registry.supportsReferrers:
+		repository, digest, _ := strings.Cut(path, "/referrers/")
+		index := ociIndex{SchemaVersion: 2, MediaType: OciImageIndexMediaType, Manifests: registry.referrers[repository+"@"+digest]}
+		w.Header().Set("Content-Type", OciImageIndexMediaType)
+		_ = json.NewEncoder(w).Encode(index)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (registry *fakeOciRegistry) handleUpload(w http.ResponseWriter, r *http.Request, path string) {
+	switch r.Method {
+	case http.MethodPost:
+		registry.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s%d?state=fake", path, registry.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		content, err := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if err != nil || r.URL.Query().Get("state") != "fake" || calcSha256Digest(content) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		registry.blobs[digest] = content
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (registry *fakeOciRegistry) handleManifest(w http.ResponseWriter, r *http.Request, path string) {
+	repository, reference, _ := strings.Cut(path, "/manifests/")
+	switch r.Method {
+	case http.MethodPut:
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		manifest := fakeManifest{mediaType: r.Header.Get("Content-Type"), content: content}
+		registry.manifests[repository+"@"+calcSha256Digest(content)] = manifest
+		registry.manifests[repository+":"+reference] = manifest
+		var referrer ociManifest
+		if registry.supportsReferrers && json.Unmarshal(content, &referrer) == nil && referrer.Subject != nil {
+			registry.referrers[repository+"@"+referrer.Subject.Digest] = append(registry.referrers[repository+"@"+referrer.Subject.Digest],
+				ociDescriptor{MediaType: manifest.mediaType, Digest: calcSha256Digest(content), Size: int64(len(content)), ArtifactType: referrer.ArtifactType, Annotations: referrer.Annotations})
+			w.Header().Set("OCI-Subject", referrer.Subject.Digest)
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		manifest, exists := registry.manifests[repository+":"+reference]
+		if !exists {
+			manifest, exists = registry.manifests[repository+"@"+reference]
+		}
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", manifest.mediaType)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(manifest.content)
+		}
+	}
+}
+
+// Writes a single layer image into an OCI image layout, and returns the image's config digest.
+func writeTestImage(t *testing.T, layout *ociLayout, architecture, layerContent string) (configDigest string, descriptor ociDescriptor) {
+	configDigest, err := layout.writeBlob([]byte(fmt.Sprintf(`{"os":"linux","architecture":"%s"}`, architecture)))
+	require.NoError(t, err)
+	layerDigest, err := layout.writeBlob([]byte(layerContent))
+	require.NoError(t, err)
+	manifest, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     OciImageManifestMediaType,
+		Config:        ociDescriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: configDigest},
+		Layers:        []ociDescriptor{{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: layerDigest, Size: int64(len(layerContent))}},
+	})
+	require.NoError(t, err)
+	manifestDigest, err := layout.writeBlob(manifest)
+	require.NoError(t, err)
+	descriptor = ociDescriptor{MediaType: OciImageManifestMediaType, Digest: manifestDigest, Size: int64(len(manifest)), Platform: &Platform{Os: "linux", Architecture: architecture}}
+	return configDigest, descriptor
+}
+
+func TestOciManagerPushAndPull(t *testing.T) {
+	registry := newFakeOciRegistry(t)
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	configDigest, descriptor := writeTestImage(t, layout, "amd64", "layer")
+	require.NoError(t, layout.tagManifest(descriptor, "1.0"))
+
+	imageName := registry.imageName("docker-local/hello:1.0")
+	pushManager := NewOciManager(registry.loginConfig())
+	assert.Equal(t, Oci, pushManager.GetContainerManagerType())
+	require.NoError(t, pushManager.RunNativeCmd([]string{"push", imageName, layout.path}))
+	assert.Contains(t, registry.manifests, "docker-local/hello:1.0")
+	assert.Contains(t, registry.blobs, configDigest)
+	// The image ID is the config digest.
+	id, err := pushManager.Id(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, configDigest, id)
+	imageOs, imageArch, err := pushManager.OsCompatibility(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, "linux", imageOs)
+	assert.Equal(t, "amd64", imageArch)
+
+	// Pushing again only uploads the manifest, since the blobs exist in the registry.
+	uploads := registry.uploads
+	assert.NoError(t, pushManager.RunNativeCmd([]string{"push", imageName, layout.path}))
+	assert.Equal(t, uploads, registry.uploads)
+
+	// Pull the image into a new layout.
+	pullManager := NewOciManager(registry.loginConfig())
+	pulledLayoutPath := filepath.Join(t.TempDir(), "pulled")
+	require.NoError(t, pullManager.RunNativeCmd([]string{"pull", imageName, pulledLayoutPath}))
+	pulledLayout, _, err := openOciLayout(pulledLayoutPath)
+	require.NoError(t, err)
+	pulledDescriptor, err := pulledLayout.getTaggedManifest("1.0")
+	require.NoError(t, err)
+	assert.Equal(t, descriptor.Digest, pulledDescriptor.Digest)
+	for digest := range registry.blobs {
+		exists, err := pulledLayout.hasBlob(digest)
+		assert.NoError(t, err)
+		assert.True(t, exists, digest)
+	}
+	id, err = pullManager.Id(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, configDigest, id)
+
+	// The ID of an image which wasn't pushed or pulled by the manager is read from the registry.
+	id, err = NewOciManager(registry.loginConfig()).Id(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, configDigest, id)
+
+	// Without credentials, the registry's token can't be issued.
+	_, err = NewManager(Oci).Id(NewImage(imageName))
+	assert.Error(t, err)
+}
+
+func TestOciManagerPushTarball(t *testing.T) {
+	registry := newFakeOciRegistry(t)
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	configDigest, descriptor := writeTestImage(t, layout, "arm64", "layer")
+	require.NoError(t, layout.tagManifest(descriptor, "2.0"))
+	tarballPath := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTestTarball(t, layout.path, tarballPath)
+
+	imageName := registry.imageName("docker-local/hello:2.0")
+	manager := NewOciManager(registry.loginConfig())
+	require.NoError(t, manager.RunNativeCmd([]string{"push", imageName, tarballPath}))
+	assert.Equal(t, descriptor.Digest, calcSha256Digest(registry.manifests["docker-local/hello:2.0"].content))
+	id, err := manager.Id(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, configDigest, id)
+}
+
+func TestOciManagerMultiPlatform(t *testing.T) {
+	registry := newFakeOciRegistry(t)
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	otherArch := "s390x"
+	if runtime.GOARCH == otherArch {
+		otherArch = "amd64"
+	}
+	currentConfigDigest, currentDescriptor := writeTestImage(t, layout, runtime.GOARCH, "current-platform-layer")
+	_, otherDescriptor := writeTestImage(t, layout, otherArch, "other-platform-layer")
+	index, err := json.Marshal(ociIndex{SchemaVersion: 2, MediaType: OciImageIndexMediaType, Manifests: []ociDescriptor{otherDescriptor, currentDescriptor}})
+	require.NoError(t, err)
+	indexDigest, err := layout.writeBlob(index)
+	require.NoError(t, err)
+	require.NoError(t, layout.tagManifest(ociDescriptor{MediaType: OciImageIndexMediaType, Digest: indexDigest, Size: int64(len(index))}, "latest"))
+
+	// Push all the platforms, followed by the index.
+	imageName := registry.imageName("docker-local/multi")
+	pushManager := NewOciManager(registry.loginConfig())
+	require.NoError(t, pushManager.RunNativeCmd([]string{"push", imageName, layout.path}))
+	assert.Equal(t, indexDigest, pushManager.(*ociManager).PushedManifestDigest(NewImage(imageName)))
+	assert.Equal(t, OciImageIndexMediaType, registry.manifests["docker-local/multi:latest"].mediaType)
+	assert.Contains(t, registry.manifests, "docker-local/multi@"+otherDescriptor.Digest)
+	assert.Contains(t, registry.manifests, "docker-local/multi@"+currentDescriptor.Digest)
+
+	// Pull the image of the current platform.
+	manager := NewOciManager(registry.loginConfig())
+	require.NoError(t, manager.RunNativeCmd([]string{"pull", imageName, t.TempDir()}))
+	id, err := manager.Id(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, currentConfigDigest, id)
+	_, imageArch, err := manager.OsCompatibility(NewImage(imageName))
+	assert.NoError(t, err)
+	assert.Equal(t, runtime.GOARCH, imageArch)
+}
+
+func TestOciManagerPullTamperedBlob(t *testing.T) {
+	registry := newFakeOciRegistry(t)
+	layout, err := createOciLayout(t.TempDir())
+	require.NoError(t, err)
+	_, descriptor := writeTestImage(t, layout, "amd64", "layer")
+	require.NoError(t, layout.tagManifest(descriptor, "1.0"))
+	imageName := registry.imageName("docker-local/hello:1.0")
+	require.NoError(t, NewOciManager(registry.loginConfig()).RunNativeCmd([]string{"push", imageName, layout.path}))
+
+	for digest, blob := range registry.blobs {
+		if string(blob) == "layer" {
+			registry.blobs[digest] = []byte("tampered")
+		}
+	}
+	pulledLayoutPath := t.TempDir()
+	err = NewOciManager(registry.loginConfig()).RunNativeCmd([]string{"pull", imageName, pulledLayoutPath})
+	assert.ErrorContains(t, err, "digest of the downloaded blob")
+	// Corrupted blobs are not left in the layout.
+	files, err := os.ReadDir(filepath.Join(pulledLayoutPath, ociBlobsDirName, sha256DigestAlgorithm))
+	assert.NoError(t, err)
+	for _, file := range files {
+		assert.False(t, strings.HasSuffix(file.Name(), ".download"), file.Name())
+	}
+}
+
+func TestOciManagerUnsupportedCommand(t *testing.T) {
+	manager := NewOciManager(nil)
+	assert.ErrorContains(t, manager.RunNativeCmd([]string{"push", "domain/repo/image:1.0"}), "unexpected OCI command")
+	assert.ErrorContains(t, manager.RunNativeCmd([]string{"tag", "domain/repo/image:1.0", "domain/repo/image:2.0"}), "unsupported OCI command")
+}
+
+func TestExtractTarIllegalPath(t *testing.T) {
+	tarballPath := filepath.Join(t.TempDir(), "image.tar")
+	file, err := os.Create(tarballPath)
+	require.NoError(t, err)
+	tarWriter := tar.NewWriter(file)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "../escape", Typeflag: tar.TypeReg, Mode: 0644, Size: 1}))
+	_, err = tarWriter.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, file.Close())
+	assert.ErrorContains(t, extractTar(tarballPath, t.TempDir()), "illegal path")
+}
+
+// Archives the OCI image layout into a gzipped tarball.
+func writeTestTarball(t *testing.T, layoutPath, tarballPath string) {
+	file, err := os.Create(tarballPath)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err = filepath.Walk(layoutPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == layoutPath {
+			return err
+		}
+		relativePath, err := filepath.Rel(layoutPath, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relativePath)
+		if err = tarWriter.WriteHeader(header); err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(content)
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+}
+
+func TestGetRegistryUrl(t *testing.T) {
+	testCases := []struct {
+		registry      string
+		serverDetails *config.ServerDetails
+		expected      string
+	}{
+		{"acme.jfrog.io", nil, "https://acme.jfrog.io"},
+		{"acme.jfrog.io", &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"}, "https://acme.jfrog.io"},
+		// A registry served by the host of the JFrog Platform is accessed with the platform's scheme.
+		{"localhost:8082", &config.ServerDetails{ArtifactoryUrl: "http://localhost:8081/artifactory/"}, "http://localhost:8082"},
+		{"localhost:8082", &config.ServerDetails{Url: "http://localhost:8082/"}, "http://localhost:8082"},
+		// Other registries are accessed over HTTPS.
+		{"docker.io", &config.ServerDetails{ArtifactoryUrl: "http://localhost:8081/artifactory/"}, "https://docker.io"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.registry, func(t *testing.T) {
+			registryUrl, err := getRegistryUrl(testCase.registry, testCase.serverDetails)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, registryUrl)
+		})
+	}
+}
+
+func TestOciRegistryClientRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// The first request fails with a server error, and is retried.
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _, _, err := createRegistryClient(NewImage(strings.TrimPrefix(server.URL, "http://")+"/docker-local/hello:1.0"), &ContainerManagerLoginConfig{ServerDetails: &config.ServerDetails{ArtifactoryUrl: server.URL + "/artifactory/"}})
+	require.NoError(t, err)
+	exists, err := client.manifestExists("docker-local/hello", "1.0")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, requests)
+}
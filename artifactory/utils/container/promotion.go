@@ -0,0 +1,271 @@
+package container
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// An image tag, which is stored in an Artifactory repository.
+type StoredImage struct {
+	repo      string
+	imageName string
+	tag       string
+	// The digest of the image's manifest.json or list.manifest.json.
+	manifestDigest string
+	// The files which are referenced by the image's manifests, and which are required to pull it.
+	layers []storedImageLayer
+}
+
+type storedImageLayer struct {
+	// The folder of the manifest which references the layer, relative to the image path, e.g. "1.0" or "sha256__<manifest-digest>".
+	folder string
+	// The file name of the layer, e.g. "sha256__<layer-digest>".
+	name      string
+	mediaType string
+}
+
+func (si *StoredImage) Tag() string {
+	return si.tag
+}
+
+func (si *StoredImage) ManifestDigest() string {
+	return si.manifestDigest
+}
+
+// Return the image tag and the layers its manifests reference. If the tag doesn't exist in the repository, return nil.
+func GetStoredImage(repo, imageName, tag string, serviceManager artifactory.ArtifactoryServicesManager) (*StoredImage, error) {
+	resultMap, err := performSearch(path.Join(repo, imageName, tag, "*"), serviceManager)
+	if err != nil {
+		return nil, err
+	}
+	storedImage := &StoredImage{repo: repo, imageName: imageName, tag: tag}
+	if manifestResult, ok := resultMap["manifest.json"]; ok {
+		storedImage.manifestDigest = "sha256:" + manifestResult.Sha256
+		imageManifest, err := getManifest(resultMap, serviceManager, repo)
+		if err != nil {
+			return nil, err
+		}
+		storedImage.addManifestLayers(tag, imageManifest)
+		return storedImage, nil
+	}
+	fatManifestResult, ok := resultMap["list.manifest.json"]
+	if !ok {
+		return nil, nil
+	}
+	storedImage.manifestDigest = "sha256:" + fatManifestResult.Sha256
+	fatManifest, err := getFatManifest(resultMap, serviceManager, repo)
+	if err != nil {
+		return nil, err
+	}
+	// In case of a fat-manifest, the platform images are stored in folders named as their manifest digests.
+	for _, manifestDetails := range fatManifest.Manifests {
+		folder := digestToLayer(manifestDetails.Digest)
+		platformResultMap, err := performSearch(path.Join(repo, imageName, folder, "*"), serviceManager)
+		if err != nil {
+			return nil, err
+		}
+		imageManifest, err := getManifest(platformResultMap, serviceManager, repo)
+		if err != nil {
+			return nil, err
+		}
+		if imageManifest == nil {
+			return nil, errorutils.CheckErrorf("couldn't find the manifest.json of the %s image %s in %s", manifestDetails.Platform.String(), manifestDetails.Digest, repo)
+		}
+		storedImage.layers = append(storedImage.layers, storedImageLayer{folder: folder, name: "manifest.json"})
+		storedImage.addManifestLayers(folder, imageManifest)
+	}
+	return storedImage, nil
+}
+
+func (si *StoredImage) addManifestLayers(folder string, imageManifest *manifest) {
+	si.layers = append(si.layers, storedImageLayer{folder: folder, name: digestToLayer(imageManifest.Config.Digest)})
+	for _, imageLayer := range removeDuplicateLayers(imageManifest.Layers) {
+		si.layers = append(si.layers, storedImageLayer{folder: folder, name: digestToLayer(imageLayer.Digest), mediaType: imageLayer.MediaType})
+	}
+}
+
+// Return the digest of the image tag's manifest.json or list.manifest.json, without reading its layers.
+// If the tag doesn't exist in the repository, return an empty string.
+func GetImageTagDigest(repo, imageName, tag string, serviceManager artifactory.ArtifactoryServicesManager) (string, error) {
+	resultMap, err := performSearch(path.Join(repo, imageName, tag, "*manifest.json"), serviceManager)
+	if err != nil {
+		return "", err
+	}
+	for _, manifestName := range []string{"manifest.json", "list.manifest.json"} {
+		if manifestResult, ok := resultMap[manifestName]; ok {
+			return "sha256:" + manifestResult.Sha256, nil
+		}
+	}
+	return "", nil
+}
+
+// Return the tag of the image, whose manifest.json or list.manifest.json has the provided digest.
+// Folders which are named as digests, and which hold the platform images of fat-manifests, aren't tags and are therefore ignored.
+func GetImageTagByDigest(repo, imageName, digest string, serviceManager artifactory.ArtifactoryServicesManager) (tag string, err error) {
+	searchParams := services.NewSearchParams()
+	searchParams.CommonParams = &utils.CommonParams{}
+	searchParams.Pattern = path.Join(repo, imageName, "*", "*manifest.json")
+	searchParams.Recursive = true
+	reader, err := serviceManager.SearchFiles(searchParams)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if deferErr := reader.Close(); err == nil {
+			err = deferErr
+		}
+	}()
+	var tags []string
+	for resultItem := new(utils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(utils.ResultItem) {
+		folder := path.Base(resultItem.Path)
+		if path.Dir(resultItem.Path) != imageName || strings.HasPrefix(folder, "sha256__") || "sha256:"+resultItem.Sha256 != digest {
+			continue
+		}
+		tags = append(tags, folder)
+	}
+	if err = reader.GetError(); err != nil {
+		return "", err
+	}
+	if len(tags) == 0 {
+		return "", errorutils.CheckErrorf("couldn't find a tag of the image %s with digest %s in %s", imageName, digest, repo)
+	}
+	// Tags with the same digest hold the same image, so any of them can be promoted.
+	sort.Strings(tags)
+	tag = tags[0]
+	return
+}
+
+// Return true if the image has any tag in the repository.
+func HasImageTags(repo, imageName string, serviceManager artifactory.ArtifactoryServicesManager) (bool, error) {
+	resultMap, err := performSearch(path.Join(repo, imageName, "*", "*manifest.json"), serviceManager)
+	return len(resultMap) > 0, err
+}
+
+// Before the image is promoted to the target repository, verify that every layer its manifests reference will exist in the target repository.
+// Layers which are stored in the folder of the promoted tag are promoted with it.
+// Other layers, which are missing in the target repository, are copied from the source repository to the promoted image's folders,
+// so the image can be pulled from the target repository as soon as it's promoted.
+// Returns the paths of the copied layers in the target repository, including those copied before an error.
+func (si *StoredImage) CopyMissingLayers(targetRepo, targetImageName, targetTag string, serviceManager artifactory.ArtifactoryServicesManager) (copiedLayers []string, err error) {
+	for _, imageLayer := range si.layers {
+		targetFolder := imageLayer.folder
+		if targetFolder == si.tag {
+			promoted, err := isLayerExists(path.Join(si.repo, si.imageName, si.tag, imageLayer.name), serviceManager)
+			if err != nil {
+				return copiedLayers, err
+			}
+			if promoted {
+				continue
+			}
+			targetFolder = targetTag
+		}
+		targetPath := path.Join(targetRepo, targetImageName, targetFolder)
+		// The manifests of platform images must be stored in their folders, while layers may be stored anywhere in the repository.
+		pattern := path.Join(targetRepo, "*", imageLayer.name)
+		if imageLayer.name == "manifest.json" {
+			pattern = path.Join(targetPath, imageLayer.name)
+		}
+		exists, err := isLayerExists(pattern, serviceManager)
+		if err != nil {
+			return copiedLayers, err
+		}
+		if exists {
+			continue
+		}
+		sourcePattern := path.Join(si.repo, "*", imageLayer.name)
+		if imageLayer.name == "manifest.json" {
+			sourcePattern = path.Join(si.repo, si.imageName, imageLayer.folder, imageLayer.name)
+		}
+		sourceExists, err := isLayerExists(sourcePattern, serviceManager)
+		if err != nil {
+			return copiedLayers, err
+		}
+		if !sourceExists {
+			if err = handleMissingLayer(imageLayer.mediaType, imageLayer.name); err != nil {
+				return copiedLayers, err
+			}
+			continue
+		}
+		log.Debug(fmt.Sprintf("Copying the missing layer %s to %s...", imageLayer.name, targetPath))
+		if err = copyLayer(sourcePattern, targetPath, serviceManager); err != nil {
+			return copiedLayers, err
+		}
+		copiedLayers = append(copiedLayers, path.Join(targetPath, imageLayer.name))
+	}
+	return copiedLayers, nil
+}
+
+func isLayerExists(pattern string, serviceManager artifactory.ArtifactoryServicesManager) (exists bool, err error) {
+	searchParams := services.NewSearchParams()
+	searchParams.CommonParams = &utils.CommonParams{}
+	searchParams.Pattern = pattern
+	searchParams.Recursive = true
+	searchParams.Limit = 1
+	reader, err := serviceManager.SearchFiles(searchParams)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if deferErr := reader.Close(); err == nil {
+			err = deferErr
+		}
+	}()
+	length, err := reader.Length()
+	exists = length > 0
+	return
+}
+
+func copyLayer(sourcePattern, targetPath string, serviceManager artifactory.ArtifactoryServicesManager) error {
+	copyParams := services.NewMoveCopyParams()
+	copyParams.Pattern = sourcePattern
+	copyParams.Target = targetPath + "/"
+	copyParams.Recursive = true
+	copyParams.Flat = true
+	// A layer may be stored in several folders of the source repository, but it should be copied once.
+	copyParams.Limit = 1
+	_, totalFailed, err := serviceManager.Copy(copyParams)
+	if err != nil {
+		return err
+	}
+	if totalFailed > 0 {
+		return errorutils.CheckErrorf("failed to copy the layer %s to %s", path.Base(sourcePattern), targetPath)
+	}
+	return nil
+}
+
+// Delete the layers, which were copied to the target repository for an image which wasn't promoted.
+// Returns the paths of the layers, which couldn't be deleted.
+func DeleteCopiedLayers(copiedLayers []string, serviceManager artifactory.ArtifactoryServicesManager) (notDeleted []string) {
+	for _, layerPath := range copiedLayers {
+		if err := deleteLayer(layerPath, serviceManager); err != nil {
+			log.Debug(fmt.Sprintf("Couldn't delete the copied layer %s: %s", layerPath, err.Error()))
+			notDeleted = append(notDeleted, layerPath)
+		}
+	}
+	return
+}
+
+func deleteLayer(layerPath string, serviceManager artifactory.ArtifactoryServicesManager) (err error) {
+	deleteParams := services.NewDeleteParams()
+	deleteParams.CommonParams = &utils.CommonParams{}
+	deleteParams.Pattern = layerPath
+	resultItems, err := serviceManager.GetPathsToDelete(deleteParams)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := resultItems.Close(); err == nil {
+			err = e
+		}
+	}()
+	_, err = serviceManager.DeleteFiles(resultItems)
+	return
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
@@ -23,6 +24,12 @@ const (
 
 type TransferSettings struct {
 	ThreadsNumber int `json:"threadsNumber,omitempty"`
+	// The maximum number of bytes per second sent to be transferred. Zero means unlimited.
+	MaxBytesPerSec int64 `json:"maxBytesPerSec,omitempty"`
+	// The maximum number of files per second sent to be transferred. Zero means unlimited.
+	MaxFilesPerSec int64 `json:"maxFilesPerSec,omitempty"`
+	// The time windows in which the transfer is allowed to run. Empty means the transfer is always allowed.
+	TimeWindows []TimeWindow `json:"timeWindows,omitempty"`
 }
 
 func (ts *TransferSettings) CalcNumberOfThreads(buildInfoRepo bool) int {
@@ -32,6 +39,15 @@ func (ts *TransferSettings) CalcNumberOfThreads(buildInfoRepo bool) int {
 	return ts.ThreadsNumber
 }
 
+// CalcCurrentNumberOfThreads calculates the number of threads at the given time.
+// Outside the allowed time windows the transfer is paused, and therefore zero is returned.
+func (ts *TransferSettings) CalcCurrentNumberOfThreads(buildInfoRepo bool, now time.Time) int {
+	if !IsInTimeWindows(ts.TimeWindows, now) {
+		return 0
+	}
+	return ts.CalcNumberOfThreads(buildInfoRepo)
+}
+
 func LoadTransferSettings() (settings *TransferSettings, err error) {
 	filePath, err := getSettingsFilePath()
 	if err != nil {
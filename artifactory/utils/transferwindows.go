@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	timeWindowTimeLayout  = "15:04"
+	timeWindowEndOfDay    = "24:00"
+	minutesInDay          = 24 * 60
+	timeWindowFormatUsage = "expected format: '[<day>[-<day>][,<day>...]] HH:MM-HH:MM', for example 'Mon-Fri 22:00-06:00'"
+)
+
+var weekdaysShortNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// TimeWindow is a recurring time window in which the transfer is allowed to run.
+type TimeWindow struct {
+	// The days of the week in which the window starts, for example "Mon". Empty means every day.
+	Days []string `json:"days,omitempty"`
+	// The start and end times of the window, in the local time zone, in HH:MM format.
+	// If the end time is not after the start time, the window ends on the following day.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// ParseTimeWindow parses a time window from a string, for example "Mon-Fri 22:00-06:00", "Sat,Sun 00:00-24:00" or "22:00-06:00".
+func ParseTimeWindow(timeWindowStr string) (*TimeWindow, error) {
+	fields := strings.Fields(timeWindowStr)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errorutils.CheckErrorf("invalid time window '%s', %s", timeWindowStr, timeWindowFormatUsage)
+	}
+	timeWindow := &TimeWindow{}
+	if len(fields) == 2 {
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		timeWindow.Days = days
+	}
+	times := strings.Split(fields[len(fields)-1], "-")
+	if len(times) != 2 {
+		return nil, errorutils.CheckErrorf("invalid time window '%s', %s", timeWindowStr, timeWindowFormatUsage)
+	}
+	timeWindow.Start, timeWindow.End = times[0], times[1]
+	if err := timeWindow.validate(); err != nil {
+		return nil, err
+	}
+	return timeWindow, nil
+}
+
+// Parse days such as "Mon-Fri" or "Sat,Sun" to a list of days short names.
+func parseWeekdays(daysStr string) ([]string, error) {
+	var days []string
+	for _, daysRange := range strings.Split(daysStr, ",") {
+		rangeEdges := strings.Split(daysRange, "-")
+		if len(rangeEdges) > 2 {
+			return nil, errorutils.CheckErrorf("invalid days range '%s', %s", daysRange, timeWindowFormatUsage)
+		}
+		first, err := getWeekdayIndex(rangeEdges[0])
+		if err != nil {
+			return nil, err
+		}
+		last, err := getWeekdayIndex(rangeEdges[len(rangeEdges)-1])
+		if err != nil {
+			return nil, err
+		}
+		// Ranges such as "Fri-Mon" wrap around the end of the week.
+		for i := first; ; i = (i + 1) % len(weekdaysShortNames) {
+			days = append(days, weekdaysShortNames[i])
+			if i == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func getWeekdayIndex(day string) (int, error) {
+	for i, weekday := range weekdaysShortNames {
+		if strings.EqualFold(weekday, day) {
+			return i, nil
+		}
+	}
+	return 0, errorutils.CheckErrorf("invalid day '%s', expected one of: %s", day, strings.Join(weekdaysShortNames, ", "))
+}
+
+func (tw *TimeWindow) validate() error {
+	for _, day := range tw.Days {
+		if _, err := getWeekdayIndex(day); err != nil {
+			return err
+		}
+	}
+	if _, err := parseMinutesOfDay(tw.Start); err != nil {
+		return err
+	}
+	_, err := parseMinutesOfDay(tw.End)
+	return err
+}
+
+// Parse a time in HH:MM format to the number of minutes since midnight. "24:00" is accepted as the end of the day.
+func parseMinutesOfDay(timeStr string) (int, error) {
+	if timeStr == timeWindowEndOfDay {
+		return minutesInDay, nil
+	}
+	parsedTime, err := time.Parse(timeWindowTimeLayout, timeStr)
+	if err != nil {
+		return 0, errorutils.CheckErrorf("invalid time '%s', expected HH:MM", timeStr)
+	}
+	return parsedTime.Hour()*60 + parsedTime.Minute(), nil
+}
+
+// Contains returns true if the given time is inside the time window.
+func (tw *TimeWindow) Contains(t time.Time) bool {
+	start, err := parseMinutesOfDay(tw.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseMinutesOfDay(tw.End)
+	if err != nil {
+		return false
+	}
+	minutes := t.Hour()*60 + t.Minute()
+	if start < end {
+		return tw.includesDay(t.Weekday()) && minutes >= start && minutes < end
+	}
+	// The window ends on the following day. A window in which the start equals the end lasts 24 hours.
+	if minutes >= start {
+		return tw.includesDay(t.Weekday())
+	}
+	return minutes < end && tw.includesDay(t.AddDate(0, 0, -1).Weekday())
+}
+
+func (tw *TimeWindow) includesDay(weekday time.Weekday) bool {
+	if len(tw.Days) == 0 {
+		return true
+	}
+	for _, day := range tw.Days {
+		if strings.EqualFold(day, weekdaysShortNames[weekday]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (tw *TimeWindow) String() string {
+	timesStr := fmt.Sprintf("%s-%s", tw.Start, tw.End)
+	if len(tw.Days) == 0 {
+		return timesStr
+	}
+	return strings.Join(tw.Days, ",") + " " + timesStr
+}
+
+// IsInTimeWindows returns true if no time windows are defined, or if the given time is inside one of them.
+func IsInTimeWindows(timeWindows []TimeWindow, t time.Time) bool {
+	if len(timeWindows) == 0 {
+		return true
+	}
+	for i := range timeWindows {
+		if timeWindows[i].Contains(t) {
+			return true
+		}
+	}
+	return false
+}
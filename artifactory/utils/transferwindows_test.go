@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *TimeWindow
+	}{
+		{"22:00-06:00", &TimeWindow{Start: "22:00", End: "06:00"}},
+		{"Mon-Fri 22:00-06:00", &TimeWindow{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "22:00", End: "06:00"}},
+		{"sat,Sun 00:00-24:00", &TimeWindow{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "24:00"}},
+		{"Fri-Mon 10:00-12:00", &TimeWindow{Days: []string{"Fri", "Sat", "Sun", "Mon"}, Start: "10:00", End: "12:00"}},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			timeWindow, err := ParseTimeWindow(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, timeWindow)
+		})
+	}
+
+	for _, invalid := range []string{"", "22:00", "Mon 22:00-25:00", "Mox 22:00-06:00", "Mon-Tue-Wed 22:00-06:00", "Mon Tue 22:00-06:00"} {
+		_, err := ParseTimeWindow(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	weekdaysNights := TimeWindow{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "22:00", End: "06:00"}
+	// 2022-10-10 is a Monday
+	assert.False(t, weekdaysNights.Contains(time.Date(2022, 10, 10, 21, 59, 0, 0, time.Local)))
+	assert.True(t, weekdaysNights.Contains(time.Date(2022, 10, 10, 22, 0, 0, 0, time.Local)))
+	assert.True(t, weekdaysNights.Contains(time.Date(2022, 10, 11, 5, 59, 0, 0, time.Local)))
+	assert.False(t, weekdaysNights.Contains(time.Date(2022, 10, 11, 6, 0, 0, 0, time.Local)))
+	// Friday night continues to Saturday morning
+	assert.True(t, weekdaysNights.Contains(time.Date(2022, 10, 15, 3, 0, 0, 0, time.Local)))
+	// Saturday night and Monday morning are not included
+	assert.False(t, weekdaysNights.Contains(time.Date(2022, 10, 15, 23, 0, 0, 0, time.Local)))
+	assert.False(t, weekdaysNights.Contains(time.Date(2022, 10, 10, 3, 0, 0, 0, time.Local)))
+
+	weekend := TimeWindow{Days: []string{"Sat", "Sun"}, Start: "00:00", End: "24:00"}
+	assert.True(t, weekend.Contains(time.Date(2022, 10, 16, 23, 59, 0, 0, time.Local)))
+	assert.False(t, weekend.Contains(time.Date(2022, 10, 17, 0, 0, 0, 0, time.Local)))
+}
+
+func TestCalcCurrentNumberOfThreads(t *testing.T) {
+	settings := &TransferSettings{ThreadsNumber: 20}
+	now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.Local)
+	assert.Equal(t, 20, settings.CalcCurrentNumberOfThreads(false, now))
+	assert.Equal(t, MaxBuildInfoThreads, settings.CalcCurrentNumberOfThreads(true, now))
+
+	settings.TimeWindows = []TimeWindow{{Start: "22:00", End: "06:00"}}
+	assert.Zero(t, settings.CalcCurrentNumberOfThreads(false, now))
+	assert.Equal(t, 20, settings.CalcCurrentNumberOfThreads(false, now.Add(11*time.Hour)))
+}
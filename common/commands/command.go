@@ -4,6 +4,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
 	"github.com/jfrog/jfrog-client-go/artifactory/usage"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -19,11 +20,13 @@ type Command interface {
 }
 
 func Exec(command Command) error {
+	// The command name is attached to the structured log entries.
+	corelog.SetCommandName(command.CommandName())
 	channel := make(chan bool)
 	// Triggers the report usage.
 	go reportUsage(command, channel)
-	// Invoke the command interface
-	err := command.Run()
+	// Invoke the command interface, through the registered middlewares
+	err := getExecChain()(command)
 	// Waits for the signal from the report usage to be done.
 	<-channel
 	return err
@@ -38,6 +38,7 @@ const (
 	AccessToken AuthenticationMethod = "Access Token"
 	BasicAuth   AuthenticationMethod = "Username and Password / API Key"
 	MTLS        AuthenticationMethod = "Mutual TLS"
+	OIDC        AuthenticationMethod = "OIDC Token Exchange"
 )
 
 // Internal golang locking for the same process.
@@ -312,8 +313,8 @@ func (cc *ConfigCommand) getConfigurationFromUser() (err error) {
 		if err = cc.promptUrls(&disallowUsingSavedPassword); err != nil {
 			return
 		}
-		// Password/Access-Token/MTLS Certificate
-		if cc.details.Password == "" && cc.details.AccessToken == "" {
+		// Password/Access-Token/MTLS Certificate/OIDC
+		if cc.details.Password == "" && cc.details.AccessToken == "" && cc.details.OidcProviderName == "" {
 			var authMethod AuthenticationMethod
 			authMethod, err = promptAuthMethods()
 			if err != nil {
@@ -341,6 +342,9 @@ func (cc *ConfigCommand) getConfigurationFromUser() (err error) {
 			case MTLS:
 				checkCertificateForMTLS(cc)
 				log.Warn("Please notice that authentication using client certificates (mTLS) is not supported by commands which integrate with package managers.")
+			case OIDC:
+				readOidcDetailsFromConsole(cc.details)
+				log.Warn("Please notice that authentication using OIDC token exchange is not supported by commands which integrate with package managers.")
 			}
 		}
 
@@ -363,6 +367,7 @@ func promptAuthMethods() (method AuthenticationMethod, err error) {
 		BasicAuth,
 		AccessToken,
 		MTLS,
+		OIDC,
 	}
 	var selectableItems []ioutils.PromptItem
 	for _, method := range authMethod {
@@ -415,6 +420,14 @@ func readAccessTokenFromConsole(details *config.ServerDetails) error {
 	return err
 }
 
+func readOidcDetailsFromConsole(details *config.ServerDetails) {
+	ioutils.ScanFromConsole("OIDC provider name", &details.OidcProviderName, "")
+	ioutils.ScanFromConsole("OIDC ID token file path (leave empty to use an environment variable)", &details.OidcTokenFile, "")
+	if details.OidcTokenFile == "" {
+		ioutils.ScanFromConsole("OIDC ID token environment variable", &details.OidcTokenEnv, "")
+	}
+}
+
 func getSshKeyPath(details *config.ServerDetails) error {
 	// If path not provided as a key, read from console:
 	if details.SshKeyPath == "" {
@@ -479,6 +492,29 @@ func ShowConfig(serverName string) error {
 	return nil
 }
 
+// Prints the resolved configuration of the server, and the layer each of its fields was resolved from.
+// If serverName is empty, the server selected by the profile, the project or the global default is printed.
+func ShowConfigSources(serverName string) error {
+	details, sources, err := config.GetServerConfigSources(serverName)
+	if err != nil {
+		return err
+	}
+	if details.IsEmpty() {
+		return errorutils.CheckErrorf("no server was configured. Run 'jf c add' or add the server to the .jfrog/servers.yaml file of the project")
+	}
+	for _, field := range config.GetServerFieldNames() {
+		value := config.GetServerFieldValue(details, field)
+		if value == "" {
+			continue
+		}
+		if config.IsSecretServerField(field) {
+			value = "***"
+		}
+		log.Output(fmt.Sprintf("%s: %s\t[%s]", field, value, sources[field]))
+	}
+	return nil
+}
+
 func Import(configTokenString string) error {
 	serverDetails, err := config.Import(configTokenString)
 	if err != nil {
@@ -544,6 +580,9 @@ func printConfigs(configuration []*config.ServerDetails) {
 		logIfNotEmpty(details.SshPassphrase, "SSH passphrase:\t\t\t", true, isDefault)
 		logIfNotEmpty(details.ClientCertPath, "Client certificate file path:\t", false, isDefault)
 		logIfNotEmpty(details.ClientCertKeyPath, "Client certificate key path:\t", false, isDefault)
+		logIfNotEmpty(details.OidcProviderName, "OIDC provider name:\t\t", false, isDefault)
+		logIfNotEmpty(details.OidcTokenFile, "OIDC ID token file path:\t", false, isDefault)
+		logIfNotEmpty(details.OidcTokenEnv, "OIDC ID token env var:\t\t", false, isDefault)
 		logIfNotEmpty(strconv.FormatBool(details.IsDefault), "Default:\t\t\t", false, isDefault)
 		log.Output()
 	}
@@ -697,9 +736,13 @@ func checkSingleAuthMethod(details *config.ServerDetails) error {
 	authMethods := []bool{
 		details.User != "" && details.Password != "",
 		details.AccessToken != "" && details.ArtifactoryRefreshToken == "",
-		details.SshKeyPath != ""}
+		details.SshKeyPath != "",
+		details.OidcProviderName != ""}
 	if coreutils.SumTrueValues(authMethods) > 1 {
-		return errorutils.CheckErrorf("Only one authentication method is allowed: Username + Password/API key, RSA Token (SSH) or Access Token")
+		return errorutils.CheckErrorf("Only one authentication method is allowed: Username + Password/API key, RSA Token (SSH), Access Token or OIDC Token Exchange")
+	}
+	if details.OidcProviderName != "" && details.OidcTokenFile == "" && details.OidcTokenEnv == "" {
+		return errorutils.CheckErrorf("The OIDC token exchange requires the file or the environment variable containing the OIDC ID token")
 	}
 	return nil
 }
@@ -95,6 +95,21 @@ func TestEmptyCredentials(t *testing.T) {
 	configAndTest(t, tests.CreateTestServerDetails(), false)
 }
 
+func TestOidcTokenSource(t *testing.T) {
+	inputDetails := tests.CreateTestServerDetails()
+	inputDetails.OidcProviderName = "github-oidc"
+	// The source of the OIDC ID token is mandatory.
+	configCmd := NewConfigCommand(AddOrEdit, "test").SetDetails(inputDetails).SetInteractive(false)
+	configCmd.disablePrompts = true
+	assert.ErrorContains(t, configCmd.Run(), "requires the file or the environment variable containing the OIDC ID token")
+
+	inputDetails.OidcTokenEnv = "JFROG_OIDC_TOKEN"
+	outputConfig, err := configAndGetTestServer(t, inputDetails, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, configStructToString(inputDetails), configStructToString(outputConfig), "unexpected configuration was saved to file")
+	assert.NoError(t, NewConfigCommand(Delete, "test").Run())
+}
+
 func TestUrls(t *testing.T) {
 	t.Run("non-interactive", func(t *testing.T) { testUrls(t, false) })
 	t.Run("interactive", func(t *testing.T) { testUrls(t, true) })
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type MasterKeyAction string
+
+const (
+	RotateMasterKey   MasterKeyAction = "Rotate"
+	DisableEncryption MasterKeyAction = "Disable"
+)
+
+// MasterKeyCommand re-encrypts the secrets of the config with a new master key, or disables the encryption of the config.
+type MasterKeyCommand struct {
+	action MasterKeyAction
+	// If empty, a random master key is generated
+	newMasterKey string
+}
+
+func NewMasterKeyCommand(action MasterKeyAction) *MasterKeyCommand {
+	return &MasterKeyCommand{action: action}
+}
+
+func (mkc *MasterKeyCommand) SetNewMasterKey(newMasterKey string) *MasterKeyCommand {
+	mkc.newMasterKey = newMasterKey
+	return mkc
+}
+
+func (mkc *MasterKeyCommand) Run() (err error) {
+	// The config lock is acquired by the config package, while the config files are updated.
+	mutex.Lock()
+	defer mutex.Unlock()
+	switch mkc.action {
+	case RotateMasterKey:
+		err = mkc.rotate()
+	case DisableEncryption:
+		if err = config.DisableEncryption(); err == nil {
+			log.Info("The encryption of the config was disabled successfully.")
+		}
+	default:
+		err = fmt.Errorf("Not supported master key command action: " + string(mkc.action))
+	}
+	return
+}
+
+func (mkc *MasterKeyCommand) rotate() (err error) {
+	newMasterKey := mkc.newMasterKey
+	if newMasterKey == "" {
+		if newMasterKey, err = config.GenerateMasterKey(); err != nil {
+			return
+		}
+	}
+	if err = config.RotateMasterKey(newMasterKey); err != nil {
+		return
+	}
+	log.Info("The config was re-encrypted with the new master key successfully.")
+	return
+}
+
+func (mkc *MasterKeyCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (mkc *MasterKeyCommand) CommandName() string {
+	return "config_master_key"
+}
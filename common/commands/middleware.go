@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// ExecFunc runs a command.
+type ExecFunc func(command Command) error
+
+// Middleware wraps the execution of the commands run by Exec.
+// A middleware may act before and after calling next, skip it to abort the command, or call it again to retry.
+// The command's name and server details are available through the Command interface.
+type Middleware func(next ExecFunc) ExecFunc
+
+var (
+	middlewares      []*Middleware
+	middlewaresMutex sync.RWMutex
+)
+
+// RegisterMiddleware adds a middleware around the execution of all commands, and returns a function which removes it.
+// Middlewares are run by their registration order, so the first registered middleware is the outermost.
+func RegisterMiddleware(middleware Middleware) (unregister func()) {
+	middlewaresMutex.Lock()
+	defer middlewaresMutex.Unlock()
+	registered := &middleware
+	middlewares = append(middlewares, registered)
+	return func() {
+		middlewaresMutex.Lock()
+		defer middlewaresMutex.Unlock()
+		for i, current := range middlewares {
+			if current == registered {
+				middlewares = append(middlewares[:i:i], middlewares[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Returns the chain of the registered middlewares, which ends with running the command.
+func getExecChain() ExecFunc {
+	middlewaresMutex.RLock()
+	defer middlewaresMutex.RUnlock()
+	chain := func(command Command) error {
+		return command.Run()
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		chain = (*middlewares[i])(chain)
+	}
+	return chain
+}
+
+// PreRunHook returns a middleware, which runs the hook before the command. If the hook returns an error, the command is not run.
+func PreRunHook(hook func(command Command) error) Middleware {
+	return func(next ExecFunc) ExecFunc {
+		return func(command Command) error {
+			if err := hook(command); err != nil {
+				return err
+			}
+			return next(command)
+		}
+	}
+}
+
+// PostRunHook returns a middleware, which runs the hook after the command, with the command's error.
+func PostRunHook(hook func(command Command, err error)) Middleware {
+	return func(next ExecFunc) ExecFunc {
+		return func(command Command) error {
+			err := next(command)
+			hook(command, err)
+			return err
+		}
+	}
+}
+
+// RetryMiddleware returns a middleware, which runs the command again if it fails with an error accepted by isRetryable.
+// It should be registered only by programs whose commands are safe to run again.
+func RetryMiddleware(maxRetries int, retryWait time.Duration, isRetryable func(err error) bool) Middleware {
+	return func(next ExecFunc) ExecFunc {
+		return func(command Command) error {
+			err := next(command)
+			for attempt := 1; attempt <= maxRetries && err != nil && isRetryable(err); attempt++ {
+				log.Warn(fmt.Sprintf("The %s command failed: %s. Retrying in %s...", command.CommandName(), err.Error(), retryWait.String()), corelog.Fields{"attempt": attempt})
+				time.Sleep(retryWait)
+				err = next(command)
+			}
+			return err
+		}
+	}
+}
+
+// DurationLogMiddleware logs the duration and the result of every command, for audit and metrics.
+func DurationLogMiddleware(next ExecFunc) ExecFunc {
+	return func(command Command) error {
+		start := time.Now()
+		err := next(command)
+		fields := corelog.Fields{"command": command.CommandName(), "durationMs": time.Since(start).Milliseconds(), "success": err == nil}
+		if serverDetails, serverErr := command.ServerDetails(); serverErr == nil && serverDetails != nil {
+			fields["serverId"] = serverDetails.ServerId
+		}
+		log.Debug("Command finished.", fields)
+		return err
+	}
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCommand struct {
+	runs int
+	// The errors returned by the runs, by their order. The runs succeed after the errors are exhausted.
+	errs []error
+}
+
+func (tc *testCommand) Run() error {
+	tc.runs++
+	if tc.runs <= len(tc.errs) {
+		return tc.errs[tc.runs-1]
+	}
+	return nil
+}
+
+func (tc *testCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (tc *testCommand) CommandName() string {
+	return "test_command"
+}
+
+func TestMiddlewaresOrder(t *testing.T) {
+	var calls []string
+	createMiddleware := func(name string) Middleware {
+		return func(next ExecFunc) ExecFunc {
+			return func(command Command) error {
+				calls = append(calls, name+" before "+command.CommandName())
+				err := next(command)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+	defer RegisterMiddleware(createMiddleware("outer"))()
+	unregisterInner := RegisterMiddleware(createMiddleware("inner"))
+
+	assert.NoError(t, Exec(&testCommand{}))
+	assert.Equal(t, []string{"outer before test_command", "inner before test_command", "inner after", "outer after"}, calls)
+
+	unregisterInner()
+	calls = nil
+	assert.NoError(t, Exec(&testCommand{}))
+	assert.Equal(t, []string{"outer before test_command", "outer after"}, calls)
+}
+
+func TestPreAndPostRunHooks(t *testing.T) {
+	validationErr := errors.New("validation failed")
+	var postRunErr error
+	defer RegisterMiddleware(PostRunHook(func(command Command, err error) {
+		postRunErr = err
+	}))()
+	defer RegisterMiddleware(PreRunHook(func(command Command) error {
+		return validationErr
+	}))()
+
+	command := &testCommand{}
+	assert.ErrorIs(t, Exec(command), validationErr)
+	assert.Zero(t, command.runs)
+	assert.ErrorIs(t, postRunErr, validationErr)
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	transientErr := errors.New("503 Service Unavailable")
+	isRetryable := func(err error) bool {
+		return errors.Is(err, transientErr)
+	}
+	defer RegisterMiddleware(DurationLogMiddleware)()
+	defer RegisterMiddleware(RetryMiddleware(2, 0, isRetryable))()
+
+	command := &testCommand{errs: []error{transientErr, transientErr}}
+	assert.NoError(t, Exec(command))
+	assert.Equal(t, 3, command.runs)
+
+	command = &testCommand{errs: []error{transientErr, transientErr, transientErr}}
+	assert.ErrorIs(t, Exec(command), transientErr)
+	assert.Equal(t, 3, command.runs)
+
+	permanentErr := errors.New("404 Not Found")
+	command = &testCommand{errs: []error{permanentErr}}
+	assert.ErrorIs(t, Exec(command), permanentErr)
+	assert.Equal(t, 1, command.runs)
+}
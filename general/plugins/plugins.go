@@ -0,0 +1,340 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	pluginsutils "github.com/jfrog/jfrog-cli-core/v2/utils/plugins"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type PluginAction string
+
+const (
+	InstallPlugin  PluginAction = "Install"
+	UpgradePlugin  PluginAction = "Upgrade"
+	RollbackPlugin PluginAction = "Rollback"
+	ListPlugins    PluginAction = "List"
+)
+
+const (
+	latestVersion = "latest"
+	// The extension of the detached signature, deployed next to the plugin's executable
+	signatureFileExtension = ".sig"
+)
+
+// PluginCommand manages the installed versions of plugins.
+// Plugins are installed from a repository in Artifactory, with the layout: <repo>/<plugin-name>/<version>/<architecture>/<executable>
+type PluginCommand struct {
+	action        PluginAction
+	serverDetails *config.ServerDetails
+	repo          string
+	pluginName    string
+	// The version to install or roll back to. The latest version is installed if empty.
+	version string
+	// The PEM encoded public key, which verifies the detached signatures of the plugins.
+	publicKeyPath string
+	// Install plugins without verifying their signatures, when no public key is provided. Only their checksums are verified.
+	allowUnsigned bool
+}
+
+func NewPluginCommand(action PluginAction) *PluginCommand {
+	return &PluginCommand{action: action}
+}
+
+func (pc *PluginCommand) SetServerDetails(serverDetails *config.ServerDetails) *PluginCommand {
+	pc.serverDetails = serverDetails
+	return pc
+}
+
+func (pc *PluginCommand) SetRepo(repo string) *PluginCommand {
+	pc.repo = repo
+	return pc
+}
+
+func (pc *PluginCommand) SetPluginName(pluginName string) *PluginCommand {
+	pc.pluginName = pluginName
+	return pc
+}
+
+func (pc *PluginCommand) SetVersion(version string) *PluginCommand {
+	pc.version = version
+	return pc
+}
+
+func (pc *PluginCommand) SetPublicKeyPath(publicKeyPath string) *PluginCommand {
+	pc.publicKeyPath = publicKeyPath
+	return pc
+}
+
+func (pc *PluginCommand) SetAllowUnsigned(allowUnsigned bool) *PluginCommand {
+	pc.allowUnsigned = allowUnsigned
+	return pc
+}
+
+func (pc *PluginCommand) ServerDetails() (*config.ServerDetails, error) {
+	return pc.serverDetails, nil
+}
+
+func (pc *PluginCommand) CommandName() string {
+	return "plugin_" + strings.ToLower(string(pc.action))
+}
+
+func (pc *PluginCommand) Run() error {
+	switch pc.action {
+	case InstallPlugin:
+		return pc.install()
+	case UpgradePlugin:
+		return pc.upgrade()
+	case RollbackPlugin:
+		activated, err := pluginsutils.RollbackPlugin(pc.pluginName, pc.version)
+		if err == nil {
+			log.Info(fmt.Sprintf("The plugin %s was rolled back to version %s.", pc.pluginName, activated))
+		}
+		return err
+	case ListPlugins:
+		return pc.list()
+	}
+	return errorutils.CheckErrorf("Not supported plugin command action: " + string(pc.action))
+}
+
+func (pc *PluginCommand) install() error {
+	servicesManager, err := utils.CreateServiceManager(pc.serverDetails, -1, 0, false)
+	if err != nil {
+		return err
+	}
+	pluginVersion := pc.version
+	if pluginVersion == "" || pluginVersion == latestVersion {
+		if pluginVersion, err = pc.getLatestVersion(servicesManager); err != nil {
+			return err
+		}
+	}
+	return pc.installVersion(servicesManager, pluginVersion)
+}
+
+func (pc *PluginCommand) upgrade() error {
+	servicesManager, err := utils.CreateServiceManager(pc.serverDetails, -1, 0, false)
+	if err != nil {
+		return err
+	}
+	latest, err := pc.getLatestVersion(servicesManager)
+	if err != nil {
+		return err
+	}
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	if err != nil {
+		return err
+	}
+	if plugin, exists := installed[pc.pluginName]; exists && plugin.Active != "" && version.NewVersion(plugin.Active).AtLeast(latest) {
+		log.Info(fmt.Sprintf("The plugin %s is already at the latest version %s.", pc.pluginName, plugin.Active))
+		return nil
+	}
+	return pc.installVersion(servicesManager, latest)
+}
+
+func (pc *PluginCommand) installVersion(servicesManager artifactory.ArtifactoryServicesManager, pluginVersion string) error {
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	if err != nil {
+		return err
+	}
+	// Versions are immutable, so an installed version is activated without downloading it again.
+	if plugin, exists := installed[pc.pluginName]; exists && plugin.GetVersion(pluginVersion) != nil {
+		log.Info(fmt.Sprintf("Version %s of the plugin %s is already installed. Activating it...", pluginVersion, pc.pluginName))
+		return pluginsutils.ActivatePluginVersion(pc.pluginName, pluginVersion)
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := fileutils.RemoveTempDir(tempDir); e != nil {
+			log.Warn(e.Error())
+		}
+	}()
+	log.Info(fmt.Sprintf("Downloading version %s of the plugin %s...", pluginVersion, pc.pluginName))
+	installedVersion, err := pc.downloadAndVerify(servicesManager, pluginVersion, tempDir)
+	if err != nil {
+		return err
+	}
+	if err = pluginsutils.AddPluginVersion(pc.pluginName, *installedVersion, tempDir); err != nil {
+		return err
+	}
+	if err = pluginsutils.ActivatePluginVersion(pc.pluginName, pluginVersion); err != nil {
+		return err
+	}
+	// The manifest is read by running the plugin, so it can only be validated after the plugin is made executable.
+	if err = pluginsutils.ValidateInstalledPlugin(pc.pluginName); err != nil {
+		if reactivated, removeErr := pluginsutils.RemovePluginVersion(pc.pluginName, pluginVersion); removeErr != nil {
+			log.Error(fmt.Sprintf("Failed removing version %s of the plugin %s: %s", pluginVersion, pc.pluginName, removeErr.Error()))
+		} else if reactivated != "" {
+			log.Warn(fmt.Sprintf("Version %s of the plugin %s cannot be used on this machine and was removed. Version %s was reactivated.", pluginVersion, pc.pluginName, reactivated))
+		} else {
+			log.Warn(fmt.Sprintf("Version %s of the plugin %s cannot be used on this machine and was removed.", pluginVersion, pc.pluginName))
+		}
+		return err
+	}
+	log.Info(fmt.Sprintf("The plugin %s version %s was installed successfully.", pc.pluginName, pluginVersion))
+	return nil
+}
+
+// Downloads the plugin's executable into destDir/bin, and verifies its checksum and signature.
+// The checksum is stored by the same server the executable is downloaded from, so only the signature proves the executable's origin.
+// The signature is therefore required, unless unsigned plugins are explicitly allowed.
+func (pc *PluginCommand) downloadAndVerify(servicesManager artifactory.ArtifactoryServicesManager, pluginVersion, destDir string) (*pluginsutils.InstalledVersion, error) {
+	if pc.publicKeyPath == "" && !pc.allowUnsigned {
+		return nil, errorutils.CheckErrorf("a public key is required to verify the signature of the plugin %s. To install the plugin without verifying its signature, explicitly allow unsigned plugins", pc.pluginName)
+	}
+	executableName := pluginsutils.GetLocalPluginExecutableName(pc.pluginName)
+	repoPath := path.Join(pc.repo, pc.pluginName, pluginVersion, GetPluginArchitecture(), executableName)
+	binDir := filepath.Join(destDir, coreutils.PluginsExecDirName)
+	// The checksum is taken from the storage API, rather than from the download response, so it verifies the downloaded content.
+	sha256, err := pc.getStoredSha256(servicesManager, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = pc.download(servicesManager, repoPath, binDir, executableName); err != nil {
+		return nil, err
+	}
+	executablePath := filepath.Join(binDir, executableName)
+	if err = pluginsutils.VerifySha256(executablePath, sha256); err != nil {
+		return nil, err
+	}
+	if pc.publicKeyPath != "" {
+		signaturesDir := filepath.Join(destDir, "signatures")
+		if _, err = pc.download(servicesManager, repoPath+signatureFileExtension, signaturesDir, executableName+signatureFileExtension); err != nil {
+			return nil, err
+		}
+		if err = pluginsutils.VerifySignature(executablePath, filepath.Join(signaturesDir, executableName+signatureFileExtension), pc.publicKeyPath); err != nil {
+			return nil, err
+		}
+		log.Info("The signature of the plugin was verified successfully.")
+	} else {
+		log.Warn(fmt.Sprintf("Unsigned plugins are allowed, so the signature of the plugin %s version %s is NOT verified. Only its checksum is verified.", pc.pluginName, pluginVersion))
+	}
+	return &pluginsutils.InstalledVersion{Version: pluginVersion, Sha256: sha256, Source: repoPath}, nil
+}
+
+func (pc *PluginCommand) download(servicesManager artifactory.ArtifactoryServicesManager, repoPath, localPath, localFileName string) (*http.Response, error) {
+	downloadUrl := clientutils.AddTrailingSlashIfNeeded(pc.serverDetails.ArtifactoryUrl) + repoPath
+	httpClientDetails := servicesManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	downloadFileDetails := &httpclient.DownloadFileDetails{
+		DownloadPath:  downloadUrl,
+		LocalPath:     localPath,
+		LocalFileName: localFileName,
+	}
+	resp, err := servicesManager.Client().DownloadFile(downloadFileDetails, "", &httpClientDetails, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errorutils.CheckErrorf("%s received when attempting to download %s\n%s", resp.Status, downloadUrl, body)
+	}
+	return resp, nil
+}
+
+// Returns the sha256 checksum of the file, as stored in Artifactory.
+func (pc *PluginCommand) getStoredSha256(servicesManager artifactory.ArtifactoryServicesManager, repoPath string) (string, error) {
+	storageUrl := clientutils.AddTrailingSlashIfNeeded(pc.serverDetails.ArtifactoryUrl) + "api/storage/" + repoPath
+	httpClientDetails := servicesManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(storageUrl, true, &httpClientDetails)
+	if err != nil {
+		return "", err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return "", err
+	}
+	var fileInfo struct {
+		Checksums struct {
+			Sha256 string `json:"sha256"`
+		} `json:"checksums"`
+	}
+	if err = json.Unmarshal(body, &fileInfo); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if fileInfo.Checksums.Sha256 == "" {
+		return "", errorutils.CheckErrorf("the sha256 checksum of %s is missing in Artifactory", repoPath)
+	}
+	return fileInfo.Checksums.Sha256, nil
+}
+
+// Returns the newest version of the plugin in the repository.
+func (pc *PluginCommand) getLatestVersion(servicesManager artifactory.ArtifactoryServicesManager) (string, error) {
+	folderInfo, err := servicesManager.FolderInfo(path.Join(pc.repo, pc.pluginName))
+	if err != nil {
+		return "", err
+	}
+	var versions []string
+	for _, child := range folderInfo.Children {
+		name := strings.TrimPrefix(child.Uri, "/")
+		if child.Folder && name != latestVersion {
+			versions = append(versions, name)
+		}
+	}
+	if len(versions) == 0 {
+		return "", errorutils.CheckErrorf("no versions of the plugin %s were found in the repository %s", pc.pluginName, pc.repo)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return version.NewVersion(versions[j]).Compare(versions[i]) > 0
+	})
+	return versions[0], nil
+}
+
+func (pc *PluginCommand) list() error {
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range installed {
+		if pc.pluginName == "" || pc.pluginName == name {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		log.Info("No plugin versions are installed.")
+		return nil
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		plugin := installed[name]
+		for _, installedVersion := range plugin.Versions {
+			line := fmt.Sprintf("%s\t%s", name, installedVersion.Version)
+			if installedVersion.Version == plugin.Active {
+				line += "\t(active)"
+			}
+			log.Output(line)
+		}
+	}
+	return nil
+}
+
+// GetPluginArchitecture returns the architecture directory name of the plugins repository layout, for the current platform.
+func GetPluginArchitecture() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "windows-amd64"
+	case "darwin":
+		if runtime.GOARCH == "arm64" {
+			return "mac-arm64"
+		}
+		return "mac-386"
+	}
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
@@ -0,0 +1,194 @@
+package plugins
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	configtests "github.com/jfrog/jfrog-cli-core/v2/utils/config/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	pluginsutils "github.com/jfrog/jfrog-cli-core/v2/utils/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRepo       = "plugins-repo"
+	testPluginName = "test-plugin"
+)
+
+// The key the plugins of the repository stub are signed with
+var testSigningKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+// The executable of the test plugin prints its signature, like a plugin built with the plugins framework.
+func createTestPluginExecutable(pluginVersion string) []byte {
+	return []byte(fmt.Sprintf("#!/bin/sh\necho '{\"name\":\"%s\",\"version\":\"%s\"}'\n", testPluginName, pluginVersion))
+}
+
+// The executable of a test plugin, which doesn't support the current platform.
+func createUnsupportedPluginExecutable(pluginVersion string) []byte {
+	return []byte(fmt.Sprintf("#!/bin/sh\necho '{\"name\":\"%s\",\"version\":\"%s\",\"manifest\":{\"platforms\":[\"plan9-386\"]}}'\n", testPluginName, pluginVersion))
+}
+
+// Creates a stub of an Artifactory plugins repository, which counts the downloads.
+func createPluginsRepoStub(t *testing.T, downloads *int, tamper bool) *httptest.Server {
+	return createPluginsRepoStubWithExecutables(t, downloads, tamper, createTestPluginExecutable)
+}
+
+func createPluginsRepoStubWithExecutables(t *testing.T, downloads *int, tamper bool, createExecutable func(pluginVersion string) []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/storage/" + testRepo + "/" + testPluginName:
+			_, err := w.Write([]byte(`{"children":[{"uri":"/1.0.0","folder":true},{"uri":"/1.1.0","folder":true},{"uri":"/latest","folder":true}]}`))
+			assert.NoError(t, err)
+			return
+		}
+		for _, pluginVersion := range []string{"1.0.0", "1.1.0"} {
+			repoPath := fmt.Sprintf("%s/%s/%s/%s/%s", testRepo, testPluginName, pluginVersion, GetPluginArchitecture(), testPluginName)
+			content := createExecutable(pluginVersion)
+			switch r.URL.Path {
+			case "/api/storage/" + repoPath:
+				digest := sha256.Sum256(content)
+				_, err := w.Write([]byte(fmt.Sprintf(`{"checksums":{"sha256":"%s"}}`, hex.EncodeToString(digest[:]))))
+				assert.NoError(t, err)
+				return
+			case "/" + repoPath + signatureFileExtension:
+				_, err := w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(testSigningKey, content))))
+				assert.NoError(t, err)
+				return
+			case "/" + repoPath:
+				*downloads++
+				if tamper {
+					content = append(content, '\n')
+				}
+				// The checksum header of the download response isn't trusted.
+				digest := sha256.Sum256(content)
+				w.Header().Set("X-Checksum-Sha256", hex.EncodeToString(digest[:]))
+				_, err := w.Write(content)
+				assert.NoError(t, err)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func runPluginCommand(serverUrl string, action PluginAction, pluginVersion string) error {
+	return createPluginCommand(serverUrl, action, pluginVersion).SetAllowUnsigned(true).Run()
+}
+
+func createPluginCommand(serverUrl string, action PluginAction, pluginVersion string) *PluginCommand {
+	return NewPluginCommand(action).SetServerDetails(&config.ServerDetails{ArtifactoryUrl: serverUrl + "/"}).
+		SetRepo(testRepo).SetPluginName(testPluginName).SetVersion(pluginVersion)
+}
+
+// Writes the PEM encoded public key to a file, and returns its path.
+func writePublicKey(t *testing.T, publicKey ed25519.PublicKey) string {
+	derKey, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+	publicKeyPath := filepath.Join(t.TempDir(), "public.pem")
+	assert.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derKey}), 0600))
+	return publicKeyPath
+}
+
+func assertActiveVersion(t *testing.T, expected string) {
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, installed[testPluginName].Active)
+}
+
+func TestPluginCommand(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("The test plugin is a shell script.")
+	}
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	downloads := 0
+	server := createPluginsRepoStub(t, &downloads, false)
+	defer server.Close()
+
+	assert.NoError(t, runPluginCommand(server.URL, InstallPlugin, "1.0.0"))
+	assertActiveVersion(t, "1.0.0")
+	assert.NoError(t, runPluginCommand(server.URL, UpgradePlugin, ""))
+	assertActiveVersion(t, "1.1.0")
+	assert.NoError(t, runPluginCommand(server.URL, RollbackPlugin, ""))
+	assertActiveVersion(t, "1.0.0")
+	// The latest version is already installed, so it is activated without downloading it again.
+	assert.NoError(t, runPluginCommand(server.URL, InstallPlugin, ""))
+	assertActiveVersion(t, "1.1.0")
+	assert.Equal(t, 2, downloads)
+	assert.NoError(t, runPluginCommand(server.URL, ListPlugins, ""))
+
+	signature, err := pluginsutils.ReadInstalledPluginSignature(testPluginName)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", signature.Version)
+}
+
+func TestPluginCommandChecksumMismatch(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	downloads := 0
+	server := createPluginsRepoStub(t, &downloads, true)
+	defer server.Close()
+
+	assert.ErrorContains(t, runPluginCommand(server.URL, InstallPlugin, "1.0.0"), "sha256 checksum")
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Empty(t, installed)
+}
+
+func TestPluginCommandUnsupportedVersion(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("The test plugin is a shell script.")
+	}
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	downloads := 0
+	server := createPluginsRepoStubWithExecutables(t, &downloads, false, createUnsupportedPluginExecutable)
+	defer server.Close()
+
+	// The first installed version has no previous version to reactivate, so it's removed.
+	assert.ErrorContains(t, runPluginCommand(server.URL, InstallPlugin, "1.0.0"), "does not support the")
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Empty(t, installed)
+	signature, err := pluginsutils.ReadInstalledPluginSignature(testPluginName)
+	assert.NoError(t, err)
+	assert.Nil(t, signature)
+}
+
+func TestPluginCommandSignature(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("The test plugin is a shell script.")
+	}
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	downloads := 0
+	server := createPluginsRepoStub(t, &downloads, false)
+	defer server.Close()
+
+	// The signature is required, unless unsigned plugins are explicitly allowed.
+	assert.ErrorContains(t, createPluginCommand(server.URL, InstallPlugin, "1.0.0").Run(), "public key is required")
+	assert.Zero(t, downloads)
+
+	// A signature which doesn't match the public key fails the installation.
+	otherPublicKey, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	err = createPluginCommand(server.URL, InstallPlugin, "1.0.0").SetPublicKeyPath(writePublicKey(t, otherPublicKey)).Run()
+	assert.ErrorContains(t, err, "could not be verified")
+	installed, err := pluginsutils.GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Empty(t, installed)
+
+	assert.NoError(t, createPluginCommand(server.URL, InstallPlugin, "1.0.0").SetPublicKeyPath(writePublicKey(t, testSigningKey.Public().(ed25519.PublicKey))).Run())
+	assertActiveVersion(t, "1.0.0")
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils"
 )
 
+// The manifest of the running plugin
+var pluginManifest components.Manifest
+
 // Get the common 'server-id' flag
 func GetServerIdFlag() components.StringFlag {
 	return components.StringFlag{
@@ -18,6 +21,7 @@ func GetServerIdFlag() components.StringFlag {
 }
 
 // Return the Artifactory Details of the provided 'server-id', or the default one.
+// The server must provide the services required by the plugin's manifest.
 func GetServerDetails(c *components.Context) (*config.ServerDetails, error) {
 	details, err := commands.GetConfig(c.GetStringFlagValue("server-id"), false)
 	if err != nil {
@@ -27,9 +31,24 @@ func GetServerDetails(c *components.Context) (*config.ServerDetails, error) {
 		return nil, errors.New("no server-id was found, or the server-id has no url")
 	}
 	details.Url = utils.AddTrailingSlashIfNeeded(details.Url)
+	if err = pluginManifest.ValidateServices(GetServiceUrls(details)); err != nil {
+		return nil, err
+	}
 	err = config.CreateInitialRefreshableTokensIfNeeded(details)
 	if err != nil {
 		return nil, err
 	}
 	return details, nil
 }
+
+// Return the URLs of the server's services, by the services of the plugins manifest.
+func GetServiceUrls(details *config.ServerDetails) map[components.Service]string {
+	return map[components.Service]string{
+		components.Artifactory:    details.ArtifactoryUrl,
+		components.Xray:           details.XrayUrl,
+		components.Distribution:   details.DistributionUrl,
+		components.Pipelines:      details.PipelinesUrl,
+		components.Access:         details.AccessUrl,
+		components.MissionControl: details.MissionControlUrl,
+	}
+}
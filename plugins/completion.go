@@ -0,0 +1,37 @@
+package plugins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const CompletionCommandName = "completion"
+
+// Adds a command to every built plugin, which prints the shell completion script of the plugin.
+// The command is not added if the plugin has a command with the same name.
+func addCompletionCommand(jfrogApp *components.App) {
+	for _, cmd := range jfrogApp.Commands {
+		if cmd.Name == CompletionCommandName {
+			return
+		}
+	}
+	jfrogApp.Commands = append(jfrogApp.Commands, components.Command{
+		Name:        CompletionCommandName,
+		Description: fmt.Sprintf("Print the completion script of the plugin. The supported shells are: %s.", strings.Join(components.CompletionShells, ", ")),
+		Arguments:   []components.Argument{{Name: "shell", Description: "The shell to complete in."}},
+		Action: func(c *components.Context) error {
+			if len(c.Arguments) != 1 {
+				return fmt.Errorf("wrong number of arguments. Expected: 1, Received: %d", len(c.Arguments))
+			}
+			script, err := components.GenerateCompletionScript(*jfrogApp, c.Arguments[0])
+			if err != nil {
+				return err
+			}
+			log.Output(script)
+			return nil
+		},
+	})
+}
@@ -1,5 +1,7 @@
 package components
 
+import "time"
+
 type Argument struct {
 	Name        string
 	Description string
@@ -14,11 +16,16 @@ type EnvVar struct {
 type ActionFunc func(c *Context) error
 
 type Context struct {
-	Arguments   []string
-	stringFlags map[string]string
-	boolFlags   map[string]bool
+	Arguments        []string
+	stringFlags      map[string]string
+	boolFlags        map[string]bool
+	intFlags         map[string]int
+	durationFlags    map[string]time.Duration
+	stringSliceFlags map[string][]string
+	setFlags         map[string]bool
 }
 
+// Returns the value of a StringFlag, EnumFlag or FilePathFlag.
 func (c *Context) GetStringFlagValue(flagName string) string {
 	return c.stringFlags[flagName]
 }
@@ -27,6 +34,23 @@ func (c *Context) GetBoolFlagValue(flagName string) bool {
 	return c.boolFlags[flagName]
 }
 
+func (c *Context) GetIntFlagValue(flagName string) int {
+	return c.intFlags[flagName]
+}
+
+func (c *Context) GetDurationFlagValue(flagName string) time.Duration {
+	return c.durationFlags[flagName]
+}
+
+func (c *Context) GetStringSliceFlagValue(flagName string) []string {
+	return c.stringSliceFlags[flagName]
+}
+
+// Returns true if the flag was set in the command line or by its environment variable.
+func (c *Context) IsFlagSet(flagName string) bool {
+	return c.setFlags[flagName]
+}
+
 type Flag interface {
 	GetName() string
 	GetDescription() string
@@ -38,6 +62,8 @@ type StringFlag struct {
 	// A flag with default value cannot be mandatory.
 	DefaultValue string
 	Mandatory    bool
+	// The environment variable from which the value is taken, if the flag is not set.
+	EnvVar string
 }
 
 func (f StringFlag) GetName() string {
@@ -56,6 +82,8 @@ type BoolFlag struct {
 	Name         string
 	Description  string
 	DefaultValue bool
+	// The environment variable from which the value is taken, if the flag is not set.
+	EnvVar string
 }
 
 func (f BoolFlag) GetName() string {
@@ -69,3 +97,115 @@ func (f BoolFlag) GetDescription() string {
 func (f BoolFlag) GetDefault() bool {
 	return f.DefaultValue
 }
+
+type IntFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue int
+	Mandatory    bool
+	EnvVar       string
+}
+
+func (f IntFlag) GetName() string {
+	return f.Name
+}
+
+func (f IntFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f IntFlag) GetDefault() int {
+	return f.DefaultValue
+}
+
+// The value is parsed by time.ParseDuration, for example "90s" or "1h30m".
+type DurationFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue time.Duration
+	Mandatory    bool
+	EnvVar       string
+}
+
+func (f DurationFlag) GetName() string {
+	return f.Name
+}
+
+func (f DurationFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f DurationFlag) GetDefault() time.Duration {
+	return f.DefaultValue
+}
+
+// The value is a comma separated list, for example "a,b,c".
+type StringSliceFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue []string
+	Mandatory    bool
+	EnvVar       string
+}
+
+func (f StringSliceFlag) GetName() string {
+	return f.Name
+}
+
+func (f StringSliceFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f StringSliceFlag) GetDefault() []string {
+	return f.DefaultValue
+}
+
+// A string flag, whose value must be one of the allowed values.
+type EnumFlag struct {
+	Name          string
+	Description   string
+	AllowedValues []string
+	// A flag with default value cannot be mandatory.
+	DefaultValue string
+	Mandatory    bool
+	EnvVar       string
+}
+
+func (f EnumFlag) GetName() string {
+	return f.Name
+}
+
+func (f EnumFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f EnumFlag) GetDefault() string {
+	return f.DefaultValue
+}
+
+// A string flag, whose value is a path in the file system. A leading '~' is replaced with the user's home directory.
+type FilePathFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue string
+	Mandatory    bool
+	EnvVar       string
+	// If true, the path must exist.
+	MustExist bool
+}
+
+func (f FilePathFlag) GetName() string {
+	return f.Name
+}
+
+func (f FilePathFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f FilePathFlag) GetDefault() string {
+	return f.DefaultValue
+}
@@ -0,0 +1,210 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+)
+
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+var CompletionShells = []string{Bash, Zsh, Fish}
+
+// The executable name of the CLI, used if the plugin is not run by the CLI.
+const defaultCliExecutableName = "jf"
+
+// The completion scripts identify the commands by their paths, such as "/repos/list".
+// Aliases are resolved to the command names, so the path of "repos ls" is "/repos/list" as well.
+const rootCompletionPath = "/"
+
+type completionTransition struct {
+	fromPath string
+	word     string
+	toPath   string
+}
+
+type completionFlag struct {
+	name          string
+	takesValue    bool
+	allowedValues []string
+}
+
+type completionNode struct {
+	path     string
+	commands []string
+	flags    []completionFlag
+}
+
+// The completion data of an app, collected from its commands tree.
+type appCompletion struct {
+	cliName     string
+	appName     string
+	functionId  string
+	transitions []completionTransition
+	nodes       []completionNode
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// GenerateCompletionScript returns a script, which completes the commands, flags and enum values of the app,
+// when it is run as a plugin of the CLI. The supported shells are bash, zsh and fish.
+func GenerateCompletionScript(app App, shell string) (string, error) {
+	completion := newAppCompletion(app)
+	switch shell {
+	case Bash:
+		return completion.bashScript(), nil
+	case Zsh:
+		return completion.zshScript(), nil
+	case Fish:
+		return completion.fishScript(), nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s'. The supported shells are: %s", shell, strings.Join(CompletionShells, ", "))
+}
+
+func newAppCompletion(app App) *appCompletion {
+	cliName := coreutils.GetCliExecutableName()
+	if cliName == "" {
+		cliName = defaultCliExecutableName
+	}
+	completion := &appCompletion{
+		cliName:    cliName,
+		appName:    app.Name,
+		functionId: nonIdentifierChars.ReplaceAllString(cliName+"_"+app.Name, "_"),
+	}
+	completion.addNode(rootCompletionPath, app.Commands, nil)
+	return completion
+}
+
+func (completion *appCompletion) addNode(path string, commands []Command, flags []Flag) {
+	node := completionNode{path: path}
+	for _, cmd := range commands {
+		childPath := strings.TrimSuffix(path, "/") + "/" + cmd.Name
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			node.commands = append(node.commands, name)
+			completion.transitions = append(completion.transitions, completionTransition{fromPath: path, word: name, toPath: childPath})
+		}
+	}
+	for _, flag := range flags {
+		completionFlag := completionFlag{name: flag.GetName(), takesValue: true}
+		switch f := flag.(type) {
+		case BoolFlag:
+			completionFlag.takesValue = false
+		case EnumFlag:
+			completionFlag.allowedValues = f.AllowedValues
+		}
+		node.flags = append(node.flags, completionFlag)
+	}
+	node.flags = append(node.flags, completionFlag{name: "help"})
+	completion.nodes = append(completion.nodes, node)
+	for _, cmd := range commands {
+		completion.addNode(strings.TrimSuffix(path, "/")+"/"+cmd.Name, cmd.Subcommands, cmd.Flags)
+	}
+}
+
+// Returns the words completed at the node: the commands and the flags.
+func (node completionNode) words() string {
+	words := append([]string{}, node.commands...)
+	for _, flag := range node.flags {
+		words = append(words, "--"+flag.name)
+	}
+	return strings.Join(words, " ")
+}
+
+func (completion *appCompletion) bashScript() string {
+	var script strings.Builder
+	fallback := "_" + completion.functionId + "_fallback"
+	fmt.Fprintf(&script, "# bash completion for the %s plugin of %s\n", completion.appName, completion.cliName)
+	fmt.Fprintf(&script, "%s=$(complete -p %s 2>/dev/null | sed -n 's/.*-F \\([^ ]*\\).*/\\1/p')\n\n", fallback, completion.cliName)
+	fmt.Fprintf(&script, "_%s_completion() {\n", completion.functionId)
+	fmt.Fprintf(&script, "\tif [[ \"${COMP_WORDS[1]}\" != \"%s\" ]]; then\n", completion.appName)
+	fmt.Fprintf(&script, "\t\tif [[ -n \"$%s\" && \"$%s\" != \"_%s_completion\" ]]; then\n\t\t\t\"$%s\" \"$@\"\n\t\tfi\n\t\treturn\n\tfi\n", fallback, fallback, completion.functionId, fallback)
+	script.WriteString("\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\" cmd_path=\"/\" i\n")
+	// With the default COMP_WORDBREAKS, '--flag=value' is split into three words.
+	script.WriteString("\tif [[ \"$prev\" == \"=\" ]]; then\n\t\tprev=\"${COMP_WORDS[COMP_CWORD-2]}\"\n\telif [[ \"$cur\" == \"=\" ]]; then\n\t\tcur=\"\"\n\tfi\n")
+	script.WriteString("\tfor ((i = 2; i < COMP_CWORD; i++)); do\n\t\tcase \"$cmd_path:${COMP_WORDS[i]}\" in\n")
+	for _, transition := range completion.transitions {
+		fmt.Fprintf(&script, "\t\t\"%s:%s\") cmd_path=\"%s\" ;;\n", transition.fromPath, transition.word, transition.toPath)
+	}
+	script.WriteString("\t\tesac\n\tdone\n\tcase \"$cmd_path:$prev\" in\n")
+	for _, node := range completion.nodes {
+		for _, flag := range node.flags {
+			if len(flag.allowedValues) > 0 {
+				fmt.Fprintf(&script, "\t\"%s:--%s\")\n\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n\t\treturn\n\t\t;;\n", node.path, flag.name, strings.Join(flag.allowedValues, " "))
+			}
+		}
+	}
+	script.WriteString("\tesac\n\tcase \"$cmd_path\" in\n")
+	for _, node := range completion.nodes {
+		fmt.Fprintf(&script, "\t\"%s\") COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", node.path, node.words())
+	}
+	script.WriteString("\tesac\n}\n\n")
+	fmt.Fprintf(&script, "complete -o default -F _%s_completion %s\n", completion.functionId, completion.cliName)
+	return script.String()
+}
+
+func (completion *appCompletion) zshScript() string {
+	var script strings.Builder
+	fallback := "_" + completion.functionId + "_fallback"
+	fmt.Fprintf(&script, "# zsh completion for the %s plugin of %s\n", completion.appName, completion.cliName)
+	fmt.Fprintf(&script, "%s=\"${_comps[%s]}\"\n\n", fallback, completion.cliName)
+	fmt.Fprintf(&script, "_%s_completion() {\n", completion.functionId)
+	fmt.Fprintf(&script, "\tif [[ \"${words[2]}\" != \"%s\" ]]; then\n", completion.appName)
+	fmt.Fprintf(&script, "\t\tif [[ -n \"$%s\" && \"$%s\" != \"_%s_completion\" ]]; then\n\t\t\t\"$%s\" \"$@\"\n\t\tfi\n\t\treturn\n\tfi\n", fallback, fallback, completion.functionId, fallback)
+	// The 'path' variable is reserved in zsh.
+	script.WriteString("\tlocal cmd_path=\"/\" prev=\"${words[CURRENT-1]}\" i\n")
+	script.WriteString("\tfor ((i = 3; i < CURRENT; i++)); do\n\t\tcase \"$cmd_path:${words[i]}\" in\n")
+	for _, transition := range completion.transitions {
+		fmt.Fprintf(&script, "\t\t\"%s:%s\") cmd_path=\"%s\" ;;\n", transition.fromPath, transition.word, transition.toPath)
+	}
+	script.WriteString("\t\tesac\n\tdone\n\tcase \"$cmd_path:$prev\" in\n")
+	for _, node := range completion.nodes {
+		for _, flag := range node.flags {
+			if len(flag.allowedValues) > 0 {
+				fmt.Fprintf(&script, "\t\"%s:--%s\")\n\t\tcompadd -- %s\n\t\treturn\n\t\t;;\n", node.path, flag.name, strings.Join(flag.allowedValues, " "))
+			}
+		}
+	}
+	script.WriteString("\tesac\n\tcase \"$cmd_path\" in\n")
+	for _, node := range completion.nodes {
+		fmt.Fprintf(&script, "\t\"%s\") compadd -- %s ;;\n", node.path, node.words())
+	}
+	script.WriteString("\tesac\n}\n\n")
+	fmt.Fprintf(&script, "compdef _%s_completion %s\n", completion.functionId, completion.cliName)
+	return script.String()
+}
+
+func (completion *appCompletion) fishScript() string {
+	var script strings.Builder
+	pathFunction := "__" + completion.functionId + "_path"
+	fmt.Fprintf(&script, "# fish completion for the %s plugin of %s\n", completion.appName, completion.cliName)
+	fmt.Fprintf(&script, "function %s\n", pathFunction)
+	script.WriteString("\tset -l words (commandline -opc)\n")
+	fmt.Fprintf(&script, "\tif test (count $words) -lt 2; or test \"$words[2]\" != \"%s\"\n\t\treturn 1\n\tend\n", completion.appName)
+	script.WriteString("\tset -e words[1..2]\n\tset -l cmd_path /\n\tfor word in $words\n\t\tswitch \"$cmd_path:$word\"\n")
+	for _, transition := range completion.transitions {
+		fmt.Fprintf(&script, "\t\t\tcase '%s:%s'\n\t\t\t\tset cmd_path '%s'\n", transition.fromPath, transition.word, transition.toPath)
+	}
+	script.WriteString("\t\tend\n\tend\n\techo $cmd_path\nend\n\n")
+	for _, node := range completion.nodes {
+		condition := fmt.Sprintf("test (%s) = '%s'", pathFunction, node.path)
+		if len(node.commands) > 0 {
+			fmt.Fprintf(&script, "complete -c %s -f -n \"%s\" -a '%s'\n", completion.cliName, condition, strings.Join(node.commands, " "))
+		}
+		for _, flag := range node.flags {
+			fmt.Fprintf(&script, "complete -c %s -n \"%s\" -l %s", completion.cliName, condition, flag.name)
+			if len(flag.allowedValues) > 0 {
+				fmt.Fprintf(&script, " -x -a '%s'", strings.Join(flag.allowedValues, " "))
+			} else if flag.takesValue {
+				script.WriteString(" -r")
+			}
+			script.WriteString("\n")
+		}
+	}
+	return script.String()
+}
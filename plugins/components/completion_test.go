@@ -0,0 +1,68 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createCompletionTestApp() App {
+	noop := func(c *Context) error { return nil }
+	return App{
+		Name: "cleanup",
+		Commands: []Command{
+			{
+				Name:    "repos",
+				Aliases: []string{"r"},
+				Subcommands: []Command{
+					{Name: "list", Aliases: []string{"ls"}, Flags: []Flag{EnumFlag{Name: "format", AllowedValues: []string{"json", "table"}}, BoolFlag{Name: "dry-run"}}, Action: noop},
+					{Name: "run", Action: noop},
+				},
+			},
+			{Name: "status", Action: noop},
+		},
+	}
+}
+
+func TestNewAppCompletion(t *testing.T) {
+	completion := newAppCompletion(createCompletionTestApp())
+	assert.Contains(t, completion.transitions, completionTransition{fromPath: "/", word: "r", toPath: "/repos"})
+	assert.Contains(t, completion.transitions, completionTransition{fromPath: "/repos", word: "ls", toPath: "/repos/list"})
+	paths := make(map[string]completionNode)
+	for _, node := range completion.nodes {
+		paths[node.path] = node
+	}
+	assert.Len(t, paths, 5)
+	assert.Equal(t, "repos r status --help", paths["/"].words())
+	assert.Equal(t, "list ls run --help", paths["/repos"].words())
+	assert.Equal(t, []completionFlag{{name: "format", takesValue: true, allowedValues: []string{"json", "table"}}, {name: "dry-run"}, {name: "help"}}, paths["/repos/list"].flags)
+}
+
+func TestGenerateCompletionScript(t *testing.T) {
+	app := createCompletionTestApp()
+	for _, shell := range CompletionShells {
+		script, err := GenerateCompletionScript(app, shell)
+		assert.NoError(t, err)
+		assert.Contains(t, script, "/repos/list")
+		assert.Contains(t, script, "json table")
+	}
+	_, err := GenerateCompletionScript(app, "powershell")
+	assert.ErrorContains(t, err, "unsupported shell")
+}
+
+func TestConvertSubcommands(t *testing.T) {
+	var ran string
+	app := createCompletionTestApp()
+	app.Commands[0].Subcommands[0].Action = func(c *Context) error {
+		ran = "list " + c.GetStringFlagValue("format")
+		return nil
+	}
+	converted, err := ConvertApp(app)
+	assert.NoError(t, err)
+	assert.NoError(t, converted.Run([]string{"cleanup", "r", "ls", "--format=json"}))
+	assert.Equal(t, "list json", ran)
+
+	app.Commands = append(app.Commands, Command{Name: "empty"})
+	_, err = ConvertApp(app)
+	assert.ErrorContains(t, err, "neither an action nor subcommands")
+}
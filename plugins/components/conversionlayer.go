@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/docs/common"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/urfave/cli"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func ConvertApp(jfrogApp App) (*cli.App, error) {
-	var err error
+	err := jfrogApp.Manifest.Validate()
+	if err != nil {
+		return nil, err
+	}
 	app := cli.NewApp()
 	app.Name = jfrogApp.Name
 	app.Description = jfrogApp.Description
@@ -36,12 +43,27 @@ func convertCommands(jfrogApp App) ([]cli.Command, error) {
 	return converted, nil
 }
 
+// Converts the command and its subcommands. The appName is the path of the command's parent, such as "my-plugin my-command".
 func convertCommand(cmd Command, appName string) (cli.Command, error) {
 	convertedFlags, err := convertFlags(cmd)
 	if err != nil {
 		return cli.Command{}, err
 	}
-	return cli.Command{
+	err = validateFlagGroupsDefinition(cmd)
+	if err != nil {
+		return cli.Command{}, err
+	}
+	var subcommands []cli.Command
+	var subcommandNames []string
+	for _, subcommand := range cmd.Subcommands {
+		converted, err := convertCommand(subcommand, appName+" "+cmd.Name)
+		if err != nil {
+			return cli.Command{}, err
+		}
+		subcommands = append(subcommands, converted)
+		subcommandNames = append(subcommandNames, subcommand.Name)
+	}
+	converted := cli.Command{
 		Name:            cmd.Name,
 		Flags:           convertedFlags,
 		Aliases:         cmd.Aliases,
@@ -49,15 +71,25 @@ func convertCommand(cmd Command, appName string) (cli.Command, error) {
 		HelpName:        common.CreateUsage(appName+" "+cmd.Name, cmd.Description, []string{createCommandUsage(cmd, appName)}),
 		UsageText:       createArgumentsSummary(cmd),
 		ArgsUsage:       createEnvVarsSummary(cmd),
-		BashComplete:    common.CreateBashCompletionFunc(),
+		BashComplete:    common.CreateBashCompletionFunc(subcommandNames...),
 		SkipFlagParsing: cmd.SkipFlagParsing,
+		Subcommands:     subcommands,
+	}
+	// A command with subcommands may have no action of its own, in which case its help is shown.
+	if cmd.Action != nil {
 		// Passing any other interface than 'cli.ActionFunc' will fail the command.
-		Action: getActionFunc(cmd),
-	}, nil
+		converted.Action = getActionFunc(cmd)
+	} else if len(cmd.Subcommands) == 0 {
+		return cli.Command{}, fmt.Errorf("the command '%s' has neither an action nor subcommands", cmd.Name)
+	}
+	return converted, nil
 }
 
 func createCommandUsage(cmd Command, appName string) string {
 	usage := fmt.Sprintf(coreutils.GetCliExecutableName()+" %s %s", appName, cmd.Name)
+	if len(cmd.Subcommands) > 0 {
+		usage += " <command>"
+	}
 	if len(cmd.Flags) > 0 {
 		usage += " [command options]"
 	}
@@ -110,27 +142,51 @@ func convertFlags(cmd Command) ([]cli.Flag, error) {
 }
 
 func convertByType(flag Flag) (cli.Flag, error) {
-	if f, ok := flag.(StringFlag); ok {
+	switch f := flag.(type) {
+	case StringFlag:
 		return convertStringFlag(f), nil
-	}
-	if f, ok := flag.(BoolFlag); ok {
+	case BoolFlag:
 		return convertBoolFlag(f), nil
+	case IntFlag:
+		defaultValue := ""
+		if f.DefaultValue != 0 {
+			defaultValue = strconv.Itoa(f.DefaultValue)
+		}
+		return createStringFlag(f.Name, f.Description, defaultValue, f.Mandatory, f.EnvVar), nil
+	case DurationFlag:
+		defaultValue := ""
+		if f.DefaultValue != 0 {
+			defaultValue = f.DefaultValue.String()
+		}
+		return createStringFlag(f.Name, f.Description, defaultValue, f.Mandatory, f.EnvVar), nil
+	case StringSliceFlag:
+		return createStringFlag(f.Name, f.Description, strings.Join(f.DefaultValue, ","), f.Mandatory, f.EnvVar), nil
+	case EnumFlag:
+		return convertEnumFlag(f)
+	case FilePathFlag:
+		return createStringFlag(f.Name, f.Description, f.DefaultValue, f.Mandatory, f.EnvVar), nil
 	}
 	return nil, fmt.Errorf("flag '%s' does not match any known flag type", flag.GetName())
 }
 
 func convertStringFlag(f StringFlag) cli.Flag {
+	return createStringFlag(f.Name, f.Description, f.DefaultValue, f.Mandatory, f.EnvVar)
+}
+
+// All the flags with values are converted to string flags, whose values are parsed by their types when the command runs.
+func createStringFlag(name, description, defaultValue string, mandatory bool, envVar string) cli.StringFlag {
 	stringFlag := cli.StringFlag{
-		Name:  f.Name,
-		Usage: f.Description + "` `",
+		Name:   name,
+		Usage:  description + "` `",
+		EnvVar: envVar,
 	}
 	// If default is set, add its value and return.
-	if f.DefaultValue != "" {
-		stringFlag.Usage = fmt.Sprintf("[Default: %s] %s", f.DefaultValue, stringFlag.Usage)
+	if defaultValue != "" {
+		stringFlag.Usage = fmt.Sprintf("[Default: %s] %s", defaultValue, stringFlag.Usage)
 		return stringFlag
 	}
 	// Otherwise, mark as mandatory/optional accordingly.
-	if f.Mandatory {
+	if mandatory {
 		stringFlag.Usage = "[Mandatory] " + stringFlag.Usage
 	} else {
 		stringFlag.Usage = "[Optional] " + stringFlag.Usage
@@ -138,17 +194,46 @@ func convertStringFlag(f StringFlag) cli.Flag {
 	return stringFlag
 }
 
+func convertEnumFlag(f EnumFlag) (cli.Flag, error) {
+	if len(f.AllowedValues) == 0 {
+		return nil, fmt.Errorf("enum flag '%s' has no allowed values", f.Name)
+	}
+	if f.DefaultValue != "" && !isAllowedValue(f.DefaultValue, f.AllowedValues) {
+		return nil, fmt.Errorf("the default value '%s' of the enum flag '%s' is not one of its allowed values", f.DefaultValue, f.Name)
+	}
+	description := fmt.Sprintf("%s Allowed values: %s.", f.Description, strings.Join(f.AllowedValues, ", "))
+	return createStringFlag(f.Name, description, f.DefaultValue, f.Mandatory, f.EnvVar), nil
+}
+
 func convertBoolFlag(f BoolFlag) cli.Flag {
 	if f.DefaultValue {
 		return cli.BoolTFlag{
-			Name:  f.Name,
-			Usage: "[Default: true] " + f.Description + "` `",
+			Name:   f.Name,
+			Usage:  "[Default: true] " + f.Description + "` `",
+			EnvVar: f.EnvVar,
 		}
 	}
 	return cli.BoolFlag{
-		Name:  f.Name,
-		Usage: "[Default: false] " + f.Description + "` `",
+		Name:   f.Name,
+		Usage:  "[Default: false] " + f.Description + "` `",
+		EnvVar: f.EnvVar,
+	}
+}
+
+// Verify that the flag groups of the command reference its flags only.
+func validateFlagGroupsDefinition(cmd Command) error {
+	names := make(map[string]bool)
+	for _, flag := range cmd.Flags {
+		names[flag.GetName()] = true
 	}
+	for _, group := range append(cmd.MutuallyExclusiveFlags, cmd.RequiredTogetherFlags...) {
+		for _, name := range group {
+			if !names[name] {
+				return fmt.Errorf("the flags group of the '%s' command references the unknown flag '%s'", cmd.Name, name)
+			}
+		}
+	}
+	return nil
 }
 
 // Wrap the base's ActionFunc with our own, while retrieving needed information from the Context.
@@ -160,47 +245,168 @@ func getActionFunc(cmd Command) cli.ActionFunc {
 		if err != nil {
 			return err
 		}
+		err = validateFlagGroups(pluginContext, cmd)
+		if err != nil {
+			return err
+		}
 		return cmd.Action(pluginContext)
 	}
 }
 
-func fillFlagMaps(c *Context, baseContext *cli.Context, originalFlags []Flag) error {
+func fillFlagMaps(c *Context, baseContext *cli.Context, originalFlags []Flag) (err error) {
 	c.stringFlags = make(map[string]string)
 	c.boolFlags = make(map[string]bool)
+	c.intFlags = make(map[string]int)
+	c.durationFlags = make(map[string]time.Duration)
+	c.stringSliceFlags = make(map[string][]string)
+	c.setFlags = make(map[string]bool)
 
 	// Loop over all plugin's known flags.
 	for _, flag := range originalFlags {
-		if stringFlag, ok := flag.(StringFlag); ok {
-			finalValue, err := getValueForStringFlag(stringFlag, baseContext.String(stringFlag.Name))
-			if err != nil {
-				return err
-			}
-			c.stringFlags[stringFlag.Name] = finalValue
-			continue
+		c.setFlags[flag.GetName()] = baseContext.IsSet(flag.GetName())
+		receivedValue := baseContext.String(flag.GetName())
+		switch f := flag.(type) {
+		case StringFlag:
+			c.stringFlags[f.Name], err = getValueForStringFlag(f, receivedValue)
+		case BoolFlag:
+			c.boolFlags[f.Name] = getValueForBoolFlag(f, baseContext)
+		case IntFlag:
+			c.intFlags[f.Name], err = getValueForIntFlag(f, receivedValue)
+		case DurationFlag:
+			c.durationFlags[f.Name], err = getValueForDurationFlag(f, receivedValue)
+		case StringSliceFlag:
+			c.stringSliceFlags[f.Name], err = getValueForStringSliceFlag(f, receivedValue)
+		case EnumFlag:
+			c.stringFlags[f.Name], err = getValueForEnumFlag(f, receivedValue)
+		case FilePathFlag:
+			c.stringFlags[f.Name], err = getValueForFilePathFlag(f, receivedValue)
 		}
-
-		if boolFlag, ok := flag.(BoolFlag); ok {
-			c.boolFlags[boolFlag.Name] = getValueForBoolFlag(boolFlag, baseContext)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
 func getValueForStringFlag(f StringFlag, receivedValue string) (finalValue string, err error) {
+	return getFlagValue(f.Name, f.DefaultValue, f.Mandatory, receivedValue)
+}
+
+func getFlagValue(name, defaultValue string, mandatory bool, receivedValue string) (string, error) {
 	if receivedValue != "" {
 		return receivedValue, nil
 	}
 	// Empty but has a default value defined.
-	if f.DefaultValue != "" {
-		return f.DefaultValue, nil
+	if defaultValue != "" {
+		return defaultValue, nil
 	}
 	// Empty but mandatory.
-	if f.Mandatory {
-		return "", errors.New("Mandatory flag '" + f.Name + "' is missing")
+	if mandatory {
+		return "", errors.New("Mandatory flag '" + name + "' is missing")
 	}
 	return "", nil
 }
 
+func getValueForIntFlag(f IntFlag, receivedValue string) (int, error) {
+	value, err := getFlagValue(f.Name, "", f.Mandatory, receivedValue)
+	if err != nil || value == "" {
+		return f.DefaultValue, err
+	}
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("the value of the '%s' flag should be an integer, but received '%s'", f.Name, value)
+	}
+	return intValue, nil
+}
+
+func getValueForDurationFlag(f DurationFlag, receivedValue string) (time.Duration, error) {
+	value, err := getFlagValue(f.Name, "", f.Mandatory, receivedValue)
+	if err != nil || value == "" {
+		return f.DefaultValue, err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("the value of the '%s' flag should be a duration such as 30s or 1h30m, but received '%s'", f.Name, value)
+	}
+	return duration, nil
+}
+
+func getValueForStringSliceFlag(f StringSliceFlag, receivedValue string) ([]string, error) {
+	value, err := getFlagValue(f.Name, "", f.Mandatory, receivedValue)
+	if err != nil || value == "" {
+		return f.DefaultValue, err
+	}
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values, nil
+}
+
+func getValueForEnumFlag(f EnumFlag, receivedValue string) (string, error) {
+	value, err := getFlagValue(f.Name, f.DefaultValue, f.Mandatory, receivedValue)
+	if err != nil || value == "" {
+		return value, err
+	}
+	if !isAllowedValue(value, f.AllowedValues) {
+		return "", fmt.Errorf("the value of the '%s' flag should be one of: %s, but received '%s'", f.Name, strings.Join(f.AllowedValues, ", "), value)
+	}
+	return value, nil
+}
+
+func getValueForFilePathFlag(f FilePathFlag, receivedValue string) (string, error) {
+	value, err := getFlagValue(f.Name, f.DefaultValue, f.Mandatory, receivedValue)
+	if err != nil || value == "" {
+		return value, err
+	}
+	value = clientutils.ReplaceTildeWithUserHome(value)
+	if f.MustExist {
+		if _, err = os.Stat(value); err != nil {
+			return "", fmt.Errorf("the path '%s' of the '%s' flag does not exist", value, f.Name)
+		}
+	}
+	return value, nil
+}
+
+func isAllowedValue(value string, allowedValues []string) bool {
+	for _, allowed := range allowedValues {
+		if value == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func validateFlagGroups(c *Context, cmd Command) error {
+	for _, group := range cmd.MutuallyExclusiveFlags {
+		var set []string
+		for _, name := range group {
+			if c.IsFlagSet(name) {
+				set = append(set, "--"+name)
+			}
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("the flags %s cannot be used together", strings.Join(set, ", "))
+		}
+	}
+	for _, group := range cmd.RequiredTogetherFlags {
+		var set, unset []string
+		for _, name := range group {
+			if c.IsFlagSet(name) {
+				set = append(set, "--"+name)
+			} else {
+				unset = append(unset, "--"+name)
+			}
+		}
+		if len(set) > 0 && len(unset) > 0 {
+			return fmt.Errorf("the flags %s must be used together with %s", strings.Join(set, ", "), strings.Join(unset, ", "))
+		}
+	}
+	return nil
+}
+
 func getValueForBoolFlag(f BoolFlag, baseContext *cli.Context) bool {
 	if f.DefaultValue {
 		return baseContext.BoolT(f.Name)
@@ -3,8 +3,12 @@ package components
 import (
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestCreateCommandUsage(t *testing.T) {
@@ -214,3 +218,80 @@ func TestGetValueForStringFlag(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, finalValue, expected)
 }
+
+func TestTypedFlags(t *testing.T) {
+	existingFile := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(existingFile, nil, 0600))
+	var context *Context
+	cmd := Command{
+		Name: "test-command",
+		Flags: []Flag{
+			IntFlag{Name: "threads", DefaultValue: 3},
+			IntFlag{Name: "retries", EnvVar: "TEST_PLUGIN_RETRIES"},
+			DurationFlag{Name: "timeout", DefaultValue: time.Minute},
+			StringSliceFlag{Name: "repos"},
+			EnumFlag{Name: "format", AllowedValues: []string{"json", "table"}, DefaultValue: "table"},
+			FilePathFlag{Name: "spec", MustExist: true},
+		},
+		Action: func(c *Context) error {
+			context = c
+			return nil
+		},
+	}
+	defer testsutils.SetEnvWithCallbackAndAssert(t, "TEST_PLUGIN_RETRIES", "5")()
+
+	assert.NoError(t, runTestCommand(t, cmd, "--timeout=90s", "--repos=a, b,,c", "--format=json", "--spec="+existingFile))
+	assert.Equal(t, 3, context.GetIntFlagValue("threads"))
+	assert.Equal(t, 5, context.GetIntFlagValue("retries"))
+	assert.True(t, context.IsFlagSet("retries"))
+	assert.False(t, context.IsFlagSet("threads"))
+	assert.Equal(t, 90*time.Second, context.GetDurationFlagValue("timeout"))
+	assert.Equal(t, []string{"a", "b", "c"}, context.GetStringSliceFlagValue("repos"))
+	assert.Equal(t, "json", context.GetStringFlagValue("format"))
+	assert.Equal(t, existingFile, context.GetStringFlagValue("spec"))
+
+	assert.NoError(t, runTestCommand(t, cmd))
+	assert.Equal(t, time.Minute, context.GetDurationFlagValue("timeout"))
+	assert.Equal(t, "table", context.GetStringFlagValue("format"))
+	assert.Empty(t, context.GetStringSliceFlagValue("repos"))
+
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--threads=many"), "should be an integer")
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--timeout=soon"), "should be a duration")
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--format=xml"), "should be one of: json, table")
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--spec="+filepath.Join(t.TempDir(), "missing")), "does not exist")
+}
+
+func TestFlagGroups(t *testing.T) {
+	cmd := Command{
+		Name: "test-command",
+		Flags: []Flag{
+			StringFlag{Name: "user"},
+			StringFlag{Name: "password"},
+			StringFlag{Name: "access-token"},
+		},
+		MutuallyExclusiveFlags: [][]string{{"user", "access-token"}},
+		RequiredTogetherFlags:  [][]string{{"user", "password"}},
+		Action:                 func(c *Context) error { return nil },
+	}
+	assert.NoError(t, runTestCommand(t, cmd, "--user=admin", "--password=password"))
+	assert.NoError(t, runTestCommand(t, cmd, "--access-token=token"))
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--user=admin", "--password=password", "--access-token=token"), "cannot be used together")
+	assert.ErrorContains(t, runTestCommand(t, cmd, "--user=admin"), "--user must be used together with --password")
+
+	cmd.RequiredTogetherFlags = [][]string{{"user", "unknown"}}
+	_, err := ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.ErrorContains(t, err, "unknown flag 'unknown'")
+}
+
+func TestConvertEnumFlagInvalidDefault(t *testing.T) {
+	_, err := convertByType(EnumFlag{Name: "format", AllowedValues: []string{"json"}, DefaultValue: "xml"})
+	assert.Error(t, err)
+	_, err = convertByType(EnumFlag{Name: "format"})
+	assert.Error(t, err)
+}
+
+func runTestCommand(t *testing.T, cmd Command, args ...string) error {
+	app, err := ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.NoError(t, err)
+	return app.Run(append([]string{"test-app", cmd.Name}, args...))
+}
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The services of the JFrog Platform a plugin may require
+type Service string
+
+const (
+	Artifactory    Service = "artifactory"
+	Xray           Service = "xray"
+	Distribution   Service = "distribution"
+	Pipelines      Service = "pipelines"
+	Access         Service = "access"
+	MissionControl Service = "missionControl"
+)
+
+var services = []Service{Artifactory, Xray, Distribution, Pipelines, Access, MissionControl}
+
+// Manifest declares the requirements of a plugin, which are validated by the CLI when the plugin is installed and run.
+type Manifest struct {
+	// The minimum version of jfrog-cli-core the plugin is compatible with
+	MinCoreVersion string `json:"minCoreVersion,omitempty"`
+	// The services the configured server must provide
+	Services []Service `json:"services,omitempty"`
+	// Other plugins which must be installed
+	Dependencies []PluginDependency `json:"dependencies,omitempty"`
+	// The platforms the plugin supports, as <os> or <os>-<arch>, such as "linux" or "darwin-arm64". All platforms are supported if empty.
+	Platforms []string `json:"platforms,omitempty"`
+}
+
+type PluginDependency struct {
+	Name string `json:"name"`
+	// The minimum version of the plugin. Any version is allowed if empty.
+	MinVersion string `json:"minVersion,omitempty"`
+}
+
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+){0,2}([-+][0-9A-Za-z.-]+)?$`)
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)?$`)
+
+// Validate checks the syntax of the manifest. The requirements are validated against the environment by the CLI.
+func (manifest *Manifest) Validate() error {
+	var problems []string
+	if manifest.MinCoreVersion != "" && !versionPattern.MatchString(manifest.MinCoreVersion) {
+		problems = append(problems, fmt.Sprintf("invalid minimum core version '%s'", manifest.MinCoreVersion))
+	}
+	for _, service := range manifest.Services {
+		if !isKnownService(service) {
+			problems = append(problems, fmt.Sprintf("unknown service '%s'. The supported services are: %s", service, joinServices(services)))
+		}
+	}
+	for _, dependency := range manifest.Dependencies {
+		if dependency.Name == "" {
+			problems = append(problems, "a dependency without a name")
+		}
+		if dependency.MinVersion != "" && !versionPattern.MatchString(dependency.MinVersion) {
+			problems = append(problems, fmt.Sprintf("invalid minimum version '%s' of the dependency '%s'", dependency.MinVersion, dependency.Name))
+		}
+	}
+	for _, platform := range manifest.Platforms {
+		if !platformPattern.MatchString(platform) {
+			problems = append(problems, fmt.Sprintf("invalid platform '%s'. Platforms should be <os> or <os>-<arch>, such as linux-amd64", platform))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid plugin manifest:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// SupportsPlatform returns true if the manifest allows running the plugin on the given OS and architecture.
+func (manifest *Manifest) SupportsPlatform(goos, goarch string) bool {
+	if len(manifest.Platforms) == 0 {
+		return true
+	}
+	for _, platform := range manifest.Platforms {
+		if platform == goos || platform == goos+"-"+goarch {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateServices checks that the URLs of all the required services are configured.
+func (manifest *Manifest) ValidateServices(serviceUrls map[Service]string) error {
+	var missing []Service
+	for _, service := range manifest.Services {
+		if serviceUrls[service] == "" {
+			missing = append(missing, service)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the plugin requires the following services, whose URLs are not configured for the server: %s", joinServices(missing))
+	}
+	return nil
+}
+
+func isKnownService(service Service) bool {
+	for _, known := range services {
+		if service == known {
+			return true
+		}
+	}
+	return false
+}
+
+func joinServices(services []Service) string {
+	var names []string
+	for _, service := range services {
+		names = append(names, string(service))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,13 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServices(t *testing.T) {
+	manifest := &Manifest{Services: []Service{Artifactory, Xray}}
+	assert.NoError(t, manifest.ValidateServices(map[Service]string{Artifactory: "https://acme.jfrog.io/artifactory/", Xray: "https://acme.jfrog.io/xray/"}))
+	assert.ErrorContains(t, manifest.ValidateServices(map[Service]string{Artifactory: "https://acme.jfrog.io/artifactory/"}), "xray")
+}
@@ -5,6 +5,8 @@ type App struct {
 	Description string
 	Version     string
 	Commands    []Command
+	// The requirements of the plugin, validated by the CLI when the plugin is installed and run
+	Manifest Manifest
 }
 
 type Command struct {
@@ -16,11 +18,19 @@ type Command struct {
 	EnvVars         []EnvVar
 	Action          ActionFunc
 	SkipFlagParsing bool
+	// Nested commands, such as 'list' in 'my-plugin repos list'
+	Subcommands []Command
+	// Groups of flags, of which at most one may be set
+	MutuallyExclusiveFlags [][]string
+	// Groups of flags, which must be either all set or all unset
+	RequiredTogetherFlags [][]string
 }
 
 type PluginSignature struct {
-	Name  string `json:"name,omitempty"`
-	Usage string `json:"usage,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Usage    string    `json:"usage,omitempty"`
+	Version  string    `json:"version,omitempty"`
+	Manifest *Manifest `json:"manifest,omitempty"`
 	// Only used internally in the CLI.
 	ExecutablePath string `json:"executablePath,omitempty"`
 }
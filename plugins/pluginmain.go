@@ -55,11 +55,13 @@ func PluginMain(jfrogApp components.App) {
 	cli.CommandHelpTemplate = commandHelpTemplate
 	cli.AppHelpTemplate = appHelpTemplate
 
+	addCompletionCommand(&jfrogApp)
 	baseApp, err := components.ConvertApp(jfrogApp)
 	if err != nil {
 		coreutils.ExitOnErr(err)
 	}
-	addHiddenPluginSignatureCommand(baseApp)
+	addHiddenPluginSignatureCommand(baseApp, jfrogApp.Manifest)
+	pluginManifest = jfrogApp.Manifest
 
 	args := os.Args
 	err = baseApp.Run(args)
@@ -12,15 +12,17 @@ const SignatureCommandName = "hidden-plugin-signature"
 
 // Adds a hidden command to every built plugin.
 // The command will later be used by the CLI to retrieve the plugin's signature to show in the CLI's help command.
-func addHiddenPluginSignatureCommand(baseApp *cli.App) {
+func addHiddenPluginSignatureCommand(baseApp *cli.App, manifest components.Manifest) {
 	cmd := cli.Command{
 		Name:     SignatureCommandName,
 		Hidden:   true,
 		HideHelp: true,
 		Action: func(c *cli.Context) error {
 			signature := components.PluginSignature{
-				Name:  baseApp.Name,
-				Usage: baseApp.Description,
+				Name:     baseApp.Name,
+				Usage:    baseApp.Description,
+				Version:  baseApp.Version,
+				Manifest: &manifest,
 			}
 			content, err := json.Marshal(signature)
 			if err == nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayAuth "github.com/jfrog/jfrog-client-go/xray/auth"
 	"os"
@@ -40,28 +41,11 @@ func IsServerConfExists() (bool, error) {
 }
 
 // Returns the configured server or error if the server id was not found.
-// If defaultOrEmpty: return empty details if no configurations found, or default conf for empty serverId.
+// The server is resolved from the project servers file, the environment variables and the global config, by this order.
+// If defaultOrEmpty: return empty details if no configurations found, or the server selected by the profile, the project or the default conf for empty serverId.
 // Exclude refreshable tokens when working with external tools (build tools, curl, etc.) or when sending requests not via ArtifactoryHttpClient.
 func GetSpecificConfig(serverId string, defaultOrEmpty bool, excludeRefreshableTokens bool) (*ServerDetails, error) {
-	configs, err := GetAllServersConfigs()
-	if err != nil {
-		return nil, err
-	}
-
-	if defaultOrEmpty {
-		if len(configs) == 0 {
-			return new(ServerDetails), nil
-		}
-		if len(serverId) == 0 {
-			details, err := GetDefaultConfiguredConf(configs)
-			if excludeRefreshableTokens {
-				excludeRefreshableTokensFromDetails(details)
-			}
-			return details, errorutils.CheckError(err)
-		}
-	}
-
-	details, err := getServerConfByServerId(serverId, configs)
+	details, _, err := getLayeredConfig(serverId, defaultOrEmpty)
 	if err != nil {
 		return nil, err
 	}
@@ -100,17 +84,15 @@ func GetDefaultConfiguredConf(configs []*ServerDetails) (*ServerDetails, error)
 
 // Returns default artifactory conf. Returns nil if default server doesn't exists.
 func GetDefaultServerConf() (*ServerDetails, error) {
-	configurations, err := GetAllServersConfigs()
+	details, _, err := getLayeredConfig("", true)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(configurations) == 0 {
+	if details.IsEmpty() {
 		log.Debug("No servers were configured.")
-		return nil, err
+		return nil, nil
 	}
-
-	return GetDefaultConfiguredConf(configurations)
+	return details, nil
 }
 
 // Returns the configured server or error if the server id not found
@@ -494,6 +476,8 @@ type ConfigV5 struct {
 	Servers []*ServerDetails `json:"servers"`
 	Version string           `json:"version,omitempty"`
 	Enc     bool             `json:"enc,omitempty"`
+	// If set, the config keeps references to the secrets kept in this secret store, instead of the secrets
+	SecretStore SecretStoreType `json:"secretStore,omitempty"`
 }
 
 // This struct is suitable for versions 1, 2, 3 and 4.
@@ -563,6 +547,9 @@ type ServerDetails struct {
 	ArtifactoryTokenRefreshInterval int    `json:"tokenRefreshInterval,omitempty"`
 	ClientCertPath                  string `json:"clientCertPath,omitempty"`
 	ClientCertKeyPath               string `json:"clientCertKeyPath,omitempty"`
+	OidcProviderName                string `json:"oidcProviderName,omitempty"`
+	OidcTokenFile                   string `json:"oidcTokenFile,omitempty"`
+	OidcTokenEnv                    string `json:"oidcTokenEnv,omitempty"`
 	ServerId                        string `json:"serverId,omitempty"`
 	IsDefault                       bool   `json:"isDefault,omitempty"`
 	InsecureTls                     bool   `json:"-"`
@@ -694,10 +681,15 @@ func (serverDetails *ServerDetails) CreateAccessAuthConfig() (auth.ServiceDetail
 
 func (serverDetails *ServerDetails) createAuthConfig(details auth.ServiceDetails) (auth.ServiceDetails, error) {
 	details.SetSshUrl(serverDetails.SshUrl)
+	// Attach the correlation ID of the CLI invocation to all the requests, to match them with the CLI's log.
+	details.AppendPreRequestFunction(correlationIdPreRequestInterceptor)
 	details.SetAccessToken(serverDetails.AccessToken)
 	// If refresh token is not empty, set a refresh handler and skip other credentials.
 	// First we check access's token, if empty we check artifactory's token.
-	if serverDetails.RefreshToken != "" {
+	// If an OIDC provider is configured, the access token is acquired by exchanging an OIDC ID token before the requests.
+	if serverDetails.OidcProviderName != "" {
+		details.AppendPreRequestFunction(serverDetails.createOidcTokenExchangePreRequestInterceptor())
+	} else if serverDetails.RefreshToken != "" {
 		// Save serverId for refreshing if needed. If empty serverId is saved, default will be used.
 		tokenRefreshServerId = serverDetails.ServerId
 		details.AppendPreRequestFunction(AccessTokenRefreshPreRequestInterceptor)
@@ -716,6 +708,18 @@ func (serverDetails *ServerDetails) createAuthConfig(details auth.ServiceDetails
 	return details, nil
 }
 
+func correlationIdPreRequestInterceptor(_ *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+	correlationId := cliLog.GetCorrelationId()
+	if httpClientDetails.Headers[cliLog.CorrelationIdHeader] == correlationId {
+		return nil
+	}
+	// The headers are copied rather than modified, since the same details may be shared by requests running concurrently.
+	headers := utils.CopyMap(httpClientDetails.Headers)
+	headers[cliLog.CorrelationIdHeader] = correlationId
+	httpClientDetails.Headers = headers
+	return nil
+}
+
 func (missionControlDetails *MissionControlDetails) GetAccessToken() string {
 	return missionControlDetails.AccessToken
 }
@@ -365,3 +365,11 @@ func assertCertsMigration(t *testing.T) {
 	// Verify only the certs were moved
 	assert.Len(t, files, 2)
 }
+
+func TestCorrelationIdPreRequestInterceptor(t *testing.T) {
+	authConfig, err := (&ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"}).CreateArtAuthConfig()
+	assert.NoError(t, err)
+	httpClientDetails := authConfig.CreateHttpClientDetails()
+	assert.NoError(t, authConfig.RunPreRequestFunctions(&httpClientDetails))
+	assert.Equal(t, log.GetCorrelationId(), httpClientDetails.Headers[log.CorrelationIdHeader])
+}
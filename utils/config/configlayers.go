@@ -0,0 +1,381 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+// The layers a server configuration is resolved from, by their precedence:
+// 1. The project servers file - .jfrog/servers.yaml in the working directory or in one of its parents.
+// 2. Environment variables overriding single fields - JFROG_CLI_SERVER_<server ID>_<field>, such as JFROG_CLI_SERVER_STAGING_ACCESS_TOKEN.
+// 3. The global config file in the JFrog CLI home directory.
+type ConfigLayer string
+
+const (
+	ProjectConfigLayer ConfigLayer = "project"
+	EnvConfigLayer     ConfigLayer = "env"
+	GlobalConfigLayer  ConfigLayer = "global"
+
+	projectServersFileVersion = "1"
+	// Prefix of the values referencing environment variables, such as env:JFROG_STAGING_TOKEN
+	envReferencePrefix = "env:"
+	// Only environment variables with this prefix may be referenced, so that the project can't read unrelated secrets
+	envReferenceVarPrefix = "JFROG_"
+	serverIdField      = "serverId"
+)
+
+var projectServersFilePath = filepath.Join(".jfrog", "servers.yaml")
+
+// ConfigSource is the layer a field of a server configuration was resolved from.
+type ConfigSource struct {
+	Layer ConfigLayer
+	// The file or the environment variable of the value
+	Location string
+}
+
+func (source ConfigSource) String() string {
+	if source.Location == "" {
+		return string(source.Layer)
+	}
+	return string(source.Layer) + " (" + source.Location + ")"
+}
+
+// Maps the fields of a server configuration to the layers they were resolved from.
+type ServerConfigSources map[string]ConfigSource
+
+// A field of the server configuration, which may be set by the project servers file and the environment variables.
+type serverField struct {
+	name string
+	// Secrets may appear in the project servers file only as references
+	secret bool
+	value  func(details *ServerDetails) *string
+}
+
+// The fields by the order they are printed
+var serverFields = []serverField{
+	{"url", false, func(details *ServerDetails) *string { return &details.Url }},
+	{"artifactoryUrl", false, func(details *ServerDetails) *string { return &details.ArtifactoryUrl }},
+	{"distributionUrl", false, func(details *ServerDetails) *string { return &details.DistributionUrl }},
+	{"xrayUrl", false, func(details *ServerDetails) *string { return &details.XrayUrl }},
+	{"missionControlUrl", false, func(details *ServerDetails) *string { return &details.MissionControlUrl }},
+	{"pipelinesUrl", false, func(details *ServerDetails) *string { return &details.PipelinesUrl }},
+	{"accessUrl", false, func(details *ServerDetails) *string { return &details.AccessUrl }},
+	{"user", false, func(details *ServerDetails) *string { return &details.User }},
+	{"password", true, func(details *ServerDetails) *string { return &details.Password }},
+	{"accessToken", true, func(details *ServerDetails) *string { return &details.AccessToken }},
+	{"refreshToken", true, func(details *ServerDetails) *string { return &details.RefreshToken }},
+	{"sshKeyPath", false, func(details *ServerDetails) *string { return &details.SshKeyPath }},
+	{"sshPassphrase", true, func(details *ServerDetails) *string { return &details.SshPassphrase }},
+	{"clientCertPath", false, func(details *ServerDetails) *string { return &details.ClientCertPath }},
+	{"clientCertKeyPath", false, func(details *ServerDetails) *string { return &details.ClientCertKeyPath }},
+	{"oidcProviderName", false, func(details *ServerDetails) *string { return &details.OidcProviderName }},
+	{"oidcTokenFile", false, func(details *ServerDetails) *string { return &details.OidcTokenFile }},
+	{"oidcTokenEnv", false, func(details *ServerDetails) *string { return &details.OidcTokenEnv }},
+}
+
+// ProjectServersFile is the content of the .jfrog/servers.yaml file of a project.
+// Secrets must be references to environment variables prefixed with JFROG_, so that the file can be committed with the project.
+// The URLs of a server may be set only if none of its credentials are set, so that no credentials are ever sent to the project URLs.
+//
+//	version: 1
+//	defaultServerId: staging
+//	profiles:
+//	  prod: production-server
+//	servers:
+//	  - serverId: staging
+//	    accessToken: env:JFROG_STAGING_TOKEN
+//	  - serverId: public
+//	    url: https://public.example.com/
+type ProjectServersFile struct {
+	Version string `yaml:"version"`
+	// The server used by the project, when no server ID or profile is provided
+	DefaultServerId string `yaml:"defaultServerId,omitempty"`
+	// Maps profile names to server IDs
+	Profiles map[string]string `yaml:"profiles,omitempty"`
+	// The fields of the servers. Fields missing here are resolved from the other layers.
+	Servers []map[string]string `yaml:"servers,omitempty"`
+	path    string
+}
+
+// Returns the project servers file in the working directory or in one of its parents, or nil if none exists.
+func loadProjectServersFile() (*ProjectServersFile, error) {
+	projectDir, exists, err := fileutils.FindUpstream(projectServersFilePath, fileutils.File)
+	if err != nil || !exists {
+		return nil, errorutils.CheckError(err)
+	}
+	return readProjectServersFile(filepath.Join(projectDir, projectServersFilePath))
+}
+
+func readProjectServersFile(path string) (*ProjectServersFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	projectFile := &ProjectServersFile{path: path}
+	if err = yaml.UnmarshalStrict(content, projectFile); err != nil {
+		return nil, errorutils.CheckErrorf("failed parsing the project servers file %s: %s", path, err.Error())
+	}
+	if err = projectFile.validate(); err != nil {
+		return nil, err
+	}
+	log.Debug("Using the project servers file " + path)
+	return projectFile, nil
+}
+
+func (projectFile *ProjectServersFile) validate() error {
+	if projectFile.Version != projectServersFileVersion {
+		return errorutils.CheckErrorf("unsupported version '%s' of the project servers file %s. The supported version is %s", projectFile.Version, projectFile.path, projectServersFileVersion)
+	}
+	for _, server := range projectFile.Servers {
+		if server[serverIdField] == "" {
+			return errorutils.CheckErrorf("a server without a %s was found in the project servers file %s", serverIdField, projectFile.path)
+		}
+		for name, value := range server {
+			if name == serverIdField {
+				continue
+			}
+			field := getServerField(name)
+			if field == nil {
+				return errorutils.CheckErrorf("unknown field '%s' of the server '%s' in the project servers file %s", name, server[serverIdField], projectFile.path)
+			}
+			isReference := strings.HasPrefix(value, envReferencePrefix)
+			if field.secret && !isReference {
+				return errorutils.CheckErrorf("the %s of the server '%s' in the project servers file %s must be a reference to an environment variable, such as %s%sMY_SECRET", name, server[serverIdField], projectFile.path, envReferencePrefix, envReferenceVarPrefix)
+			}
+			if isReference && !strings.HasPrefix(strings.TrimPrefix(value, envReferencePrefix), envReferenceVarPrefix) {
+				return errorutils.CheckErrorf("the %s of the server '%s' in the project servers file %s references the environment variable %s. Only environment variables prefixed with %s may be referenced", name, server[serverIdField], projectFile.path, strings.TrimPrefix(value, envReferencePrefix), envReferenceVarPrefix)
+			}
+		}
+	}
+	return nil
+}
+
+func (projectFile *ProjectServersFile) getServer(serverId string) map[string]string {
+	if projectFile == nil {
+		return nil
+	}
+	for _, server := range projectFile.Servers {
+		if server[serverIdField] == serverId {
+			return server
+		}
+	}
+	return nil
+}
+
+func (field serverField) isUrl() bool {
+	return strings.HasSuffix(strings.ToLower(field.name), "url")
+}
+
+// Returns true if the field is a secret, a path to a key which authenticates the user, or a source of the OIDC ID token which is exchanged for an access token.
+func (field serverField) isCredential() bool {
+	switch field.name {
+	case "sshKeyPath", "clientCertPath", "clientCertKeyPath", "oidcProviderName", "oidcTokenFile", "oidcTokenEnv":
+		return true
+	}
+	return field.secret
+}
+
+func getServerField(name string) *serverField {
+	for i := range serverFields {
+		if serverFields[i].name == name {
+			return &serverFields[i]
+		}
+	}
+	return nil
+}
+
+// Returns the ID of the server selected by the profile of the shell or by the project, or an empty string if none was selected.
+// The profile is either a profile of the project servers file, or a server ID.
+func (projectFile *ProjectServersFile) getSelectedServerId() (serverId string, source ConfigSource) {
+	if profile := os.Getenv(coreutils.ServerProfile); profile != "" {
+		source = ConfigSource{Layer: EnvConfigLayer, Location: coreutils.ServerProfile}
+		if projectFile != nil && projectFile.Profiles[profile] != "" {
+			return projectFile.Profiles[profile], source
+		}
+		return profile, source
+	}
+	if projectFile != nil && projectFile.DefaultServerId != "" {
+		return projectFile.DefaultServerId, ConfigSource{Layer: ProjectConfigLayer, Location: projectFile.path}
+	}
+	return "", ConfigSource{}
+}
+
+// Returns the name of the environment variable overriding a field of a server, such as JFROG_CLI_SERVER_STAGING_ACCESS_TOKEN.
+func getServerFieldEnvVar(serverId, fieldName string) string {
+	return coreutils.ServerEnvPrefix + toEnvVarName(serverId) + "_" + toEnvVarName(fieldName)
+}
+
+var camelCaseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Converts a server ID or a field name to a part of an environment variable name, such as "accessToken" to "ACCESS_TOKEN".
+func toEnvVarName(name string) string {
+	name = camelCaseBoundary.ReplaceAllString(name, "${1}_${2}")
+	return strings.ToUpper(nonAlphanumeric.ReplaceAllString(name, "_"))
+}
+
+// Resolves the server configuration from the config layers.
+// If serverId is empty and defaultOrEmpty is true, the server selected by the profile, the project or the global default is returned.
+func getLayeredConfig(serverId string, defaultOrEmpty bool) (*ServerDetails, ServerConfigSources, error) {
+	configs, err := GetAllServersConfigs()
+	if err != nil {
+		return nil, nil, err
+	}
+	projectFile, err := loadProjectServersFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := make(ServerConfigSources)
+	var serverIdSource ConfigSource
+	if serverId == "" && defaultOrEmpty {
+		serverId, serverIdSource = projectFile.getSelectedServerId()
+	}
+
+	var details *ServerDetails
+	if serverId == "" && defaultOrEmpty {
+		if len(configs) == 0 {
+			return new(ServerDetails), sources, nil
+		}
+		if details, err = GetDefaultConfiguredConf(configs); err != nil {
+			return nil, nil, errorutils.CheckError(err)
+		}
+		serverId = details.ServerId
+	} else {
+		details, _ = getServerConfByServerId(serverId, configs)
+	}
+	if details != nil {
+		sources[serverIdField] = ConfigSource{Layer: GlobalConfigLayer}
+		for _, field := range serverFields {
+			if *field.value(details) != "" {
+				sources[field.name] = ConfigSource{Layer: GlobalConfigLayer}
+			}
+		}
+	}
+
+	if details, err = applyEnvLayer(serverId, details, sources); err != nil {
+		return nil, nil, err
+	}
+	if details, err = applyProjectLayer(projectFile, serverId, details, sources); err != nil {
+		return nil, nil, err
+	}
+	if details == nil {
+		if defaultOrEmpty && len(configs) == 0 {
+			return new(ServerDetails), sources, nil
+		}
+		return nil, nil, errorutils.CheckErrorf("Server ID '%s' does not exist.", serverId)
+	}
+	if serverIdSource.Layer != "" {
+		sources[serverIdField] = serverIdSource
+	}
+	return details, sources, nil
+}
+
+// Overrides the fields of the server with the environment variables set for them.
+// If the server is not configured globally, it is created when any of its fields is set.
+func applyEnvLayer(serverId string, details *ServerDetails, sources ServerConfigSources) (*ServerDetails, error) {
+	if serverId == "" {
+		return details, nil
+	}
+	for _, field := range serverFields {
+		envVar := getServerFieldEnvVar(serverId, field.name)
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		if details == nil {
+			details = &ServerDetails{ServerId: serverId}
+			sources[serverIdField] = ConfigSource{Layer: EnvConfigLayer, Location: envVar}
+		}
+		*field.value(details) = value
+		sources[field.name] = ConfigSource{Layer: EnvConfigLayer, Location: envVar}
+	}
+	return details, nil
+}
+
+// Overrides the fields of the server with its fields in the project servers file, after resolving the references to environment variables.
+// If the server is not configured by the other layers, it is created.
+func applyProjectLayer(projectFile *ProjectServersFile, serverId string, details *ServerDetails, sources ServerConfigSources) (*ServerDetails, error) {
+	projectServer := projectFile.getServer(serverId)
+	if projectServer == nil {
+		return details, nil
+	}
+	if details == nil {
+		details = &ServerDetails{ServerId: serverId}
+		sources[serverIdField] = ConfigSource{Layer: ProjectConfigLayer, Location: projectFile.path}
+	}
+	for _, field := range serverFields {
+		value, exists := projectServer[field.name]
+		if !exists {
+			continue
+		}
+		source := ConfigSource{Layer: ProjectConfigLayer, Location: projectFile.path}
+		if strings.HasPrefix(value, envReferencePrefix) {
+			envVar := strings.TrimPrefix(value, envReferencePrefix)
+			if value = os.Getenv(envVar); value == "" {
+				return nil, errorutils.CheckErrorf("the environment variable %s, referenced by the %s of the server '%s' in the project servers file %s, is not set", envVar, field.name, serverId, projectFile.path)
+			}
+			source.Location += ", " + envVar
+		}
+		*field.value(details) = value
+		sources[field.name] = source
+	}
+	return details, validateProjectUrls(projectFile, projectServer, details)
+}
+
+// The project servers file is part of the project, so it can't be trusted with any credentials.
+// Overriding the URLs of a server is therefore allowed only if none of its credentials are set, by any of the layers.
+func validateProjectUrls(projectFile *ProjectServersFile, projectServer map[string]string, details *ServerDetails) error {
+	for _, urlField := range serverFields {
+		if _, exists := projectServer[urlField.name]; !exists || !urlField.isUrl() {
+			continue
+		}
+		for _, field := range serverFields {
+			if field.isCredential() && *field.value(details) != "" {
+				return errorutils.CheckErrorf("the project servers file %s sets the %s of the server '%s', whose %s is set. "+
+					"The URLs of a server with credentials can't be set by the project. Set the URL globally or by the %s environment variable instead",
+					projectFile.path, urlField.name, details.ServerId, field.name, getServerFieldEnvVar(details.ServerId, urlField.name))
+			}
+		}
+	}
+	return nil
+}
+
+// GetServerConfigSources returns the resolved configuration of the server, and the layer each of its fields was resolved from.
+// If serverId is empty, the server selected by the profile, the project or the global default is returned.
+func GetServerConfigSources(serverId string) (*ServerDetails, ServerConfigSources, error) {
+	return getLayeredConfig(serverId, true)
+}
+
+// Returns true if the field of the server configuration is a secret, which should be masked when printed.
+func IsSecretServerField(name string) bool {
+	field := getServerField(name)
+	return field != nil && field.secret
+}
+
+// Returns the value of the field of the server configuration, by its name.
+func GetServerFieldValue(details *ServerDetails, name string) string {
+	if name == serverIdField {
+		return details.ServerId
+	}
+	if field := getServerField(name); field != nil {
+		return *field.value(details)
+	}
+	return ""
+}
+
+// Returns the names of the fields of the server configuration, which may be set by the config layers.
+func GetServerFieldNames() []string {
+	names := []string{serverIdField}
+	for _, field := range serverFields {
+		names = append(names, field.name)
+	}
+	return names
+}
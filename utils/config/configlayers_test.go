@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	configtests "github.com/jfrog/jfrog-cli-core/v2/utils/config/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+const testProjectServersFile = `version: 1
+defaultServerId: staging
+profiles:
+  prod: production
+servers:
+  - serverId: staging
+    accessToken: env:JFROG_TEST_STAGING_TOKEN
+  - serverId: production
+    user: project-user
+  - serverId: release
+    artifactoryUrl: https://untrusted.example.com/artifactory/
+  - serverId: untrusted
+    url: https://untrusted.example.com/
+    accessToken: env:JFROG_TEST_STAGING_TOKEN
+  - serverId: oidc
+    url: https://untrusted.example.com/
+    oidcProviderName: github-oidc
+    oidcTokenEnv: ACTIONS_ID_TOKEN
+  - serverId: public
+    url: https://public.example.com/
+`
+
+func TestToEnvVarName(t *testing.T) {
+	assert.Equal(t, "ACCESS_TOKEN", toEnvVarName("accessToken"))
+	assert.Equal(t, "URL", toEnvVarName("url"))
+	assert.Equal(t, "MY_SERVER_1", toEnvVarName("my-server.1"))
+	assert.Equal(t, "JFROG_CLI_SERVER_PROD_SSH_KEY_PATH", getServerFieldEnvVar("prod", "sshKeyPath"))
+}
+
+func TestProjectServersFileValidation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"unsupported version", "version: 2\n"},
+		{"missing server ID", "version: 1\nservers:\n  - url: https://example.com/\n"},
+		{"unknown field", "version: 1\nservers:\n  - serverId: test\n    token: env:TOKEN\n"},
+		{"plain secret", "version: 1\nservers:\n  - serverId: test\n    password: secret\n"},
+		{"reference without the JFrog prefix", "version: 1\nservers:\n  - serverId: test\n    password: env:AWS_SECRET_ACCESS_KEY\n"},
+		{"unknown key", "version: 1\nservers: []\nservrs: []\n"},
+	}
+	tmpDir := t.TempDir()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "servers.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(testCase.content), 0600))
+			_, err := readProjectServersFile(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLayeredConfig(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "global-default", Url: "https://global.example.com/", AccessToken: "global-token", IsDefault: true},
+		{ServerId: "staging", Url: "https://old-staging.example.com/", User: "global-user", AccessToken: "old-token"},
+		{ServerId: "production", Url: "https://production.example.com/", User: "global-user", Password: "password"},
+		{ServerId: "release", Url: "https://release.example.com/", AccessToken: "release-token"},
+	}))
+
+	// Without a project servers file, the global config is used.
+	details, sources, err := getLayeredConfig("", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "global-default", details.ServerId)
+	assert.Equal(t, GlobalConfigLayer, sources["url"].Layer)
+
+	projectDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".jfrog"), 0777))
+	projectFilePath := filepath.Join(projectDir, ".jfrog", "servers.yaml")
+	assert.NoError(t, os.WriteFile(projectFilePath, []byte(testProjectServersFile), 0600))
+	subDir := filepath.Join(projectDir, "module")
+	assert.NoError(t, os.Mkdir(subDir, 0777))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer testsutils.ChangeDirWithCallback(t, wd, subDir)()
+
+	// A secret referencing an unset environment variable fails the resolution.
+	_, _, err = getLayeredConfig("", true)
+	assert.ErrorContains(t, err, "JFROG_TEST_STAGING_TOKEN")
+
+	defer testsutils.SetEnvWithCallbackAndAssert(t, "JFROG_TEST_STAGING_TOKEN", "project-token")()
+	defer testsutils.SetEnvWithCallbackAndAssert(t, getServerFieldEnvVar("staging", "url"), "https://env-staging.example.com/")()
+	defer testsutils.SetEnvWithCallbackAndAssert(t, getServerFieldEnvVar("staging", "xrayUrl"), "https://env-staging.example.com/xray/")()
+
+	// The project default server is selected, and its fields are resolved by the layers precedence.
+	details, sources, err = getLayeredConfig("", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", details.ServerId)
+	assert.Equal(t, "https://env-staging.example.com/", details.Url)
+	assert.Equal(t, "https://env-staging.example.com/xray/", details.XrayUrl)
+	assert.Equal(t, "global-user", details.User)
+	assert.Equal(t, "project-token", details.AccessToken)
+	assert.Equal(t, ConfigSource{Layer: ProjectConfigLayer, Location: projectFilePath}, sources[serverIdField])
+	assert.Equal(t, EnvConfigLayer, sources["url"].Layer)
+	assert.Equal(t, ConfigSource{Layer: EnvConfigLayer, Location: "JFROG_CLI_SERVER_STAGING_XRAY_URL"}, sources["xrayUrl"])
+	assert.Equal(t, GlobalConfigLayer, sources["user"].Layer)
+	assert.Equal(t, ConfigSource{Layer: ProjectConfigLayer, Location: projectFilePath + ", JFROG_TEST_STAGING_TOKEN"}, sources["accessToken"])
+
+	// The profile of the shell selects the server.
+	defer testsutils.SetEnvWithCallbackAndAssert(t, coreutils.ServerProfile, "prod")()
+	details, sources, err = getLayeredConfig("", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "production", details.ServerId)
+	assert.Equal(t, "project-user", details.User)
+	assert.Equal(t, "password", details.Password)
+	assert.Equal(t, ConfigSource{Layer: EnvConfigLayer, Location: coreutils.ServerProfile}, sources[serverIdField])
+
+	// The URLs of a server can't be set by the project, if any of its credentials are set.
+	_, err = GetSpecificConfig("release", false, false)
+	assert.ErrorContains(t, err, "sets the artifactoryUrl of the server 'release', whose accessToken is set")
+	_, err = GetSpecificConfig("untrusted", false, false)
+	assert.ErrorContains(t, err, "sets the url of the server 'untrusted', whose accessToken is set")
+	_, err = GetSpecificConfig("oidc", false, false)
+	assert.ErrorContains(t, err, "sets the url of the server 'oidc', whose oidcProviderName is set")
+
+	// The URLs of a server without credentials may be set by the project.
+	details, err = GetSpecificConfig("public", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://public.example.com/", details.Url)
+
+	// An explicit server ID is not affected by the profile.
+	details, err = GetSpecificConfig("global-default", true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://global.example.com/", details.Url)
+
+	// A server may be configured by environment variables only.
+	defer testsutils.SetEnvWithCallbackAndAssert(t, getServerFieldEnvVar("ci", "url"), "https://ci.example.com/")()
+	details, sources, err = getLayeredConfig("ci", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ci.example.com/", details.Url)
+	assert.Equal(t, EnvConfigLayer, sources[serverIdField].Layer)
+
+	_, err = GetSpecificConfig("missing", false, false)
+	assert.Error(t, err)
+
+	// The layers are never saved to the global config.
+	configs, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	staging, err := getServerConfByServerId("staging", configs)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://old-staging.example.com/", staging.Url)
+	assert.Equal(t, "old-token", staging.AccessToken)
+}
+
+func TestWriteNewArtifactoryTokens(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "first", Url: "https://first.example.com/", AccessToken: "old-token", ArtifactoryRefreshToken: "old-refresh-token"},
+		{ServerId: "second", Url: "https://second.example.com/"},
+	}))
+
+	// Only the tokens of a global server are updated.
+	layeredDetails := &ServerDetails{ServerId: "first", Url: "https://project.example.com/"}
+	assert.NoError(t, writeNewArtifactoryTokens(layeredDetails, "first", "new-token", "new-refresh-token"))
+	configs, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	if assert.Len(t, configs, 2) {
+		assert.Equal(t, "first", configs[0].ServerId)
+		assert.Equal(t, "https://first.example.com/", configs[0].Url)
+		assert.Equal(t, "new-token", configs[0].AccessToken)
+		assert.Equal(t, "new-refresh-token", configs[0].ArtifactoryRefreshToken)
+	}
+
+	// Servers resolved from the other layers are not added to the global config.
+	assert.NoError(t, writeNewArtifactoryTokens(&ServerDetails{ServerId: "ci", Url: "https://ci.example.com/"}, "ci", "ci-token", "ci-refresh-token"))
+	configs, err = GetAllServersConfigs()
+	assert.NoError(t, err)
+	assert.Len(t, configs, 2)
+}
@@ -20,30 +20,87 @@ import (
 )
 
 type SecurityConf struct {
-	Version   string `yaml:"version,omitempty"`
-	MasterKey string `yaml:"masterKey,omitempty"`
+	Version     string          `yaml:"version,omitempty"`
+	MasterKey   string          `yaml:"masterKey,omitempty"`
+	SecretStore SecretStoreConf `yaml:"secretStore,omitempty"`
 }
 
 const masterKeyField = "masterKey"
+const secretStoreTypeField = "secretStore.type"
+const secretStoreHelperField = "secretStore.helper"
 const masterKeyLength = 32
 const encryptErrorPrefix = "cannot encrypt config: "
 const decryptErrorPrefix = "cannot decrypt config: "
 
 type secretHandler func(string, string) (string, error)
 
-// Encrypt config file if security configuration file exists and contains master key.
+// Returns the type of the secret store configured, or the file secret store by default.
+func (securityConf *SecurityConf) getSecretStoreType() SecretStoreType {
+	if securityConf.SecretStore.Type == "" {
+		return FileSecretStore
+	}
+	return securityConf.SecretStore.Type
+}
+
+// Returns true if the secrets should be encrypted with a master key or kept in a secret store.
+func (securityConf *SecurityConf) protectsSecrets() bool {
+	if securityConf.getSecretStoreType() == FileSecretStore {
+		return securityConf.MasterKey != ""
+	}
+	return true
+}
+
+// Protect the secrets of the config, if the security configuration file contains a master key or a secret store.
+// With the file secret store, the config is encrypted. With the other secret stores, the config keeps only references to the secrets.
 func (config *Config) encrypt() error {
-	key, _, err := getMasterKeyFromSecurityConfFile()
-	if err != nil || key == "" {
+	securityConf, _, err := readSecurityConf()
+	if err != nil || !securityConf.protectsSecrets() {
+		return err
+	}
+	if securityConf.getSecretStoreType() == FileSecretStore {
+		// Mark config as encrypted.
+		config.Enc = true
+		config.SecretStore = ""
+		return handleSecrets(config, encrypt, securityConf.MasterKey)
+	}
+	store, err := newSecretStore(securityConf, securityConf.SecretStore.Type)
+	if err != nil {
+		return err
+	}
+	previousReferences, err := getStoredSecretsReferences(securityConf.SecretStore.Type)
+	if err != nil {
 		return err
 	}
-	// Mark config as encrypted.
-	config.Enc = true
-	return handleSecrets(config, encrypt, key)
+	config.Enc = false
+	config.SecretStore = securityConf.SecretStore.Type
+	references := make(map[string]bool)
+	err = forEachSecret(config, func(serverId, field string, secret *string) (err error) {
+		if *secret == "" {
+			return
+		}
+		*secret, err = store.Save(getSecretKey(serverId, field), *secret)
+		references[*secret] = true
+		return
+	})
+	if err != nil {
+		return err
+	}
+	// Delete the secrets of the servers and fields removed from the config
+	for _, reference := range previousReferences {
+		if !references[reference] {
+			if err = store.Delete(reference); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// Decrypt config if encrypted and master key exists.
+// Decrypt config if encrypted and master key exists, or get its secrets from the secret store.
 func (config *Config) decrypt() error {
+	if config.SecretStore != "" {
+		return config.getSecretsFromStore()
+	}
 	if !config.Enc {
 		return updateEncryptionIfNeeded(config)
 	}
@@ -60,10 +117,48 @@ func (config *Config) decrypt() error {
 	return handleSecrets(config, decrypt, key)
 }
 
-// Encrypt the config file if it is decrypted while security configuration file exists and contains a master key.
+// Replace the references in the config with the secrets kept in the secret store.
+func (config *Config) getSecretsFromStore() error {
+	securityConf, _, err := readSecurityConf()
+	if err != nil {
+		return err
+	}
+	store, err := newSecretStore(securityConf, config.SecretStore)
+	if err != nil {
+		return errorutils.CheckErrorf(decryptErrorPrefix + err.Error())
+	}
+	return forEachSecret(config, func(_, _ string, secret *string) (err error) {
+		if *secret != "" {
+			*secret, err = store.Get(*secret)
+		}
+		return
+	})
+}
+
+// Returns the references in the config file, if its secrets are kept in the given secret store.
+func getStoredSecretsReferences(storeType SecretStoreType) (references []string, err error) {
+	content, err := getConfigFile()
+	if err != nil || len(content) == 0 {
+		return
+	}
+	storedConfig := new(Config)
+	// Config files of older versions don't keep secrets in secret stores, so they are not converted.
+	if json.Unmarshal(content, storedConfig) != nil || storedConfig.SecretStore != storeType {
+		return
+	}
+	err = forEachSecret(storedConfig, func(_, _ string, secret *string) error {
+		if *secret != "" {
+			references = append(references, *secret)
+		}
+		return nil
+	})
+	return
+}
+
+// Protect the secrets of the config file if they are not protected, while the security configuration file contains a master key or a secret store.
 func updateEncryptionIfNeeded(originalConfig *Config) error {
-	masterKey, _, err := getMasterKeyFromSecurityConfFile()
-	if err != nil || masterKey == "" {
+	securityConf, _, err := readSecurityConf()
+	if err != nil || !securityConf.protectsSecrets() {
 		return err
 	}
 
@@ -82,66 +177,80 @@ func updateEncryptionIfNeeded(originalConfig *Config) error {
 		return err
 	}
 	// Mark that config file is encrypted
-	originalConfig.Enc = true
+	originalConfig.Enc = tmpEncConfig.Enc
+	originalConfig.SecretStore = tmpEncConfig.SecretStore
 	return nil
 }
 
 // Encrypt/Decrypt all secrets in the provided config, with the provided master key.
 func handleSecrets(config *Config, handler secretHandler, key string) error {
-	var err error
+	return forEachSecret(config, func(_, _ string, secret *string) (err error) {
+		*secret, err = handler(*secret, key)
+		return
+	})
+}
+
+// Call the handler with each of the secrets of the servers in the config, and the name of its field.
+func forEachSecret(config *Config, handler func(serverId, field string, secret *string) error) error {
 	for _, serverDetails := range config.Servers {
-		serverDetails.Password, err = handler(serverDetails.Password, key)
-		if err != nil {
-			return err
-		}
-		serverDetails.AccessToken, err = handler(serverDetails.AccessToken, key)
-		if err != nil {
-			return err
-		}
-		serverDetails.SshPassphrase, err = handler(serverDetails.SshPassphrase, key)
-		if err != nil {
-			return err
+		secrets := []struct {
+			field string
+			value *string
+		}{
+			{"password", &serverDetails.Password},
+			{"accessToken", &serverDetails.AccessToken},
+			{"sshPassphrase", &serverDetails.SshPassphrase},
+			{"refreshToken", &serverDetails.RefreshToken},
+			{"artifactoryRefreshToken", &serverDetails.ArtifactoryRefreshToken},
 		}
-		serverDetails.RefreshToken, err = handler(serverDetails.RefreshToken, key)
-		if err != nil {
-			return err
-		}
-		serverDetails.ArtifactoryRefreshToken, err = handler(serverDetails.ArtifactoryRefreshToken, key)
-		if err != nil {
-			return err
+		for _, secret := range secrets {
+			if err := handler(serverDetails.ServerId, secret.field, secret.value); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 func getMasterKeyFromSecurityConfFile() (key string, secFileExists bool, err error) {
-	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	securityConf, secFileExists, err := readSecurityConf()
 	if err != nil {
 		return "", false, err
 	}
+	return securityConf.MasterKey, secFileExists, nil
+}
+
+func readSecurityConf() (securityConf *SecurityConf, secFileExists bool, err error) {
+	securityConf = new(SecurityConf)
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return nil, false, err
+	}
 	exists, err := fileutils.IsFileExists(secFile, false)
 	if err != nil || !exists {
-		return "", false, err
+		return securityConf, false, err
 	}
 
 	config := viper.New()
 	config.SetConfigType("yaml")
 	f, err := os.Open(secFile)
+	if err != nil {
+		return nil, false, errorutils.CheckError(err)
+	}
 	defer func() {
 		e := f.Close()
 		if err == nil {
-			err = e
+			err = errorutils.CheckError(e)
 		}
 	}()
-	if err != nil {
-		return "", false, errorutils.CheckError(err)
-	}
 	err = config.ReadConfig(f)
 	if err != nil {
-		return "", false, errorutils.CheckError(err)
+		return nil, false, errorutils.CheckError(err)
 	}
-	key = config.GetString(masterKeyField)
-	return key, true, nil
+	securityConf.MasterKey = config.GetString(masterKeyField)
+	securityConf.SecretStore.Type = SecretStoreType(config.GetString(secretStoreTypeField))
+	securityConf.SecretStore.Helper = config.GetString(secretStoreHelperField)
+	return securityConf, true, nil
 }
 
 func readMasterKeyFromConsole() (string, error) {
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"crypto/rand"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	securityConfVersion = "1"
+	masterKeyCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&*+-=?@^_"
+)
+
+// GenerateMasterKey returns a new random master key of the required length.
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, masterKeyLength)
+	for i := range key {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(masterKeyCharacters))))
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		key[i] = masterKeyCharacters[index.Int64()]
+	}
+	return string(key), nil
+}
+
+// RotateMasterKey decrypts the secrets of all the servers with the current master key, and encrypts them with the new one.
+// If the config is not encrypted yet, the new master key enables its encryption.
+// The config and the security configuration file are restored if the rotation fails.
+func RotateMasterKey(newMasterKey string) error {
+	if len(newMasterKey) != masterKeyLength {
+		return errorutils.CheckErrorf("wrong length for the new master key. The key should have a length of exactly %d bytes", masterKeyLength)
+	}
+	return updateMasterKey(func(securityConf *SecurityConf) error {
+		if securityConf.MasterKey == newMasterKey {
+			return errorutils.CheckErrorf("the new master key is identical to the current master key")
+		}
+		securityConf.MasterKey = newMasterKey
+		return nil
+	})
+}
+
+// DisableEncryption decrypts the secrets of all the servers, and removes the master key from the security configuration file.
+// The config and the security configuration file are restored if the decryption fails.
+func DisableEncryption() error {
+	return updateMasterKey(func(securityConf *SecurityConf) error {
+		if securityConf.MasterKey == "" {
+			return errorutils.CheckErrorf("the config is not encrypted")
+		}
+		securityConf.MasterKey = ""
+		return nil
+	})
+}
+
+// Reads the config with the current master key, updates the security configuration file and saves the config again.
+// All of these steps run under the config lock, so that other processes don't read the config in the middle of the update.
+func updateMasterKey(updateSecurityConf func(*SecurityConf) error) (err error) {
+	lockDirPath, err := coreutils.GetJfrogConfigLockDir()
+	if err != nil {
+		return
+	}
+	unlockFunc, err := lock.CreateLock(lockDirPath)
+	// Defer the lockFile.Unlock() function before throwing a possible error to avoid deadlock situations.
+	defer func() {
+		e := unlockFunc()
+		if err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+
+	securityConf, _, err := readSecurityConf()
+	if err != nil {
+		return
+	}
+	if securityConf.getSecretStoreType() != FileSecretStore {
+		return errorutils.CheckErrorf("the secrets are kept in the '%s' secret store, and are not encrypted with the master key", securityConf.SecretStore.Type)
+	}
+	if err = updateSecurityConf(securityConf); err != nil {
+		return
+	}
+	if err = createHomeDirBackup(); err != nil {
+		return
+	}
+	// Decrypt the config with the current master key.
+	config, err := readConf()
+	if err != nil {
+		return
+	}
+	snapshot, err := takeConfigFilesSnapshot()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		log.Warn("Failed updating the master key. Restoring the config and the security configuration file...")
+		if e := snapshot.restore(); e != nil {
+			log.Error("Failed restoring the config and the security configuration file. A backup of the previous configuration is available at the backup directory of the JFrog CLI home directory: " + e.Error())
+		}
+	}()
+
+	if err = saveSecurityConf(securityConf); err != nil {
+		return
+	}
+	// Secrets of unencrypted configs are saved as-is, so the config must be explicitly marked as not encrypted.
+	config.Enc = false
+	if err = saveConfig(config); err != nil {
+		return
+	}
+	// Make sure the saved config can be decrypted with the new master key.
+	_, err = readConf()
+	return
+}
+
+// Writes the security configuration file. The file is removed if it contains no settings.
+func saveSecurityConf(securityConf *SecurityConf) error {
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return err
+	}
+	if securityConf.MasterKey == "" && securityConf.SecretStore == (SecretStoreConf{}) {
+		return errorutils.CheckError(removeFileIfExists(secFile))
+	}
+	securityConf.Version = securityConfVersion
+	content, err := yaml.Marshal(securityConf)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(secFile), 0777); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(secFile, content, 0600))
+}
+
+// The contents of the config file and the security configuration file before an update.
+// A nil content means that the file didn't exist.
+type configFilesSnapshot struct {
+	files map[string][]byte
+}
+
+func takeConfigFilesSnapshot() (*configFilesSnapshot, error) {
+	confFile, err := getConfFilePath()
+	if err != nil {
+		return nil, err
+	}
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &configFilesSnapshot{files: make(map[string][]byte)}
+	for _, path := range []string{confFile, secFile} {
+		exists, err := fileutils.IsFileExists(path, false)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.files[path] = nil
+		if exists {
+			if snapshot.files[path], err = fileutils.ReadFile(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+func (snapshot *configFilesSnapshot) restore() error {
+	for path, content := range snapshot.files {
+		if content == nil {
+			if err := removeFileIfExists(path); err != nil {
+				return errorutils.CheckError(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return errorutils.CheckError(err)
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+func removeFileIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
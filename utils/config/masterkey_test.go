@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	configtests "github.com/jfrog/jfrog-cli-core/v2/utils/config/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const newMasterKey = "anotherkeywithlengthofexactly32!"
+
+func TestGenerateMasterKey(t *testing.T) {
+	key, err := GenerateMasterKey()
+	assert.NoError(t, err)
+	assert.Len(t, key, masterKeyLength)
+	otherKey, err := GenerateMasterKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+}
+
+func TestRotateMasterKey(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+	originalConfig := saveEncryptedTestConfig(t)
+
+	assert.NoError(t, RotateMasterKey(newMasterKey))
+	key, _, err := getMasterKeyFromSecurityConfFile()
+	assert.NoError(t, err)
+	assert.Equal(t, newMasterKey, key)
+
+	// The config file should be encrypted with the new master key.
+	encryptedConfig := readConfFromFile(t)
+	assert.True(t, encryptedConfig.Enc)
+	verifyEncryptionStatus(t, originalConfig, encryptedConfig, true)
+	password, err := decrypt(encryptedConfig.Servers[0].Password, newMasterKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "password", password)
+
+	readConfig, err := readConf()
+	assert.NoError(t, err)
+	verifyEncryptionStatus(t, originalConfig, readConfig, false)
+
+	// A backup of the home dir should be created.
+	backupDir, err := coreutils.GetJfrogBackupDir()
+	assert.NoError(t, err)
+	backups, err := os.ReadDir(backupDir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, backups)
+}
+
+func TestRotateMasterKeyInvalid(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+
+	assert.Error(t, RotateMasterKey("short"))
+	assert.Error(t, RotateMasterKey("randomkeywithlengthofexactly32!!"))
+	key, _, err := getMasterKeyFromSecurityConfFile()
+	assert.NoError(t, err)
+	assert.Equal(t, "randomkeywithlengthofexactly32!!", key)
+}
+
+func TestConfigFilesSnapshotRestore(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+	confFile, err := getConfFilePath()
+	assert.NoError(t, err)
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	assert.NoError(t, err)
+	originalConfContent, err := os.ReadFile(confFile)
+	assert.NoError(t, err)
+	originalSecContent, err := os.ReadFile(secFile)
+	assert.NoError(t, err)
+	snapshot, err := takeConfigFilesSnapshot()
+	assert.NoError(t, err)
+
+	// Update the files, as a failed rotation may leave them.
+	assert.NoError(t, saveSecurityConf(&SecurityConf{MasterKey: newMasterKey}))
+	assert.NoError(t, os.WriteFile(confFile, []byte("{}"), 0600))
+
+	// Restoring replaces the updated files with the original ones.
+	assert.NoError(t, snapshot.restore())
+	confContent, err := os.ReadFile(confFile)
+	assert.NoError(t, err)
+	assert.Equal(t, originalConfContent, confContent)
+	secContent, err := os.ReadFile(secFile)
+	assert.NoError(t, err)
+	assert.Equal(t, originalSecContent, secContent)
+}
+
+func TestDisableEncryption(t *testing.T) {
+	cleanUpTempEnv := configtests.CreateTempEnv(t, true)
+	defer cleanUpTempEnv()
+	originalConfig := saveEncryptedTestConfig(t)
+
+	assert.NoError(t, DisableEncryption())
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, secFile)
+	decryptedConfig := readConfFromFile(t)
+	assert.False(t, decryptedConfig.Enc)
+	verifyEncryptionStatus(t, originalConfig, decryptedConfig, false)
+	assert.Equal(t, "password", decryptedConfig.Servers[0].Password)
+
+	// Disabling the encryption of an unencrypted config fails.
+	assert.Error(t, DisableEncryption())
+}
+
+// Saves a config with secrets, encrypted with the current master key, and returns the decrypted config.
+func saveEncryptedTestConfig(t *testing.T) *Config {
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: "test", Url: "http://localhost:8080/", User: "user", Password: "password", AccessToken: "token"}}))
+	originalConfig, err := readConf()
+	assert.NoError(t, err)
+	verifyEncryptionStatus(t, originalConfig, readConfFromFile(t), true)
+	return originalConfig
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	oidcTokenExchangeApi     = "api/v1/oidc/token"
+	tokenExchangeGrantType   = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenSubjectTokenType  = "urn:ietf:params:oauth:token-type:id_token"
+	oidcTokenExchangeRetries = 3
+)
+
+// The request body of the Access token exchange API
+type oidcTokenExchangeRequest struct {
+	GrantType        string `json:"grant_type"`
+	SubjectTokenType string `json:"subject_token_type"`
+	SubjectToken     string `json:"subject_token"`
+	ProviderName     string `json:"provider_name"`
+}
+
+type oidcTokenExchangeResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// The access tokens acquired by the token exchange, by the servers they were acquired for.
+// Access tokens are kept in memory only, since they can be acquired again by exchanging a new ID token.
+var oidcAccessTokens = struct {
+	sync.Mutex
+	tokens map[string]oidcAccessToken
+}{tokens: make(map[string]oidcAccessToken)}
+
+type oidcAccessToken struct {
+	value  string
+	expiry time.Time
+}
+
+// Returns a pre-request interceptor, which sets the access token acquired by exchanging the OIDC ID token of the server.
+// A new access token is acquired when the previous one is about to expire.
+func (serverDetails *ServerDetails) createOidcTokenExchangePreRequestInterceptor() auth.ServiceDetailsPreRequestFunc {
+	return func(fields *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+		accessToken, err := serverDetails.getOidcAccessToken()
+		if err != nil {
+			return err
+		}
+		fields.SetAccessToken(accessToken)
+		httpClientDetails.AccessToken = accessToken
+		// The access token replaces any other credentials
+		httpClientDetails.User = ""
+		httpClientDetails.Password = ""
+		return nil
+	}
+}
+
+func (serverDetails *ServerDetails) getOidcAccessToken() (string, error) {
+	oidcAccessTokens.Lock()
+	defer oidcAccessTokens.Unlock()
+	cacheKey := serverDetails.getOidcTokenExchangeUrl() + " " + serverDetails.OidcProviderName
+	token, exists := oidcAccessTokens.tokens[cacheKey]
+	if exists && time.Until(token.expiry) > time.Duration(auth.RefreshBeforeExpiryMinutes)*time.Minute {
+		return token.value, nil
+	}
+	token, err := serverDetails.exchangeOidcToken()
+	if err != nil {
+		return "", err
+	}
+	oidcAccessTokens.tokens[cacheKey] = token
+	return token.value, nil
+}
+
+// Exchanges the OIDC ID token for a short-lived access token, using the Access token exchange API.
+func (serverDetails *ServerDetails) exchangeOidcToken() (oidcAccessToken, error) {
+	idToken, err := serverDetails.readOidcIdToken()
+	if err != nil {
+		return oidcAccessToken{}, err
+	}
+	content, err := json.Marshal(oidcTokenExchangeRequest{
+		GrantType:        tokenExchangeGrantType,
+		SubjectTokenType: idTokenSubjectTokenType,
+		SubjectToken:     idToken,
+		ProviderName:     serverDetails.OidcProviderName,
+	})
+	if err != nil {
+		return oidcAccessToken{}, errorutils.CheckError(err)
+	}
+	client, err := serverDetails.createOidcTokenExchangeHttpClient()
+	if err != nil {
+		return oidcAccessToken{}, err
+	}
+	exchangeUrl := serverDetails.getOidcTokenExchangeUrl()
+	log.Debug("Exchanging the OIDC ID token for an access token at " + exchangeUrl)
+	httpClientDetails := httputils.HttpClientDetails{Headers: map[string]string{"Content-Type": "application/json"}}
+	resp, body, err := client.SendPost(exchangeUrl, content, httpClientDetails, "")
+	if err != nil {
+		return oidcAccessToken{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return oidcAccessToken{}, errorutils.CheckErrorf("failed exchanging the OIDC ID token with the '%s' provider: %s %s", serverDetails.OidcProviderName, resp.Status, string(body))
+	}
+	var response oidcTokenExchangeResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return oidcAccessToken{}, errorutils.CheckErrorf("unexpected response of the token exchange: %s", err.Error())
+	}
+	if response.AccessToken == "" {
+		return oidcAccessToken{}, errorutils.CheckErrorf("the token exchange response does not contain an access token")
+	}
+	token := oidcAccessToken{value: response.AccessToken}
+	if response.ExpiresIn > 0 {
+		token.expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	} else if minutesLeft, err := auth.GetTokenMinutesLeft(response.AccessToken); err == nil {
+		token.expiry = time.Now().Add(time.Duration(minutesLeft) * time.Minute)
+	}
+	log.Debug("The OIDC ID token was exchanged successfully.")
+	return token, nil
+}
+
+// The token exchange client trusts the certificates of the JFrog CLI, and authenticates with the client certificate of the server, like the other clients of the server.
+func (serverDetails *ServerDetails) createOidcTokenExchangeHttpClient() (*httpclient.HttpClient, error) {
+	certsPath, err := coreutils.GetJfrogCertsDir()
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.ClientBuilder().
+		SetCertificatesPath(certsPath).
+		SetInsecureTls(serverDetails.InsecureTls).
+		SetClientCertPath(serverDetails.ClientCertPath).
+		SetClientCertKeyPath(serverDetails.ClientCertKeyPath).
+		SetRetries(oidcTokenExchangeRetries).
+		Build()
+}
+
+func (serverDetails *ServerDetails) getOidcTokenExchangeUrl() string {
+	accessUrl := serverDetails.AccessUrl
+	if accessUrl == "" {
+		accessUrl = utils.AddTrailingSlashIfNeeded(serverDetails.Url) + "access/"
+	}
+	return utils.AddTrailingSlashIfNeeded(accessUrl) + oidcTokenExchangeApi
+}
+
+// Reads the OIDC ID token from the configured file or environment variable.
+// The ID token is never discovered implicitly, since it is sent to the token exchange URL of the server, and must be issued for it.
+// In GitHub Actions or GitLab CI, the ID token should be requested for the audience of the JFrog Platform, and its environment variable set in the server configuration.
+func (serverDetails *ServerDetails) readOidcIdToken() (string, error) {
+	switch {
+	case serverDetails.OidcTokenFile != "":
+		return readOidcIdTokenFile(serverDetails.OidcTokenFile)
+	case serverDetails.OidcTokenEnv != "":
+		if idToken := os.Getenv(serverDetails.OidcTokenEnv); idToken != "" {
+			return idToken, nil
+		}
+		return "", errorutils.CheckErrorf("the environment variable %s, which should contain the OIDC ID token, is not set", serverDetails.OidcTokenEnv)
+	}
+	return "", errorutils.CheckErrorf("no OIDC ID token source is configured for the '%s' provider. Configure the file or the environment variable containing the token", serverDetails.OidcProviderName)
+}
+
+func readOidcIdTokenFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errorutils.CheckErrorf("failed reading the OIDC ID token: %s", err.Error())
+	}
+	idToken := strings.TrimSpace(string(content))
+	if idToken == "" {
+		return "", errorutils.CheckErrorf("the OIDC ID token file %s is empty", path)
+	}
+	return idToken, nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+// Creates a stub of the Access token exchange API, which counts the exchanges.
+func createTokenExchangeStub(t *testing.T, exchanges *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/access/"+oidcTokenExchangeApi, r.URL.Path)
+		var request oidcTokenExchangeRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.SubjectToken != "id-token" || request.ProviderName != "github-oidc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, tokenExchangeGrantType, request.GrantType)
+		assert.Equal(t, idTokenSubjectTokenType, request.SubjectTokenType)
+		*exchanges++
+		content, err := json.Marshal(oidcTokenExchangeResponse{AccessToken: "access-token", ExpiresIn: 3600})
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}))
+}
+
+func TestOidcTokenExchangePreRequestInterceptor(t *testing.T) {
+	exchanges := 0
+	server := createTokenExchangeStub(t, &exchanges)
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("id-token\n"), 0600))
+
+	serverDetails := &ServerDetails{Url: server.URL, ArtifactoryUrl: server.URL + "/artifactory/", User: "user", OidcProviderName: "github-oidc", OidcTokenFile: tokenFile}
+	authConfig, err := serverDetails.CreateArtAuthConfig()
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		httpClientDetails := authConfig.CreateHttpClientDetails()
+		assert.NoError(t, authConfig.RunPreRequestFunctions(&httpClientDetails))
+		assert.Equal(t, "access-token", httpClientDetails.AccessToken)
+		assert.Empty(t, httpClientDetails.User)
+		assert.Equal(t, "access-token", authConfig.GetAccessToken())
+	}
+	// The access token is reused until it is about to expire.
+	assert.Equal(t, 1, exchanges)
+}
+
+func TestOidcTokenExchangeFailure(t *testing.T) {
+	exchanges := 0
+	server := createTokenExchangeStub(t, &exchanges)
+	defer server.Close()
+	defer testsutils.SetEnvWithCallbackAndAssert(t, "TEST_OIDC_TOKEN", "wrong-token")()
+
+	serverDetails := &ServerDetails{Url: server.URL, OidcProviderName: "github-oidc", OidcTokenEnv: "TEST_OIDC_TOKEN"}
+	_, err := serverDetails.getOidcAccessToken()
+	assert.ErrorContains(t, err, "401")
+	assert.Zero(t, exchanges)
+}
+
+func TestReadOidcIdToken(t *testing.T) {
+	defer testsutils.SetEnvWithCallbackAndAssert(t, "TEST_OIDC_TOKEN", "env-token")()
+	idToken, err := (&ServerDetails{OidcTokenEnv: "TEST_OIDC_TOKEN"}).readOidcIdToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "env-token", idToken)
+
+	_, err = (&ServerDetails{OidcTokenEnv: "TEST_MISSING_OIDC_TOKEN"}).readOidcIdToken()
+	assert.Error(t, err)
+
+	emptyFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0600))
+	_, err = (&ServerDetails{OidcTokenFile: emptyFile}).readOidcIdToken()
+	assert.Error(t, err)
+
+	// The ID token isn't discovered without an explicit source.
+	defer testsutils.SetEnvWithCallbackAndAssert(t, "ACTIONS_ID_TOKEN_REQUEST_URL", "https://github.example.com/token")()
+	_, err = (&ServerDetails{OidcProviderName: "github-oidc"}).readOidcIdToken()
+	assert.ErrorContains(t, err, "no OIDC ID token source is configured for the 'github-oidc' provider")
+}
+
+func TestGetOidcTokenExchangeUrl(t *testing.T) {
+	assert.Equal(t, "https://acme.jfrog.io/access/api/v1/oidc/token", (&ServerDetails{Url: "https://acme.jfrog.io"}).getOidcTokenExchangeUrl())
+	assert.Equal(t, "https://access.acme.io/api/v1/oidc/token", (&ServerDetails{Url: "https://acme.jfrog.io/", AccessUrl: "https://access.acme.io"}).getOidcTokenExchangeUrl())
+}
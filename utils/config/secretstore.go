@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+type SecretStoreType string
+
+const (
+	// SecretStoreType values
+	// The secrets are encrypted with the master key, and kept in the config file
+	FileSecretStore SecretStoreType = "file"
+	// The secrets are kept by the Linux Secret Service over D-Bus, using the secret-tool command of libsecret
+	SecretServiceStore SecretStoreType = "secret-service"
+	// The secrets are kept in the password store of the pass command, encrypted with GPG
+	PassSecretStore SecretStoreType = "pass"
+	// The secrets are kept by a credential helper implementing the docker-credential-helpers protocol
+	ExecSecretStore SecretStoreType = "exec"
+
+	secretStoreService = "jfrog-cli"
+)
+
+var SecretStoreTypes = []string{string(FileSecretStore), string(SecretServiceStore), string(PassSecretStore), string(ExecSecretStore)}
+
+// SecretStore keeps the secrets of the servers, such as passwords and access tokens.
+// The config file keeps only the references returned by the store.
+type SecretStore interface {
+	// Saves the secret under the key, and returns the reference to keep in the config file
+	Save(key, secret string) (reference string, err error)
+	// Returns the secret of the reference
+	Get(reference string) (string, error)
+	// Deletes the secret of the reference
+	Delete(reference string) error
+}
+
+// The configuration of the secret store in the security configuration file
+type SecretStoreConf struct {
+	Type SecretStoreType `yaml:"type,omitempty"`
+	// The credential helper executable of the exec secret store, such as docker-credential-pass
+	Helper string `yaml:"helper,omitempty"`
+}
+
+func newSecretStore(securityConf *SecurityConf, storeType SecretStoreType) (SecretStore, error) {
+	switch storeType {
+	case FileSecretStore, "":
+		if securityConf.MasterKey == "" {
+			return nil, errorutils.CheckErrorf("the security configuration file does not contain a master key")
+		}
+		return &fileSecretStore{masterKey: securityConf.MasterKey}, nil
+	case SecretServiceStore:
+		return &secretServiceStore{executable: "secret-tool"}, nil
+	case PassSecretStore:
+		return &passSecretStore{executable: "pass"}, nil
+	case ExecSecretStore:
+		if securityConf.SecretStore.Helper == "" {
+			return nil, errorutils.CheckErrorf("the exec secret store requires a credential helper in the security configuration file")
+		}
+		return &execSecretStore{helper: securityConf.SecretStore.Helper}, nil
+	}
+	return nil, errorutils.CheckErrorf("unsupported secret store '%s'. The supported secret stores are: %s", storeType, strings.Join(SecretStoreTypes, ", "))
+}
+
+// The key of a secret in the store: <server ID>/<field>
+func getSecretKey(serverId, field string) string {
+	return serverId + "/" + field
+}
+
+// The secrets are encrypted with AES-GCM, and the cipher texts are the references
+type fileSecretStore struct {
+	masterKey string
+}
+
+func (fss *fileSecretStore) Save(_, secret string) (string, error) {
+	return encrypt(secret, fss.masterKey)
+}
+
+func (fss *fileSecretStore) Get(reference string) (string, error) {
+	return decrypt(reference, fss.masterKey)
+}
+
+func (fss *fileSecretStore) Delete(string) error {
+	return nil
+}
+
+type secretServiceStore struct {
+	executable string
+}
+
+func (sss *secretServiceStore) Save(key, secret string) (string, error) {
+	_, err := runSecretStoreCommand(secret, sss.executable, "store", "--label", "JFrog CLI "+key, "service", secretStoreService, "key", key)
+	return key, err
+}
+
+func (sss *secretServiceStore) Get(reference string) (string, error) {
+	secret, err := runSecretStoreCommand("", sss.executable, "lookup", "service", secretStoreService, "key", reference)
+	if err == nil && secret == "" {
+		err = errorutils.CheckErrorf("the secret '%s' was not found in the Secret Service", reference)
+	}
+	return strings.TrimSuffix(secret, "\n"), err
+}
+
+func (sss *secretServiceStore) Delete(reference string) error {
+	_, err := runSecretStoreCommand("", sss.executable, "clear", "service", secretStoreService, "key", reference)
+	return err
+}
+
+type passSecretStore struct {
+	executable string
+}
+
+func (pss *passSecretStore) Save(key, secret string) (string, error) {
+	_, err := runSecretStoreCommand(secret, pss.executable, "insert", "--multiline", "--force", getPassName(key))
+	return key, err
+}
+
+func (pss *passSecretStore) Get(reference string) (string, error) {
+	secret, err := runSecretStoreCommand("", pss.executable, "show", getPassName(reference))
+	return strings.TrimSuffix(secret, "\n"), err
+}
+
+func (pss *passSecretStore) Delete(reference string) error {
+	_, err := runSecretStoreCommand("", pss.executable, "rm", "--force", getPassName(reference))
+	return err
+}
+
+func getPassName(key string) string {
+	return secretStoreService + "/" + key
+}
+
+// The credentials of the docker-credential-helpers protocol
+type helperCredentials struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+type execSecretStore struct {
+	helper string
+}
+
+func (ess *execSecretStore) Save(key, secret string) (string, error) {
+	content, err := json.Marshal(helperCredentials{ServerURL: getHelperServerUrl(key), Username: key, Secret: secret})
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	_, err = runSecretStoreCommand(string(content), ess.helper, "store")
+	return key, err
+}
+
+func (ess *execSecretStore) Get(reference string) (string, error) {
+	output, err := runSecretStoreCommand(getHelperServerUrl(reference), ess.helper, "get")
+	if err != nil {
+		return "", err
+	}
+	var credentials helperCredentials
+	if err = json.Unmarshal([]byte(output), &credentials); err != nil {
+		return "", errorutils.CheckErrorf("unexpected response of the credential helper %s: %s", ess.helper, err.Error())
+	}
+	return credentials.Secret, nil
+}
+
+func (ess *execSecretStore) Delete(reference string) error {
+	_, err := runSecretStoreCommand(getHelperServerUrl(reference), ess.helper, "erase")
+	return err
+}
+
+func getHelperServerUrl(key string) string {
+	return secretStoreService + "://" + key
+}
+
+// Runs the command with the input in its standard input, and returns its standard output.
+// The input is never included in the returned errors, since it may be a secret.
+func runSecretStoreCommand(input, executable string, args ...string) (string, error) {
+	cmd := exec.Command(executable, args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errorutils.CheckErrorf("'%s %s' failed: %s %s", executable, args[0], err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
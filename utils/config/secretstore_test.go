@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	configtests "github.com/jfrog/jfrog-cli-core/v2/utils/config/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// A credential helper of the docker-credential-helpers protocol, which keeps the credentials in files next to it
+const fakeCredentialHelper = `#!/bin/sh
+dir="$(dirname "$0")/secrets"
+mkdir -p "$dir"
+input=$(cat)
+case "$1" in
+store)
+  url=$(printf '%s' "$input" | sed 's/.*"ServerURL":"\([^"]*\)".*/\1/')
+  printf '%s' "$input" > "$dir/$(printf '%s' "$url" | tr '/:' '__')";;
+get)
+  cat "$dir/$(printf '%s' "$input" | tr '/:' '__')";;
+erase)
+  rm "$dir/$(printf '%s' "$input" | tr '/:' '__')";;
+esac
+`
+
+func TestNewSecretStore(t *testing.T) {
+	store, err := newSecretStore(&SecurityConf{MasterKey: "randomkeywithlengthofexactly32!!"}, "")
+	assert.NoError(t, err)
+	reference, err := store.Save("server/password", "password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "password", reference)
+	secret, err := store.Get(reference)
+	assert.NoError(t, err)
+	assert.Equal(t, "password", secret)
+
+	_, err = newSecretStore(&SecurityConf{}, FileSecretStore)
+	assert.ErrorContains(t, err, "does not contain a master key")
+	_, err = newSecretStore(&SecurityConf{}, ExecSecretStore)
+	assert.ErrorContains(t, err, "requires a credential helper")
+	_, err = newSecretStore(&SecurityConf{}, "keychain")
+	assert.ErrorContains(t, err, "unsupported secret store 'keychain'")
+}
+
+func TestSecurityConfProtectsSecrets(t *testing.T) {
+	assert.False(t, (&SecurityConf{}).protectsSecrets())
+	assert.True(t, (&SecurityConf{MasterKey: "key"}).protectsSecrets())
+	assert.False(t, (&SecurityConf{SecretStore: SecretStoreConf{Type: FileSecretStore}}).protectsSecrets())
+	assert.True(t, (&SecurityConf{SecretStore: SecretStoreConf{Type: PassSecretStore}}).protectsSecrets())
+}
+
+func TestExecSecretStoreConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake credential helper is a shell script")
+	}
+	cleanUpTempEnv := configtests.CreateTempEnv(t, false)
+	defer cleanUpTempEnv()
+	helperDir := t.TempDir()
+	helper := filepath.Join(helperDir, "docker-credential-fake")
+	assert.NoError(t, os.WriteFile(helper, []byte(fakeCredentialHelper), 0700))
+	secFile, err := coreutils.GetJfrogSecurityConfFilePath()
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(secFile, []byte("version: 1\nsecretStore:\n  type: exec\n  helper: "+helper+"\n"), 0600))
+
+	assert.NoError(t, SaveServersConf([]*ServerDetails{
+		{ServerId: "server1", User: "user", Password: "password1", AccessToken: "token1"},
+		{ServerId: "server2", AccessToken: "token2"},
+	}))
+
+	// The config file keeps only references to the secrets
+	storedConfig := readStoredConfig(t)
+	assert.Equal(t, ExecSecretStore, storedConfig.SecretStore)
+	assert.False(t, storedConfig.Enc)
+	assert.Equal(t, "server1/password", storedConfig.Servers[0].Password)
+	assert.Equal(t, "server1/accessToken", storedConfig.Servers[0].AccessToken)
+	assert.Equal(t, "server2/accessToken", storedConfig.Servers[1].AccessToken)
+	assertStoredSecretsCount(t, helperDir, 3)
+
+	servers, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	assert.Equal(t, "password1", servers[0].Password)
+	assert.Equal(t, "token1", servers[0].AccessToken)
+	assert.Equal(t, "token2", servers[1].AccessToken)
+
+	// The secrets of removed servers are deleted from the secret store
+	assert.NoError(t, SaveServersConf(servers[:1]))
+	assertStoredSecretsCount(t, helperDir, 2)
+}
+
+func readStoredConfig(t *testing.T) *Config {
+	content, err := getConfigFile()
+	assert.NoError(t, err)
+	storedConfig := new(Config)
+	assert.NoError(t, json.Unmarshal(content, storedConfig))
+	return storedConfig
+}
+
+func assertStoredSecretsCount(t *testing.T, helperDir string, expected int) {
+	files, err := os.ReadDir(filepath.Join(helperDir, "secrets"))
+	assert.NoError(t, err)
+	assert.Len(t, files, expected)
+}
@@ -170,11 +170,15 @@ func writeNewArtifactoryTokens(serverConfiguration *ServerDetails, serverId, acc
 		return err
 	}
 
-	// Remove and get the server details from the configurations list
-	_, configurations = GetAndRemoveConfiguration(serverId, configurations)
-
-	// Append the configuration to the configurations list
-	configurations = append(configurations, serverConfiguration)
+	// Servers resolved from the project servers file or the environment variables are never added to the global config
+	globalConfiguration, err := getServerConfByServerId(serverId, configurations)
+	if err != nil {
+		log.Debug("The server '" + serverId + "' is not configured globally, so its refreshed tokens are not saved.")
+		return nil
+	}
+	// Only the tokens are updated, to avoid saving values of the project servers file and the environment variables in the global config
+	globalConfiguration.SetAccessToken(accessToken)
+	globalConfiguration.SetArtifactoryRefreshToken(refreshToken)
 	return SaveServersConf(configurations)
 }
 
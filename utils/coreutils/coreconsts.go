@@ -35,10 +35,14 @@ const (
 	TempDir            = "JFROG_CLI_TEMP_DIR"
 	LogLevel           = "JFROG_CLI_LOG_LEVEL"
 	LogTimestamp       = "JFROG_CLI_LOG_TIMESTAMP"
+	LogFormat          = "JFROG_CLI_LOG_FORMAT"
+	CorrelationId      = "JFROG_CLI_CORRELATION_ID"
 	ReportUsage        = "JFROG_CLI_REPORT_USAGE"
 	DependenciesDir    = "JFROG_CLI_DEPENDENCIES_DIR"
 	TransitiveDownload = "JFROG_CLI_TRANSITIVE_DOWNLOAD_EXPERIMENTAL"
 	FailNoOp           = "JFROG_CLI_FAIL_NO_OP"
+	ServerProfile      = "JFROG_CLI_PROFILE"
+	ServerEnvPrefix    = "JFROG_CLI_SERVER_"
 	CI                 = "CI"
 )
 
@@ -13,6 +13,14 @@ import (
 	"golang.org/x/term"
 )
 
+// The output format of commands, which print their results either as a table or as JSON.
+type OutputFormat string
+
+const (
+	TableFormat OutputFormat = "table"
+	JsonFormat  OutputFormat = "json"
+)
+
 // Controls the max col width when printing to a non-terminal. See the PrintTable description for more info.
 var DefaultMaxColWidth = 25
 
@@ -36,7 +36,12 @@ func getJfrogCliLogTimestamp() int {
 	}
 }
 
+// Sets the default logger, or the structured JSON logger if JFROG_CLI_LOG_FORMAT is set to JSON.
 func SetDefaultLogger() {
+	if IsJsonLogFormat() {
+		log.SetLogger(NewJsonLogger(GetCliLogLevel(), os.Stderr, os.Stdout))
+		return
+	}
 	log.SetLogger(log.NewLoggerWithFlags(GetCliLogLevel(), nil, getJfrogCliLogTimestamp()))
 }
 
@@ -0,0 +1,165 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The HTTP header, which carries the correlation ID in the requests to the JFrog Platform.
+const CorrelationIdHeader = "X-JFrog-CLI-Correlation-Id"
+
+// The value of the JFROG_CLI_LOG_FORMAT environment variable, which enables the structured log.
+const JsonLogFormat = "JSON"
+
+var (
+	correlationId     string
+	correlationIdOnce sync.Once
+	commandName       string
+	commandNameMutex  sync.RWMutex
+)
+
+// GetCorrelationId returns the ID of the current CLI invocation, which is attached to its log entries and requests.
+// The ID is taken from JFROG_CLI_CORRELATION_ID if set, and is otherwise generated and exported to that variable,
+// so processes started by the CLI, such as plugins, share the same ID.
+func GetCorrelationId() string {
+	correlationIdOnce.Do(func() {
+		correlationId = os.Getenv(coreutils.CorrelationId)
+		if correlationId == "" {
+			correlationId = uuid.NewString()
+			if err := os.Setenv(coreutils.CorrelationId, correlationId); err != nil {
+				log.Debug("Failed exporting the correlation ID: " + err.Error())
+			}
+		}
+	})
+	return correlationId
+}
+
+// SetCommandName sets the name of the running command, which is attached to the structured log entries.
+func SetCommandName(name string) {
+	commandNameMutex.Lock()
+	defer commandNameMutex.Unlock()
+	commandName = name
+}
+
+func getCommandName() string {
+	commandNameMutex.RLock()
+	defer commandNameMutex.RUnlock()
+	return commandName
+}
+
+func IsJsonLogFormat() bool {
+	return strings.EqualFold(os.Getenv(coreutils.LogFormat), JsonLogFormat)
+}
+
+// Fields are key/value pairs, which can be passed to the log functions in addition to the message.
+// For example: log.Info("Uploaded the artifacts.", corelog.Fields{"repo": repo, "count": count})
+// The structured log emits the fields as JSON, while the text log prints them as key=value pairs.
+type Fields map[string]interface{}
+
+func (fields Fields) String() string {
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, fields[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+type jsonLogEntry struct {
+	Timestamp     string `json:"timestamp"`
+	Level         string `json:"level"`
+	Command       string `json:"command,omitempty"`
+	CorrelationId string `json:"correlationId"`
+	Message       string `json:"message"`
+	Fields        Fields `json:"fields,omitempty"`
+}
+
+// JsonLogger writes the log entries as JSON lines, for log pipelines.
+// The command output is written as is, since it is the result of the command rather than a log entry.
+type JsonLogger struct {
+	level        log.LevelType
+	logsWriter   io.Writer
+	outputWriter io.Writer
+	mutex        sync.Mutex
+}
+
+func NewJsonLogger(level log.LevelType, logsWriter, outputWriter io.Writer) *JsonLogger {
+	return &JsonLogger{level: level, logsWriter: logsWriter, outputWriter: outputWriter}
+}
+
+func (logger *JsonLogger) GetLogLevel() log.LevelType {
+	return logger.level
+}
+
+func (logger *JsonLogger) Debug(a ...interface{}) {
+	logger.log(log.DEBUG, "debug", a...)
+}
+
+func (logger *JsonLogger) Info(a ...interface{}) {
+	logger.log(log.INFO, "info", a...)
+}
+
+func (logger *JsonLogger) Warn(a ...interface{}) {
+	logger.log(log.WARN, "warn", a...)
+}
+
+func (logger *JsonLogger) Error(a ...interface{}) {
+	logger.log(log.ERROR, "error", a...)
+}
+
+func (logger *JsonLogger) Output(a ...interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	_, _ = fmt.Fprintln(logger.outputWriter, a...)
+}
+
+func (logger *JsonLogger) log(level log.LevelType, levelName string, a ...interface{}) {
+	if logger.level < level {
+		return
+	}
+	entry := jsonLogEntry{
+		Timestamp:     time.Now().Format(time.RFC3339Nano),
+		Level:         levelName,
+		Command:       getCommandName(),
+		CorrelationId: GetCorrelationId(),
+	}
+	var values []interface{}
+	for _, value := range a {
+		if fields, ok := value.(Fields); ok {
+			if entry.Fields == nil {
+				entry.Fields = Fields{}
+			}
+			for key, fieldValue := range fields {
+				entry.Fields[key] = fieldValue
+			}
+			continue
+		}
+		values = append(values, value)
+	}
+	entry.Message = strings.TrimSuffix(fmt.Sprintln(values...), "\n")
+	content, err := json.Marshal(entry)
+	if err != nil {
+		// Fields which can't be marshaled are replaced with their text representation.
+		entry.Fields = Fields{"fields": entry.Fields.String()}
+		if content, err = json.Marshal(entry); err != nil {
+			return
+		}
+	}
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	_, _ = fmt.Fprintln(logger.logsWriter, string(content))
+}
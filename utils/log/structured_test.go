@@ -0,0 +1,39 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonLogger(t *testing.T) {
+	var logs, output bytes.Buffer
+	logger := NewJsonLogger(log.INFO, &logs, &output)
+	SetCommandName("rt_upload")
+	defer SetCommandName("")
+
+	logger.Info("Uploaded", 3, "files.", Fields{"repo": "generic-local"})
+	logger.Debug("Not logged in the INFO level.")
+	logger.Output(`{"status":"success"}`)
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	assert.Len(t, lines, 1)
+	var entry jsonLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "info", entry.Level)
+	assert.Equal(t, "rt_upload", entry.Command)
+	assert.Equal(t, "Uploaded 3 files.", entry.Message)
+	assert.Equal(t, Fields{"repo": "generic-local"}, entry.Fields)
+	assert.Equal(t, GetCorrelationId(), entry.CorrelationId)
+	assert.NotEmpty(t, entry.Timestamp)
+	// The command output is not wrapped as a log entry.
+	assert.Equal(t, "{\"status\":\"success\"}\n", output.String())
+}
+
+func TestFieldsString(t *testing.T) {
+	assert.Equal(t, "count=3 repo=generic-local", Fields{"repo": "generic-local", "count": 3}.String())
+}
@@ -0,0 +1,143 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const coreModulePath = "github.com/jfrog/jfrog-cli-core/v2"
+
+// ReadPluginSignature runs the plugin's hidden signature command, and returns the signature and manifest of the plugin.
+func ReadPluginSignature(executablePath string) (*components.PluginSignature, error) {
+	output, err := exec.Command(executablePath, plugins.SignatureCommandName).Output()
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed reading the signature of the plugin at %s: %s", executablePath, err.Error())
+	}
+	signature := new(components.PluginSignature)
+	if err = json.Unmarshal(output, signature); err != nil {
+		return nil, errorutils.CheckErrorf("the plugin at %s returned an invalid signature: %s", executablePath, err.Error())
+	}
+	return signature, nil
+}
+
+// ReadInstalledPluginSignature returns the signature of a plugin installed at '.jfrog/plugins', or nil if the plugin isn't installed.
+func ReadInstalledPluginSignature(pluginName string) (*components.PluginSignature, error) {
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return nil, err
+	}
+	executablePath := getPluginExecutablePath(pluginsDir, pluginName)
+	exists, err := fileutils.IsFileExists(executablePath, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return ReadPluginSignature(executablePath)
+}
+
+// ValidateInstalledPlugin validates the manifest of an installed plugin before running it.
+// Only the plugin and the plugins it depends on are run to read their signatures.
+func ValidateInstalledPlugin(pluginName string) error {
+	signature, err := ReadInstalledPluginSignature(pluginName)
+	if err != nil {
+		return err
+	}
+	if signature == nil {
+		return errorutils.CheckErrorf("the plugin '%s' is not installed", pluginName)
+	}
+	installed := map[string]*components.PluginSignature{pluginName: signature}
+	if signature.Manifest != nil {
+		for _, dependency := range signature.Manifest.Dependencies {
+			if _, exists := installed[dependency.Name]; exists {
+				continue
+			}
+			dependencySignature, err := ReadInstalledPluginSignature(dependency.Name)
+			if err != nil {
+				return err
+			}
+			if dependencySignature != nil {
+				installed[dependency.Name] = dependencySignature
+			}
+		}
+	}
+	return ValidatePluginManifest(signature, installed)
+}
+
+// ValidatePluginManifest validates the requirements of a plugin, which is being installed or run, against the CLI and the installed plugins.
+// All the unmet requirements are returned in a single error.
+func ValidatePluginManifest(signature *components.PluginSignature, installed map[string]*components.PluginSignature) error {
+	return validatePluginManifest(signature, installed, getCoreVersion())
+}
+
+// The minimal core version is validated only if the version of the core module is known.
+func validatePluginManifest(signature *components.PluginSignature, installed map[string]*components.PluginSignature, coreVersion string) error {
+	if signature.Manifest == nil {
+		return nil
+	}
+	manifest := signature.Manifest
+	if err := manifest.Validate(); err != nil {
+		return errorutils.CheckErrorf("plugin '%s': %s", signature.Name, err.Error())
+	}
+	var problems []string
+	if manifest.MinCoreVersion != "" && coreVersion == "" {
+		log.Warn(fmt.Sprintf("The version of jfrog-cli-core is unknown, so the requirement of the plugin '%s' for version %s or above isn't validated.", signature.Name, manifest.MinCoreVersion))
+	}
+	if manifest.MinCoreVersion != "" && coreVersion != "" && !version.NewVersion(coreVersion).AtLeast(manifest.MinCoreVersion) {
+		problems = append(problems, fmt.Sprintf("requires jfrog-cli-core version %s or above, but the current version is %s", manifest.MinCoreVersion, coreVersion))
+	}
+	if !manifest.SupportsPlatform(runtime.GOOS, runtime.GOARCH) {
+		problems = append(problems, fmt.Sprintf("does not support the %s-%s platform. The supported platforms are: %s", runtime.GOOS, runtime.GOARCH, strings.Join(manifest.Platforms, ", ")))
+	}
+	for _, dependency := range manifest.Dependencies {
+		dependencySignature, exists := installed[dependency.Name]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("depends on the plugin '%s', which is not installed", dependency.Name))
+			continue
+		}
+		if dependency.MinVersion != "" && !version.NewVersion(dependencySignature.Version).AtLeast(dependency.MinVersion) {
+			problems = append(problems, fmt.Sprintf("requires version %s or above of the plugin '%s', but version %s is installed", dependency.MinVersion, dependency.Name, dependencySignature.Version))
+		}
+	}
+	if len(problems) > 0 {
+		return errorutils.CheckErrorf("the plugin '%s' cannot be used:\n%s", signature.Name, strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// Returns the version of the jfrog-cli-core module the CLI was built with, or an empty string if it's unknown,
+// as when the core itself is built from sources.
+func getCoreVersion() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, module := range append([]*debug.Module{&buildInfo.Main}, buildInfo.Deps...) {
+		if module.Path != coreModulePath {
+			continue
+		}
+		if module.Replace != nil {
+			module = module.Replace
+		}
+		if module.Version == "" || module.Version == "(devel)" {
+			return ""
+		}
+		return strings.TrimPrefix(module.Version, "v")
+	}
+	return ""
+}
+
+func getPluginExecutablePath(pluginsDir, pluginName string) string {
+	return filepath.Join(pluginsDir, pluginName, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(pluginName))
+}
@@ -0,0 +1,42 @@
+package plugins
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePluginManifest(t *testing.T) {
+	installed := map[string]*components.PluginSignature{"base-plugin": {Name: "base-plugin", Version: "1.2.0"}}
+	testCases := []struct {
+		name          string
+		manifest      *components.Manifest
+		expectedError string
+	}{
+		{"no manifest", nil, ""},
+		{"valid", &components.Manifest{MinCoreVersion: "2.27.0", Dependencies: []components.PluginDependency{{Name: "base-plugin", MinVersion: "1.1.0"}}, Platforms: []string{runtime.GOOS}}, ""},
+		{"newer core", &components.Manifest{MinCoreVersion: "2.31.0"}, "requires jfrog-cli-core version 2.31.0 or above, but the current version is 2.30.1"},
+		{"unsupported platform", &components.Manifest{Platforms: []string{"plan9-386"}}, "does not support the " + runtime.GOOS + "-" + runtime.GOARCH + " platform"},
+		{"missing dependency", &components.Manifest{Dependencies: []components.PluginDependency{{Name: "missing-plugin"}}}, "'missing-plugin', which is not installed"},
+		{"old dependency", &components.Manifest{Dependencies: []components.PluginDependency{{Name: "base-plugin", MinVersion: "2.0.0"}}}, "version 1.2.0 is installed"},
+		{"invalid manifest", &components.Manifest{Services: []components.Service{"jira"}}, "unknown service 'jira'"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validatePluginManifest(&components.PluginSignature{Name: "test-plugin", Manifest: testCase.manifest}, installed, "2.30.1")
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatePluginManifestUnknownCoreVersion(t *testing.T) {
+	// The minimal core version can't be validated if the version of the core module is unknown.
+	manifest := &components.Manifest{MinCoreVersion: "2.27.0"}
+	assert.NoError(t, validatePluginManifest(&components.PluginSignature{Name: "test-plugin", Manifest: manifest}, nil, ""))
+}
@@ -0,0 +1,331 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The directory inside the plugin's directory, which holds all its installed versions:
+//
+//	plugins (dir)
+//		plugin-name (dir)
+//			bin (dir)
+//				plugin-executable (file) - a copy of the active version
+//			resources (optional dir) - a copy of the active version
+//			versions (dir)
+//				1.0.0 (dir)
+//					bin (dir)
+//					resources (optional dir)
+const PluginVersionsDirName = "versions"
+
+// The installed versions of a plugin, recorded in 'plugins.yml'.
+type InstalledPlugin struct {
+	// The version copied to the plugin's 'bin' and 'resources' directories, which is run by the CLI
+	Active string `json:"active,omitempty"`
+	// The version which was active before the active one, and is activated on rollback
+	Previous string             `json:"previous,omitempty"`
+	Versions []InstalledVersion `json:"versions,omitempty"`
+}
+
+type InstalledVersion struct {
+	Version string `json:"version"`
+	// The sha256 checksum of the plugin's executable, verified before the version is activated
+	Sha256 string `json:"sha256"`
+	// The path in Artifactory the version was installed from
+	Source string `json:"source,omitempty"`
+}
+
+func (plugin *InstalledPlugin) GetVersion(pluginVersion string) *InstalledVersion {
+	for i := range plugin.Versions {
+		if plugin.Versions[i].Version == pluginVersion {
+			return &plugin.Versions[i]
+		}
+	}
+	return nil
+}
+
+// GetInstalledPluginsVersions returns the installed versions of the plugins, by the plugins names.
+func GetInstalledPluginsVersions() (plugins map[string]*InstalledPlugin, err error) {
+	err = runWithPluginsLock(func(pluginsConfig *PluginsV1) (bool, error) {
+		plugins = pluginsConfig.Plugins
+		return false, nil
+	})
+	return
+}
+
+// AddPluginVersion moves a verified plugin version into the plugin's versions directory, and records it in 'plugins.yml'.
+// The sourceDir should contain the 'bin' directory with the plugin's executable, and optionally the 'resources' directory.
+// The version is not activated.
+func AddPluginVersion(pluginName string, installed InstalledVersion, sourceDir string) error {
+	return runWithPluginsLock(func(pluginsConfig *PluginsV1) (bool, error) {
+		versionDir, err := GetPluginVersionDir(pluginName, installed.Version)
+		if err != nil {
+			return false, err
+		}
+		if err = os.RemoveAll(versionDir); err != nil {
+			return false, errorutils.CheckError(err)
+		}
+		if err = os.MkdirAll(filepath.Dir(versionDir), 0777); err != nil {
+			return false, errorutils.CheckError(err)
+		}
+		if err = fileutils.MoveDir(sourceDir, versionDir); err != nil {
+			return false, err
+		}
+		plugin := pluginsConfig.getOrAddPlugin(pluginName)
+		if existing := plugin.GetVersion(installed.Version); existing != nil {
+			*existing = installed
+		} else {
+			plugin.Versions = append(plugin.Versions, installed)
+			sortPluginVersions(plugin.Versions)
+		}
+		return true, nil
+	})
+}
+
+// ActivatePluginVersion makes an installed version of the plugin the one run by the CLI.
+// The checksum of the version's executable is verified before it is made executable.
+func ActivatePluginVersion(pluginName, pluginVersion string) error {
+	return runWithPluginsLock(func(pluginsConfig *PluginsV1) (bool, error) {
+		plugin, exists := pluginsConfig.Plugins[pluginName]
+		if !exists || plugin.GetVersion(pluginVersion) == nil {
+			return false, errorutils.CheckErrorf("version %s of the plugin '%s' is not installed", pluginVersion, pluginName)
+		}
+		if err := activatePluginVersion(pluginName, *plugin.GetVersion(pluginVersion)); err != nil {
+			return false, err
+		}
+		if plugin.Active != pluginVersion {
+			plugin.Previous = plugin.Active
+			plugin.Active = pluginVersion
+		}
+		return true, nil
+	})
+}
+
+// RollbackPlugin activates the given version of the plugin, or the previously active version if no version is given.
+// Returns the activated version.
+func RollbackPlugin(pluginName, pluginVersion string) (activated string, err error) {
+	if pluginVersion == "" {
+		plugins, err := GetInstalledPluginsVersions()
+		if err != nil {
+			return "", err
+		}
+		plugin, exists := plugins[pluginName]
+		if !exists {
+			return "", errorutils.CheckErrorf("the plugin '%s' is not installed", pluginName)
+		}
+		if plugin.Previous == "" {
+			return "", errorutils.CheckErrorf("the plugin '%s' has no previous version to roll back to. Installed versions: %s", pluginName, joinVersions(plugin.Versions))
+		}
+		pluginVersion = plugin.Previous
+	}
+	return pluginVersion, ActivatePluginVersion(pluginName, pluginVersion)
+}
+
+// RemovePluginVersion removes an installed version of the plugin.
+// If the version is active, the previously active version is reactivated, or the plugin is deactivated if there is none.
+// Returns the reactivated version.
+func RemovePluginVersion(pluginName, pluginVersion string) (reactivated string, err error) {
+	err = runWithPluginsLock(func(pluginsConfig *PluginsV1) (bool, error) {
+		plugin, exists := pluginsConfig.Plugins[pluginName]
+		if !exists || plugin.GetVersion(pluginVersion) == nil {
+			return false, errorutils.CheckErrorf("version %s of the plugin '%s' is not installed", pluginVersion, pluginName)
+		}
+		if plugin.Active == pluginVersion {
+			if plugin.Previous != "" && plugin.Previous != pluginVersion && plugin.GetVersion(plugin.Previous) != nil {
+				if err := activatePluginVersion(pluginName, *plugin.GetVersion(plugin.Previous)); err != nil {
+					return false, err
+				}
+				reactivated = plugin.Previous
+			} else if err := deactivatePlugin(pluginName); err != nil {
+				return false, err
+			}
+			plugin.Active = reactivated
+			plugin.Previous = ""
+		} else if plugin.Previous == pluginVersion {
+			plugin.Previous = ""
+		}
+		versionDir, err := GetPluginVersionDir(pluginName, pluginVersion)
+		if err != nil {
+			return false, err
+		}
+		pluginDir := filepath.Dir(filepath.Dir(versionDir))
+		if err = os.RemoveAll(versionDir); err != nil {
+			return false, errorutils.CheckError(err)
+		}
+		var versions []InstalledVersion
+		for _, installed := range plugin.Versions {
+			if installed.Version != pluginVersion {
+				versions = append(versions, installed)
+			}
+		}
+		plugin.Versions = versions
+		if len(plugin.Versions) == 0 {
+			delete(pluginsConfig.Plugins, pluginName)
+			if err = os.RemoveAll(pluginDir); err != nil {
+				return false, errorutils.CheckError(err)
+			}
+		}
+		return true, nil
+	})
+	return
+}
+
+func GetPluginVersionDir(pluginName, pluginVersion string) (string, error) {
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginsDir, pluginName, PluginVersionsDirName, pluginVersion), nil
+}
+
+func activatePluginVersion(pluginName string, installed InstalledVersion) error {
+	versionDir, err := GetPluginVersionDir(pluginName, installed.Version)
+	if err != nil {
+		return err
+	}
+	executableName := GetLocalPluginExecutableName(pluginName)
+	if err = VerifySha256(filepath.Join(versionDir, coreutils.PluginsExecDirName, executableName), installed.Sha256); err != nil {
+		return err
+	}
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return err
+	}
+	for _, dirName := range []string{coreutils.PluginsExecDirName, coreutils.PluginsResourcesDirName} {
+		activeDir := filepath.Join(pluginsDir, pluginName, dirName)
+		if err = os.RemoveAll(activeDir); err != nil {
+			return errorutils.CheckError(err)
+		}
+		exists, err := fileutils.IsDirExists(filepath.Join(versionDir, dirName), false)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err = fileutils.CopyDir(filepath.Join(versionDir, dirName), activeDir, true, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err = os.Chmod(filepath.Join(pluginsDir, pluginName, coreutils.PluginsExecDirName, executableName), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Debug("Activated version " + installed.Version + " of the plugin " + pluginName)
+	return coreutils.ChmodPluginsDirectoryContent()
+}
+
+// Removes the copies of the active version, so the plugin is not run by the CLI.
+func deactivatePlugin(pluginName string) error {
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return err
+	}
+	for _, dirName := range []string{coreutils.PluginsExecDirName, coreutils.PluginsResourcesDirName} {
+		if err = os.RemoveAll(filepath.Join(pluginsDir, pluginName, dirName)); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	log.Debug("Deactivated the plugin " + pluginName)
+	return nil
+}
+
+// Reads 'plugins.yml' and runs the action, while holding the plugins lock.
+// If the action returns true, the updated config is saved.
+func runWithPluginsLock(action func(pluginsConfig *PluginsV1) (bool, error)) (err error) {
+	if err = CheckPluginsVersionAndConvertIfNeeded(); err != nil {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	lockDirPath, err := coreutils.GetJfrogPluginsLockDir()
+	if err != nil {
+		return
+	}
+	unlockFunc, err := lock.CreateLock(lockDirPath)
+	// Defer the lockFile.Unlock() function before throwing a possible error to avoid deadlock situations.
+	defer func() {
+		e := unlockFunc()
+		if err == nil {
+			err = e
+		}
+	}()
+	if err != nil {
+		return
+	}
+	pluginsConfig, err := readPluginsConfig()
+	if err != nil {
+		return
+	}
+	save, err := action(pluginsConfig)
+	if err != nil || !save {
+		return
+	}
+	return savePluginsConfig(pluginsConfig)
+}
+
+func readPluginsConfig() (*PluginsV1, error) {
+	content, err := getPluginsConfigFileContent()
+	if err != nil {
+		return nil, err
+	}
+	pluginsConfig := &PluginsV1{Version: coreutils.GetPluginsConfigVersion()}
+	if len(content) > 0 {
+		if err = json.Unmarshal(content, pluginsConfig); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	if pluginsConfig.Plugins == nil {
+		pluginsConfig.Plugins = make(map[string]*InstalledPlugin)
+	}
+	return pluginsConfig, nil
+}
+
+func savePluginsConfig(pluginsConfig *PluginsV1) error {
+	pluginsFilePath, err := getPluginsFilePath()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(pluginsFilePath), 0777); err != nil {
+		return errorutils.CheckError(err)
+	}
+	content, err := json.Marshal(pluginsConfig)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(pluginsFilePath, content, 0600))
+}
+
+func (pluginsConfig *PluginsV1) getOrAddPlugin(pluginName string) *InstalledPlugin {
+	plugin, exists := pluginsConfig.Plugins[pluginName]
+	if !exists {
+		plugin = new(InstalledPlugin)
+		pluginsConfig.Plugins[pluginName] = plugin
+	}
+	return plugin
+}
+
+// Sorts the versions from the oldest to the newest.
+func sortPluginVersions(versions []InstalledVersion) {
+	sort.Slice(versions, func(i, j int) bool {
+		return !version.NewVersion(versions[i].Version).AtLeast(versions[j].Version)
+	})
+}
+
+func joinVersions(versions []InstalledVersion) string {
+	joined := ""
+	for i, installed := range versions {
+		if i > 0 {
+			joined += ", "
+		}
+		joined += installed.Version
+	}
+	return joined
+}
@@ -0,0 +1,173 @@
+package plugins
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPluginName = "test-plugin"
+
+// Creates a directory with the plugin's executable, as it is downloaded, and returns it with the executable's checksum.
+func createPluginVersionDir(t *testing.T, content string) (string, string) {
+	versionDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(versionDir, coreutils.PluginsExecDirName), 0777))
+	assert.NoError(t, os.WriteFile(filepath.Join(versionDir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(testPluginName)), []byte(content), 0600))
+	digest := sha256.Sum256([]byte(content))
+	return versionDir, hex.EncodeToString(digest[:])
+}
+
+func addTestPluginVersion(t *testing.T, pluginVersion string) {
+	versionDir, sha256 := createPluginVersionDir(t, "plugin "+pluginVersion)
+	assert.NoError(t, AddPluginVersion(testPluginName, InstalledVersion{Version: pluginVersion, Sha256: sha256}, versionDir))
+}
+
+func assertActivePluginContent(t *testing.T, expected string) {
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(pluginsDir, testPluginName, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(testPluginName)))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func TestPluginVersions(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	addTestPluginVersion(t, "1.10.0")
+	addTestPluginVersion(t, "1.2.0")
+	installed, err := GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0, 1.10.0", joinVersions(installed[testPluginName].Versions))
+	assert.Empty(t, installed[testPluginName].Active)
+
+	// Rolling back requires a previously active version.
+	assert.NoError(t, ActivatePluginVersion(testPluginName, "1.2.0"))
+	_, err = RollbackPlugin(testPluginName, "")
+	assert.ErrorContains(t, err, "no previous version")
+
+	assert.NoError(t, ActivatePluginVersion(testPluginName, "1.10.0"))
+	assertActivePluginContent(t, "plugin 1.10.0")
+	activated, err := RollbackPlugin(testPluginName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", activated)
+	assertActivePluginContent(t, "plugin 1.2.0")
+	installed, err = GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, InstalledPlugin{Active: "1.2.0", Previous: "1.10.0", Versions: installed[testPluginName].Versions}, *installed[testPluginName])
+
+	assert.ErrorContains(t, ActivatePluginVersion(testPluginName, "2.0.0"), "is not installed")
+
+	// A tampered version is not activated.
+	versionDir, err := GetPluginVersionDir(testPluginName, "1.10.0")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(versionDir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(testPluginName)), []byte("tampered"), 0600))
+	assert.ErrorContains(t, ActivatePluginVersion(testPluginName, "1.10.0"), "tampered with")
+	assertActivePluginContent(t, "plugin 1.2.0")
+}
+
+func TestRemovePluginVersion(t *testing.T) {
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	addTestPluginVersion(t, "1.0.0")
+	addTestPluginVersion(t, "1.1.0")
+	assert.NoError(t, ActivatePluginVersion(testPluginName, "1.0.0"))
+	assert.NoError(t, ActivatePluginVersion(testPluginName, "1.1.0"))
+
+	// Removing the active version reactivates the previous one.
+	reactivated, err := RemovePluginVersion(testPluginName, "1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", reactivated)
+	assertActivePluginContent(t, "plugin 1.0.0")
+	installed, err := GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", joinVersions(installed[testPluginName].Versions))
+
+	// Removing the only version deactivates the plugin.
+	reactivated, err = RemovePluginVersion(testPluginName, "1.0.0")
+	assert.NoError(t, err)
+	assert.Empty(t, reactivated)
+	installed, err = GetInstalledPluginsVersions()
+	assert.NoError(t, err)
+	assert.Empty(t, installed)
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(pluginsDir, testPluginName))
+}
+
+func TestActivatedPluginPermissions(t *testing.T) {
+	if coreutils.IsWindows() {
+		t.Skip("File permissions are not supported on Windows.")
+	}
+	cleanUpTempEnv := createTempEnvForPluginsTests(t)
+	defer cleanUpTempEnv()
+
+	addTestPluginVersion(t, "1.0.0")
+	assert.NoError(t, ActivatePluginVersion(testPluginName, "1.0.0"))
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	assert.NoError(t, err)
+	info, err := os.Stat(filepath.Join(pluginsDir, testPluginName, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(testPluginName)))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestVerifySignature(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "plugin")
+	content := []byte("plugin content")
+	assert.NoError(t, os.WriteFile(filePath, content, 0600))
+	digest := sha256.Sum256(content)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	assert.NoError(t, err)
+	ed25519PublicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		publicKey interface{}
+		signature []byte
+	}{
+		{"ecdsa", &ecdsaKey.PublicKey, ecdsaSignature},
+		{"ed25519", ed25519PublicKey, ed25519.Sign(ed25519Key, content)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			publicKeyPath := filepath.Join(tmpDir, testCase.name+".pub")
+			encodedKey, err := x509.MarshalPKIXPublicKey(testCase.publicKey)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedKey}), 0600))
+			signaturePath := filepath.Join(tmpDir, testCase.name+".sig")
+			assert.NoError(t, os.WriteFile(signaturePath, []byte(base64.StdEncoding.EncodeToString(testCase.signature)), 0600))
+			assert.NoError(t, VerifySignature(filePath, signaturePath, publicKeyPath))
+
+			invalidSignature := append([]byte{}, testCase.signature...)
+			invalidSignature[len(invalidSignature)-1] ^= 1
+			assert.NoError(t, os.WriteFile(signaturePath, []byte(base64.StdEncoding.EncodeToString(invalidSignature)), 0600))
+			assert.ErrorContains(t, VerifySignature(filePath, signaturePath, publicKeyPath), "could not be verified")
+		})
+	}
+}
+
+func TestVerifySha256(t *testing.T) {
+	dir, sha256 := createPluginVersionDir(t, "content")
+	filePath := filepath.Join(dir, coreutils.PluginsExecDirName, GetLocalPluginExecutableName(testPluginName))
+	assert.NoError(t, VerifySha256(filePath, sha256))
+	assert.Error(t, VerifySha256(filePath, ""))
+	assert.Error(t, VerifySha256(filePath, "0123"))
+}
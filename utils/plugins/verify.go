@@ -0,0 +1,86 @@
+package plugins
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+// VerifySha256 verifies the sha256 checksum of the file, before it is made executable.
+func VerifySha256(filePath, expectedSha256 string) error {
+	if expectedSha256 == "" {
+		return errorutils.CheckErrorf("no sha256 checksum is available to verify %s", filePath)
+	}
+	details, err := fileutils.GetFileDetails(filePath, true)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(details.Checksum.Sha256, expectedSha256) {
+		return errorutils.CheckErrorf("the sha256 checksum of %s is %s, but %s was expected. The file may have been tampered with", filePath, details.Checksum.Sha256, expectedSha256)
+	}
+	return nil
+}
+
+// VerifySignature verifies a detached signature of the file, using a PEM encoded public key.
+// The signature should be base64 encoded, as produced by 'cosign sign-blob' or 'openssl dgst -sha256 -sign | base64'.
+// ECDSA and RSA (PKCS #1 v1.5) signatures are of the sha256 digest of the file. Ed25519 signatures are of the file's content.
+func VerifySignature(filePath, signaturePath, publicKeyPath string) error {
+	publicKey, err := readPublicKey(publicKeyPath)
+	if err != nil {
+		return err
+	}
+	encodedSignature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedSignature)))
+	if err != nil {
+		return errorutils.CheckErrorf("the signature %s is not base64 encoded: %s", signaturePath, err.Error())
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	digest := sha256.Sum256(content)
+	verified := false
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		verified = ecdsa.VerifyASN1(key, digest[:], signature)
+	case *rsa.PublicKey:
+		verified = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case ed25519.PublicKey:
+		verified = ed25519.Verify(key, content, signature)
+	default:
+		return errorutils.CheckErrorf("unsupported public key type in %s. Supported types are ECDSA, RSA and Ed25519", publicKeyPath)
+	}
+	if !verified {
+		return errorutils.CheckErrorf("the signature of %s could not be verified with the public key %s", filePath, publicKeyPath)
+	}
+	return nil
+}
+
+func readPublicKey(publicKeyPath string) (crypto.PublicKey, error) {
+	content, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errorutils.CheckErrorf("the public key %s is not PEM encoded", publicKeyPath)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed parsing the public key %s: %s", publicKeyPath, err.Error())
+	}
+	return publicKey, nil
+}
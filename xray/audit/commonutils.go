@@ -13,6 +13,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	xraycommands "github.com/jfrog/jfrog-cli-core/v2/xray/commands"
+	xrutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	ioUtils "github.com/jfrog/jfrog-client-go/utils/io"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -90,6 +91,24 @@ func Audit(modulesDependencyTrees []*services.GraphNode, xrayGraphScanPrams serv
 	return
 }
 
+// AuditOffline scans the dependency trees with the offline vulnerabilities database, instead of Xray.
+func AuditOffline(modulesDependencyTrees []*services.GraphNode, offlineDb *xrutils.OfflineDb, technology coreutils.Technology) (results []services.ScanResponse, err error) {
+	if len(modulesDependencyTrees) == 0 {
+		err = errorutils.CheckErrorf("No dependencies were found. Please try to build your project and re-run the audit command.")
+		return
+	}
+	for _, moduleDependencyTree := range modulesDependencyTrees {
+		moduleName := moduleDependencyTree.Id[strings.Index(moduleDependencyTree.Id, "//")+2:]
+		log.Info("Scanning module " + moduleName + " with the offline vulnerabilities database...")
+		scanResults := offlineDb.ScanGraph(moduleDependencyTree)
+		for i := range scanResults.Vulnerabilities {
+			scanResults.Vulnerabilities[i].Technology = technology.ToString()
+		}
+		results = append(results, *scanResults)
+	}
+	return
+}
+
 func CreateTestWorkspace(t *testing.T, sourceDir string) (string, func()) {
 	tempDirPath, createTempDirCallback := tests.CreateTempDirWithCallbackAndAssert(t)
 	assert.NoError(t, fileutils.CopyDir(filepath.Join("..", "..", "commands", "testdata", sourceDir), tempDirPath, true, nil))
@@ -0,0 +1,119 @@
+package java
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const mavenTestScope = "test"
+
+var (
+	// Matches the string notation of Gradle test dependencies, for example: testImplementation 'junit:junit:4.13'
+	gradleTestDependencyRegexp = regexp.MustCompile(`(?m)^\s*(?:test|androidTest)\w*\s*\(?\s*['"]([^'":\s]+):([^'":\s]+)`)
+	// Matches the map notation of Gradle test dependencies, for example: testImplementation group: 'junit', name: 'junit', version: '4.13'
+	gradleTestDependencyMapRegexp = regexp.MustCompile(`(?m)^\s*(?:test|androidTest)\w*\s*\(?\s*group\s*[:=]\s*['"]([^'"]+)['"]\s*,\s*name\s*[:=]\s*['"]([^'"]+)['"]`)
+)
+
+type pomProject struct {
+	GroupId string `xml:"groupId"`
+	Parent  struct {
+		GroupId string `xml:"groupId"`
+	} `xml:"parent"`
+	Dependencies []struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Scope      string `xml:"scope"`
+	} `xml:"dependencies>dependency"`
+}
+
+// GetMavenDevDependencies returns the 'groupId:artifactId' of the test scoped dependencies, declared in the pom.xml files of the project in the current directory.
+func GetMavenDevDependencies() ([]string, error) {
+	devDependencies := make(map[string]bool)
+	err := walkProjectFiles(func(path string, fileName string) error {
+		if fileName != "pom.xml" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		var project pomProject
+		if err = xml.Unmarshal(content, &project); err != nil {
+			return errorutils.CheckErrorf("failed to parse %s: %s", path, err.Error())
+		}
+		projectGroupId := project.GroupId
+		if projectGroupId == "" {
+			projectGroupId = project.Parent.GroupId
+		}
+		for _, dependency := range project.Dependencies {
+			if strings.TrimSpace(dependency.Scope) != mavenTestScope {
+				continue
+			}
+			groupId := strings.TrimSpace(dependency.GroupId)
+			if groupId == "${project.groupId}" || groupId == "${groupId}" {
+				groupId = projectGroupId
+			}
+			devDependencies[groupId+":"+strings.TrimSpace(dependency.ArtifactId)] = true
+		}
+		return nil
+	})
+	return toSortedSlice(devDependencies), err
+}
+
+// GetGradleDevDependencies returns the 'group:name' of the dependencies of the test configurations, declared in the build scripts of the project in the current directory.
+func GetGradleDevDependencies() ([]string, error) {
+	devDependencies := make(map[string]bool)
+	err := walkProjectFiles(func(path string, fileName string) error {
+		if fileName != "build.gradle" && fileName != "build.gradle.kts" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		for _, regex := range []*regexp.Regexp{gradleTestDependencyRegexp, gradleTestDependencyMapRegexp} {
+			for _, match := range regex.FindAllStringSubmatch(string(content), -1) {
+				devDependencies[match[1]+":"+match[2]] = true
+			}
+		}
+		return nil
+	})
+	return toSortedSlice(devDependencies), err
+}
+
+// Runs the handler on the files of the project in the current directory, including the files of its sub-modules.
+// Hidden and build output directories are skipped.
+func walkProjectFiles(handler func(path string, fileName string) error) error {
+	currentDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckError(filepath.WalkDir(currentDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != currentDir && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "target" || entry.Name() == "build" || entry.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return handler(path, entry.Name())
+	}))
+}
+
+func toSortedSlice(set map[string]bool) (values []string) {
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return
+}
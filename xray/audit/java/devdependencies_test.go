@@ -0,0 +1,45 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMavenDevDependencies(t *testing.T) {
+	_, cleanUp := audit.CreateTestWorkspace(t, "maven-example")
+	defer cleanUp()
+
+	// The test dependencies of the root project and of its modules
+	devDependencies, err := GetMavenDevDependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"junit:junit", "org.testng:testng"}, devDependencies)
+}
+
+func TestGetGradleDevDependencies(t *testing.T) {
+	_, cleanUp := audit.CreateTestWorkspace(t, "gradle-example-ci-server")
+	defer cleanUp()
+
+	devDependencies, err := GetGradleDevDependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"junit:junit"}, devDependencies)
+}
+
+func TestGradleTestDependencyRegexp(t *testing.T) {
+	buildScript := `dependencies {
+    implementation 'org.apache.commons:commons-lang3:3.12.0'
+    testImplementation 'org.junit.jupiter:junit-jupiter:5.9.0'
+    testRuntimeOnly("org.junit.platform:junit-platform-launcher:1.9.0")
+    androidTestImplementation group: 'androidx.test', name: 'runner', version: '1.5.0'
+    testImplementation project(':shared')
+}`
+	var devDependencies []string
+	for _, match := range gradleTestDependencyRegexp.FindAllStringSubmatch(buildScript, -1) {
+		devDependencies = append(devDependencies, match[1]+":"+match[2])
+	}
+	for _, match := range gradleTestDependencyMapRegexp.FindAllStringSubmatch(buildScript, -1) {
+		devDependencies = append(devDependencies, match[1]+":"+match[2])
+	}
+	assert.Equal(t, []string{"org.junit.jupiter:junit-jupiter", "org.junit.platform:junit-platform-launcher", "androidx.test:runner"}, devDependencies)
+}
@@ -1,10 +1,16 @@
 package npm
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	buildinfo "github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/audit"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 )
@@ -51,3 +57,27 @@ func parseNpmDependenciesList(dependencies []buildinfo.Dependency, packageInfo *
 	}
 	return audit.BuildXrayDependencyTree(treeMap, npmPackageTypeIdentifier+packageInfo.BuildInfoModuleId())
 }
+
+// GetDevDependencies returns the names of the devDependencies declared in the package.json file of the current directory.
+// It is used by Yarn projects as well.
+func GetDevDependencies() (devDependencies []string, err error) {
+	currentDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return
+	}
+	content, err := os.ReadFile(filepath.Join(currentDir, "package.json"))
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	var packageJson struct {
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err = json.Unmarshal(content, &packageJson); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the package.json file in %s: %s", currentDir, err.Error())
+	}
+	for name := range packageJson.DevDependencies {
+		devDependencies = append(devDependencies, name)
+	}
+	sort.Strings(devDependencies)
+	return
+}
@@ -3,6 +3,7 @@ package npm
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	biutils "github.com/jfrog/build-info-go/build/utils"
@@ -76,3 +77,14 @@ func TestParseNpmDependenciesList(t *testing.T) {
 	}
 
 }
+
+func TestGetDevDependencies(t *testing.T) {
+	tempDirPath, cleanUp := audit.CreateTestWorkspace(t, "npm")
+	defer cleanUp()
+	packageJson := `{"name": "root", "version": "0.0.0", "dependencies": {"lodash": "^4.17.21"}, "devDependencies": {"jest": "^29.0.0", "@types/node": "^18.0.0"}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDirPath, "package.json"), []byte(packageJson), 0600))
+
+	devDependencies, err := GetDevDependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@types/node", "jest"}, devDependencies)
+}
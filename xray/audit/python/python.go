@@ -5,7 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/jfrog/build-info-go/utils/pythonutils"
@@ -15,12 +17,16 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	clientLog "github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/spf13/viper"
 )
 
 const (
 	pythonPackageTypeIdentifier = "pypi://"
 )
 
+// Python package names are compared after replacing runs of '-', '_' and '.' with a single '-', as defined by PEP 503
+var pythonNameSeparatorsRegexp = regexp.MustCompile(`[-_.]+`)
+
 func BuildDependencyTree(pythonTool pythonutils.PythonTool, requirementsFile string) (dependencyTree []*services.GraphNode, err error) {
 	dependenciesGraph, directDependenciesList, err := getDependencies(pythonTool, requirementsFile)
 	if err != nil {
@@ -230,3 +236,47 @@ func populatePythonDependencyTree(currNode *services.GraphNode, dependenciesGrap
 		populatePythonDependencyTree(childNode, dependenciesGraph)
 	}
 }
+
+// GetDevDependencies returns the names of the dev dependencies of the Pipenv or Poetry project in the current directory.
+// Pipenv dev dependencies are declared in the [dev-packages] section of the Pipfile.
+// Poetry dev dependencies are declared in the dev-dependencies section or in the dependency groups of the pyproject.toml file.
+func GetDevDependencies(pythonTool pythonutils.PythonTool) ([]string, error) {
+	var configFile string
+	var devSectionsKeys []string
+	switch pythonTool {
+	case pythonutils.Pipenv:
+		configFile, devSectionsKeys = "Pipfile", []string{"dev-packages"}
+	case pythonutils.Poetry:
+		configFile, devSectionsKeys = "pyproject.toml", []string{"tool.poetry.dev-dependencies"}
+	default:
+		return nil, errorutils.CheckErrorf("dev dependencies are not supported by %s", pythonTool)
+	}
+	pythonConfig := viper.New()
+	pythonConfig.SetConfigType("toml")
+	pythonConfig.SetConfigFile(configFile)
+	if err := pythonConfig.ReadInConfig(); err != nil {
+		return nil, errorutils.CheckErrorf("failed to read %s: %s", configFile, err.Error())
+	}
+	if pythonTool == pythonutils.Poetry {
+		// All the dependency groups are optional, unlike the main dependencies in the tool.poetry.dependencies section
+		for group := range pythonConfig.GetStringMap("tool.poetry.group") {
+			devSectionsKeys = append(devSectionsKeys, "tool.poetry.group."+group+".dependencies")
+		}
+	}
+	devDependencies := make(map[string]bool)
+	for _, sectionKey := range devSectionsKeys {
+		for name := range pythonConfig.GetStringMap(sectionKey) {
+			devDependencies[normalizePackageName(name)] = true
+		}
+	}
+	var devDependenciesNames []string
+	for name := range devDependencies {
+		devDependenciesNames = append(devDependenciesNames, name)
+	}
+	sort.Strings(devDependenciesNames)
+	return devDependenciesNames, nil
+}
+
+func normalizePackageName(name string) string {
+	return pythonNameSeparatorsRegexp.ReplaceAllString(strings.ToLower(name), "-")
+}
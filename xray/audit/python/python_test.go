@@ -1,6 +1,7 @@
 package python
 
 import (
+	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
@@ -135,3 +136,28 @@ func TestBuildPoetryDependencyList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDevDependencies(t *testing.T) {
+	// The Pipfile of the project has no dev packages
+	_, cleanUp := audit.CreateTestWorkspace(t, "pipenv-project")
+	devDependencies, err := GetDevDependencies(pythonutils.Pipenv)
+	assert.NoError(t, err)
+	assert.Empty(t, devDependencies)
+	cleanUp()
+
+	tempDirPath, cleanUp := audit.CreateTestWorkspace(t, "poetry-project")
+	defer cleanUp()
+	devDependencies, err = GetDevDependencies(pythonutils.Poetry)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pytest"}, devDependencies)
+
+	// Dependency groups are dev dependencies as well
+	pyproject, err := os.OpenFile(filepath.Join(tempDirPath, "pyproject.toml"), os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = pyproject.WriteString("\n[tool.poetry.group.test.dependencies]\nTyping_Extensions = \"^4.0\"\n")
+	assert.NoError(t, err)
+	assert.NoError(t, pyproject.Close())
+	devDependencies, err = GetDevDependencies(pythonutils.Poetry)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pytest", "typing-extensions"}, devDependencies)
+}
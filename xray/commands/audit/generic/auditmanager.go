@@ -36,10 +36,30 @@ func GenericAudit(
 	ignoreConfigFile bool,
 	workingDirs []string,
 	technologies ...string) (results []services.ScanResponse, isMultipleRoot bool, err error) {
+	scanFunc := func(dependencyTrees []*services.GraphNode, tech coreutils.Technology) ([]services.ScanResponse, error) {
+		return audit.Audit(dependencyTrees, xrayGraphScanParams, serverDetails, progress, tech)
+	}
+	return genericAudit(scanFunc, excludeTestDeps, useWrapper, insecureTls, args, progress, requirementsFile, ignoreConfigFile, workingDirs, technologies...)
+}
+
+// Scans the dependency trees of a technology, and returns the results
+type scanDependencyTreesFunc func(dependencyTrees []*services.GraphNode, tech coreutils.Technology) ([]services.ScanResponse, error)
+
+func genericAudit(
+	scanFunc scanDependencyTreesFunc,
+	excludeTestDeps,
+	useWrapper,
+	insecureTls bool,
+	args []string,
+	progress ioUtils.ProgressMgr,
+	requirementsFile string,
+	ignoreConfigFile bool,
+	workingDirs []string,
+	technologies ...string) (results []services.ScanResponse, isMultipleRoot bool, err error) {
 
 	if len(workingDirs) == 0 {
 		log.Info("Auditing project: ")
-		return doAudit(xrayGraphScanParams, serverDetails, excludeTestDeps, useWrapper, insecureTls, args, progress, requirementsFile, ignoreConfigFile, technologies...)
+		return doAudit(scanFunc, excludeTestDeps, useWrapper, insecureTls, args, progress, requirementsFile, ignoreConfigFile, technologies...)
 	}
 	projectDir, err := os.Getwd()
 	if errorutils.CheckError(err) != nil {
@@ -66,7 +86,7 @@ func GenericAudit(
 			errorList = append(errorList, fmt.Sprintf("the audit command couldn't change the current working directory to the following path: %s\n%s", absWd, e.Error()))
 			continue
 		}
-		techResults, isMultipleRootProject, e := doAudit(xrayGraphScanParams, serverDetails, excludeTestDeps, useWrapper, insecureTls, args, progress, requirementsFile, ignoreConfigFile, technologies...)
+		techResults, isMultipleRootProject, e := doAudit(scanFunc, excludeTestDeps, useWrapper, insecureTls, args, progress, requirementsFile, ignoreConfigFile, technologies...)
 		if e != nil {
 			// Save the error but continue to the other paths
 			errorList = append(errorList, fmt.Sprintf("audit command in %s failed:\n%s", absWd, e.Error()))
@@ -83,8 +103,7 @@ func GenericAudit(
 
 //  Audits the project found in the current directory using Xray.
 func doAudit(
-	xrayGraphScanParams services.XrayGraphScanParams,
-	serverDetails *config.ServerDetails,
+	scanFunc scanDependencyTreesFunc,
 	excludeTestDeps,
 	useWrapper,
 	insecureTls bool,
@@ -104,36 +123,17 @@ func doAudit(
 	}
 	var errorList []string
 	for _, tech := range coreutils.ToTechnologies(technologies) {
-		var dependencyTrees []*services.GraphNode
-		var e error
+		if tech == coreutils.Dotnet {
+			continue
+		}
 		if progress != nil {
 			progress.SetHeadlineMsg(fmt.Sprintf("Calculating %v dependencies", tech.ToFormal()))
 		}
-		switch tech {
-		case coreutils.Maven:
-			dependencyTrees, e = java.BuildMvnDependencyTree(insecureTls, ignoreConfigFile)
-		case coreutils.Gradle:
-			dependencyTrees, e = java.BuildGradleDependencyTree(excludeTestDeps, useWrapper, ignoreConfigFile)
-		case coreutils.Npm:
-			dependencyTrees, e = npm.BuildDependencyTree(args)
-		case coreutils.Yarn:
-			dependencyTrees, e = yarn.BuildDependencyTree()
-		case coreutils.Go:
-			dependencyTrees, e = _go.BuildDependencyTree()
-		case coreutils.Pipenv, coreutils.Pip, coreutils.Poetry:
-			dependencyTrees, e = python.BuildDependencyTree(pythonutils.PythonTool(tech), requirementsFile)
-		case coreutils.Dotnet:
-			continue
-		case coreutils.Nuget:
-			dependencyTrees, e = nuget.BuildDependencyTree()
-		default:
-			e = errors.New(string(tech) + " is currently not supported")
-		}
-
+		dependencyTrees, e := buildDependencyTrees(tech, excludeTestDeps, useWrapper, insecureTls, args, requirementsFile, ignoreConfigFile)
 		var techResults []services.ScanResponse
 		if e == nil {
 			// If building the dependency tree was successful, run Xray scan.
-			techResults, e = audit.Audit(dependencyTrees, xrayGraphScanParams, serverDetails, progress, tech)
+			techResults, e = scanFunc(dependencyTrees, tech)
 		}
 
 		if e != nil {
@@ -150,6 +150,47 @@ func doAudit(
 	return
 }
 
+// Build the dependency trees of the project of the given technology, found in the current directory.
+func buildDependencyTrees(tech coreutils.Technology, excludeTestDeps, useWrapper, insecureTls bool, args []string, requirementsFile string,
+	ignoreConfigFile bool) (dependencyTrees []*services.GraphNode, err error) {
+	switch tech {
+	case coreutils.Maven:
+		return java.BuildMvnDependencyTree(insecureTls, ignoreConfigFile)
+	case coreutils.Gradle:
+		return java.BuildGradleDependencyTree(excludeTestDeps, useWrapper, ignoreConfigFile)
+	case coreutils.Npm:
+		return npm.BuildDependencyTree(args)
+	case coreutils.Yarn:
+		return yarn.BuildDependencyTree()
+	case coreutils.Go:
+		return _go.BuildDependencyTree()
+	case coreutils.Pipenv, coreutils.Pip, coreutils.Poetry:
+		return python.BuildDependencyTree(pythonutils.PythonTool(tech), requirementsFile)
+	case coreutils.Nuget:
+		return nuget.BuildDependencyTree()
+	default:
+		return nil, errors.New(string(tech) + " is currently not supported")
+	}
+}
+
+// Returns the names of the direct dev and test dependencies of the project of the given technology, found in the current directory.
+// Technologies which have no notion of dev dependencies return no dependencies, so all their issues are evaluated by the policy.
+func getDevDependencies(tech coreutils.Technology) ([]string, error) {
+	switch tech {
+	case coreutils.Maven:
+		return java.GetMavenDevDependencies()
+	case coreutils.Gradle:
+		return java.GetGradleDevDependencies()
+	case coreutils.Npm, coreutils.Yarn:
+		return npm.GetDevDependencies()
+	case coreutils.Pipenv, coreutils.Poetry:
+		return python.GetDevDependencies(pythonutils.PythonTool(tech))
+	default:
+		log.Warn(fmt.Sprintf("%s projects have no dev dependencies, so the allowDevDependencies option of the policy doesn't apply to them.", tech.ToFormal()))
+		return nil, nil
+	}
+}
+
 func detectedTechnologies() (technologies []string, err error) {
 	wd, err := os.Getwd()
 	if errorutils.CheckError(err) != nil {
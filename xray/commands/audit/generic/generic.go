@@ -3,6 +3,10 @@ package audit
 import (
 	"os"
 
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+
 	ioUtils "github.com/jfrog/jfrog-client-go/utils/io"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -29,6 +33,17 @@ type GenericAuditCommand struct {
 	technologies            []string
 	requirementsFile        string
 	progress                ioUtils.ProgressMgr
+	// Recursively audit all the sub-projects found in the working directories, and group the results by directory and technology
+	recursive bool
+	threads   int
+	// A local policy file, which determines whether the results fail the build
+	policyFile string
+	policy     *xrutils.Policy
+	// The direct dev dependencies of the audited projects, collected only if the policy allows dev dependencies
+	devDependencies []string
+	// The path to the data downloaded by the offline-update command. If set, the dependencies are scanned locally instead of by Xray.
+	offlineDbPath string
+	offlineDb     *xrutils.OfflineDb
 }
 
 func NewGenericAuditCommand() *GenericAuditCommand {
@@ -101,18 +116,42 @@ func (auditCmd *GenericAuditCommand) CreateXrayGraphScanParams() services.XrayGr
 		params.ProjectKey = auditCmd.projectKey
 	}
 	params.IncludeVulnerabilities = auditCmd.IncludeVulnerabilities
-	params.IncludeLicenses = auditCmd.IncludeLicenses
+	params.IncludeLicenses = auditCmd.IncludeLicenses || (auditCmd.policy != nil && auditCmd.policy.HasLicenseRules())
 	return params
 }
 
+func (auditCmd *GenericAuditCommand) SetRecursive(recursive bool) *GenericAuditCommand {
+	auditCmd.recursive = recursive
+	return auditCmd
+}
+
+func (auditCmd *GenericAuditCommand) SetThreads(threads int) *GenericAuditCommand {
+	auditCmd.threads = threads
+	return auditCmd
+}
+
+func (auditCmd *GenericAuditCommand) SetPolicyFile(policyFile string) *GenericAuditCommand {
+	auditCmd.policyFile = policyFile
+	return auditCmd
+}
+
+func (auditCmd *GenericAuditCommand) SetOfflineDbPath(offlineDbPath string) *GenericAuditCommand {
+	auditCmd.offlineDbPath = offlineDbPath
+	return auditCmd
+}
+
 func (auditCmd *GenericAuditCommand) Run() (err error) {
-	server, err := auditCmd.ServerDetails()
-	if err != nil {
+	if err = auditCmd.loadPolicy(); err != nil {
+		return
+	}
+	if err = auditCmd.loadOfflineDb(); err != nil {
 		return
 	}
-	results, isMultipleRootProject, auditErr := GenericAudit(
-		auditCmd.CreateXrayGraphScanParams(),
-		server,
+	if auditCmd.recursive {
+		return auditCmd.runRecursive()
+	}
+	results, isMultipleRootProject, auditErr := genericAudit(
+		auditCmd.collectDevDependencies(auditCmd.getScanFunc(auditCmd.progress)),
 		auditCmd.excludeTestDependencies,
 		auditCmd.useWrapper,
 		auditCmd.insecureTls,
@@ -153,10 +192,102 @@ func (auditCmd *GenericAuditCommand) Run() (err error) {
 	// Only in case Xray's context was given (!auditCmd.IncludeVulnerabilities) and the user asked to fail the build accordingly, do so.
 	if auditCmd.Fail && !auditCmd.IncludeVulnerabilities && xrutils.CheckIfFailBuild(results) {
 		err = xrutils.NewFailBuildError()
+		return
+	}
+	if auditCmd.policy != nil {
+		err = xrutils.CheckPolicy(auditCmd.policy, results, auditCmd.devDependencies, auditCmd.IncludeVulnerabilities, isMultipleRootProject)
 	}
 	return
 }
 
+// Audit all the sub-projects in the working directories, and print the results grouped by directory and technology.
+func (auditCmd *GenericAuditCommand) runRecursive() (err error) {
+	groups, auditErrors, err := auditCmd.auditSubProjects()
+	if err != nil {
+		return
+	}
+	if auditCmd.progress != nil {
+		if err = auditCmd.progress.Quit(); err != nil {
+			return
+		}
+	}
+	var results []services.ScanResponse
+	for _, group := range groups {
+		results = append(results, group.Results...)
+	}
+	if len(auditErrors) == 0 || !xrutils.IsEmptyScanResponse(results) {
+		if err = xrutils.PrintGroupedScanResults(groups, auditErrors, auditCmd.OutputFormat, auditCmd.IncludeVulnerabilities, auditCmd.IncludeLicenses, auditCmd.PrintExtendedTable); err != nil {
+			return
+		}
+	}
+	if err = auditErrorsToError(auditErrors); err != nil {
+		return
+	}
+	if auditCmd.Fail && !auditCmd.IncludeVulnerabilities && xrutils.CheckIfFailBuild(results) {
+		err = xrutils.NewFailBuildError()
+		return
+	}
+	if auditCmd.policy != nil {
+		err = xrutils.CheckPolicyForGroups(auditCmd.policy, groups, auditCmd.IncludeVulnerabilities)
+	}
+	return
+}
+
+func (auditCmd *GenericAuditCommand) loadPolicy() (err error) {
+	if auditCmd.policyFile == "" {
+		return
+	}
+	if auditCmd.policy, err = xrutils.LoadPolicy(auditCmd.policyFile); err != nil {
+		return
+	}
+	return
+}
+
+func (auditCmd *GenericAuditCommand) loadOfflineDb() (err error) {
+	if auditCmd.offlineDbPath == "" {
+		return
+	}
+	if len(auditCmd.watches) > 0 || auditCmd.projectKey != "" || auditCmd.targetRepoPath != "" {
+		return errorutils.CheckErrorf("the watches, project and repository path options require Xray, and can't be used with an offline vulnerabilities database")
+	}
+	if auditCmd.IncludeLicenses {
+		log.Warn("Licenses aren't included in the offline vulnerabilities database, and won't be shown.")
+	}
+	// Without Xray policies, only vulnerabilities can be found
+	auditCmd.IncludeVulnerabilities = true
+	auditCmd.offlineDb, err = xrutils.LoadOfflineDb(auditCmd.offlineDbPath)
+	return
+}
+
+// Returns the function that scans the dependency trees, either with Xray or with the offline vulnerabilities database.
+func (auditCmd *GenericAuditCommand) getScanFunc(progress ioUtils.ProgressMgr) scanDependencyTreesFunc {
+	if auditCmd.offlineDb != nil {
+		return func(dependencyTrees []*services.GraphNode, tech coreutils.Technology) ([]services.ScanResponse, error) {
+			return audit.AuditOffline(dependencyTrees, auditCmd.offlineDb, tech)
+		}
+	}
+	xrayGraphScanParams := auditCmd.CreateXrayGraphScanParams()
+	return func(dependencyTrees []*services.GraphNode, tech coreutils.Technology) ([]services.ScanResponse, error) {
+		return audit.Audit(dependencyTrees, xrayGraphScanParams, auditCmd.serverDetails, progress, tech)
+	}
+}
+
+// Wraps the scan function, so that the dev dependencies of each audited project are collected for the policy evaluation.
+// The scan function runs in the directory of the audited project.
+func (auditCmd *GenericAuditCommand) collectDevDependencies(scanFunc scanDependencyTreesFunc) scanDependencyTreesFunc {
+	if auditCmd.policy == nil || !auditCmd.policy.AllowDevDependencies {
+		return scanFunc
+	}
+	return func(dependencyTrees []*services.GraphNode, tech coreutils.Technology) ([]services.ScanResponse, error) {
+		devDependencies, err := getDevDependencies(tech)
+		if err != nil {
+			return nil, err
+		}
+		auditCmd.devDependencies = append(auditCmd.devDependencies, devDependencies...)
+		return scanFunc(dependencyTrees, tech)
+	}
+}
+
 func (auditCmd *GenericAuditCommand) CommandName() string {
 	return "generic_audit"
 }
@@ -0,0 +1,235 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/parallel"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	xrutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
+)
+
+const defaultAuditThreads = 3
+
+// Directories that contain dependencies, build outputs or virtual environments, rather than sub-projects.
+var skippedDirs = []string{"node_modules", "vendor", "target", "build", "dist", "venv", "__pycache__"}
+
+// The projects of these technologies include the projects of the same technology in their sub-directories as modules.
+// Therefore, these sub-directories are audited as part of their parent project.
+var multiModuleTechnologies = []coreutils.Technology{coreutils.Maven, coreutils.Gradle}
+
+// SubProject is a project found in a directory of a multi-module repository.
+type SubProject struct {
+	// The directory of the project, relative to the root directory
+	Dir          string
+	Technologies []coreutils.Technology
+}
+
+// DetectSubProjects recursively searches the root directory for projects, using the technologies indicators.
+// requestedTechnologies - If not empty, only projects of these technologies are returned.
+func DetectSubProjects(rootDir string, requestedTechnologies []string) (subProjects []SubProject, err error) {
+	// Maps each multi-module technology to the directories of the projects detected so far
+	multiModuleProjectsDirs := make(map[coreutils.Technology][]string)
+	err = filepath.WalkDir(rootDir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != rootDir && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(skippedDirs, entry.Name())) {
+			return filepath.SkipDir
+		}
+		detected, err := coreutils.DetectTechnologies(path, false, false)
+		if err != nil {
+			return err
+		}
+		relativeDir, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		var technologies []coreutils.Technology
+		for tech := range detected {
+			if len(requestedTechnologies) > 0 && !slices.Contains(requestedTechnologies, tech.ToString()) {
+				continue
+			}
+			if slices.Contains(multiModuleTechnologies, tech) {
+				if isModuleOfDetectedProject(relativeDir, multiModuleProjectsDirs[tech]) {
+					continue
+				}
+				multiModuleProjectsDirs[tech] = append(multiModuleProjectsDirs[tech], relativeDir)
+			}
+			technologies = append(technologies, tech)
+		}
+		if len(technologies) > 0 {
+			sort.Slice(technologies, func(i, j int) bool {
+				return technologies[i] < technologies[j]
+			})
+			subProjects = append(subProjects, SubProject{Dir: relativeDir, Technologies: technologies})
+		}
+		return nil
+	})
+	return subProjects, errorutils.CheckError(err)
+}
+
+func isModuleOfDetectedProject(dir string, projectsDirs []string) bool {
+	for _, projectDir := range projectsDirs {
+		if projectDir == "." || strings.HasPrefix(dir, projectDir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// The dependency trees of a single technology in a sub-project, waiting to be scanned by Xray
+type subProjectScanTask struct {
+	subProjectDir   string
+	technology      coreutils.Technology
+	dependencyTrees []*services.GraphNode
+	devDependencies []string
+}
+
+// Audit all the sub-projects found in the working directories.
+// The dependency trees are built one sub-project after the other, because building them requires changing the working directory.
+// The dependency trees are then scanned by Xray in parallel.
+func (auditCmd *GenericAuditCommand) auditSubProjects() (groups []xrutils.ScanResultsGroup, auditErrors []formats.SimpleJsonError, err error) {
+	rootDirs := auditCmd.workingDirs
+	if len(rootDirs) == 0 {
+		rootDirs = []string{"."}
+	}
+	projectDir, err := os.Getwd()
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	defer func() {
+		e := os.Chdir(projectDir)
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+
+	var tasks []subProjectScanTask
+	for _, rootDir := range rootDirs {
+		rootTasks, rootErrors, e := auditCmd.buildSubProjectsDependencyTrees(projectDir, rootDir)
+		if e != nil {
+			auditErrors = append(auditErrors, formats.SimpleJsonError{FilePath: rootDir, ErrorMessage: e.Error()})
+			continue
+		}
+		tasks = append(tasks, rootTasks...)
+		auditErrors = append(auditErrors, rootErrors...)
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	if auditCmd.progress != nil {
+		auditCmd.progress.SetHeadlineMsg(fmt.Sprintf("Scanning %d projects for vulnerabilities", len(tasks)))
+	}
+	groups = make([]xrutils.ScanResultsGroup, len(tasks))
+	scanErrors := make([]*formats.SimpleJsonError, len(tasks))
+	scanFunc := auditCmd.getScanFunc(nil)
+	runner := parallel.NewBounedRunner(auditCmd.getThreads(), false)
+	go func() {
+		defer runner.Done()
+		for i := range tasks {
+			taskIndex := i
+			_, _ = runner.AddTask(func(int) error {
+				task := tasks[taskIndex]
+				results, e := scanFunc(task.dependencyTrees, task.technology)
+				if e != nil {
+					scanErrors[taskIndex] = &formats.SimpleJsonError{FilePath: task.subProjectDir, ErrorMessage: e.Error()}
+				}
+				groups[taskIndex] = xrutils.ScanResultsGroup{
+					Directory:       task.subProjectDir,
+					Technology:      task.technology,
+					Results:         results,
+					IsMultipleRoots: len(task.dependencyTrees) > 1,
+					DevDependencies: task.devDependencies,
+				}
+				return e
+			})
+		}
+	}()
+	runner.Run()
+	for _, scanError := range scanErrors {
+		if scanError != nil {
+			auditErrors = append(auditErrors, *scanError)
+		}
+	}
+	return
+}
+
+// Detect the sub-projects in the root directory, and build the dependency trees of each of them.
+// Failures are returned as audit errors, so that the other sub-projects are still audited.
+func (auditCmd *GenericAuditCommand) buildSubProjectsDependencyTrees(projectDir, rootDir string) (tasks []subProjectScanTask, auditErrors []formats.SimpleJsonError, err error) {
+	absRootDir := rootDir
+	if !filepath.IsAbs(absRootDir) {
+		absRootDir = filepath.Join(projectDir, rootDir)
+	}
+	subProjects, err := DetectSubProjects(absRootDir, auditCmd.technologies)
+	if err != nil {
+		return
+	}
+	if len(subProjects) == 0 {
+		log.Info("No projects were found in " + absRootDir)
+		return
+	}
+	for _, subProject := range subProjects {
+		subProjectDir := filepath.Join(rootDir, subProject.Dir)
+		if err = os.Chdir(filepath.Join(absRootDir, subProject.Dir)); err != nil {
+			return nil, nil, errorutils.CheckError(err)
+		}
+		for _, tech := range subProject.Technologies {
+			if tech == coreutils.Dotnet {
+				continue
+			}
+			log.Info(fmt.Sprintf("Calculating %s dependencies in %s", tech.ToFormal(), subProjectDir))
+			if auditCmd.progress != nil {
+				auditCmd.progress.SetHeadlineMsg(fmt.Sprintf("Calculating %v dependencies in %s", tech.ToFormal(), subProjectDir))
+			}
+			dependencyTrees, e := buildDependencyTrees(tech, auditCmd.excludeTestDependencies, auditCmd.useWrapper, auditCmd.insecureTls,
+				auditCmd.args, auditCmd.requirementsFile, false)
+			if e != nil {
+				auditErrors = append(auditErrors, formats.SimpleJsonError{FilePath: subProjectDir, ErrorMessage: fmt.Sprintf("'%s' audit command failed:\n%s", tech, e.Error())})
+				continue
+			}
+			task := subProjectScanTask{subProjectDir: subProjectDir, technology: tech, dependencyTrees: dependencyTrees}
+			if auditCmd.policy != nil && auditCmd.policy.AllowDevDependencies {
+				if task.devDependencies, e = getDevDependencies(tech); e != nil {
+					auditErrors = append(auditErrors, formats.SimpleJsonError{FilePath: subProjectDir, ErrorMessage: fmt.Sprintf("'%s' audit command failed:\n%s", tech, e.Error())})
+					continue
+				}
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return
+}
+
+func (auditCmd *GenericAuditCommand) getThreads() int {
+	if auditCmd.threads > 0 {
+		return auditCmd.threads
+	}
+	return defaultAuditThreads
+}
+
+func auditErrorsToError(auditErrors []formats.SimpleJsonError) error {
+	if len(auditErrors) == 0 {
+		return nil
+	}
+	var errorsMessages []string
+	for _, auditError := range auditErrors {
+		errorsMessages = append(errorsMessages, fmt.Sprintf("%s: %s", auditError.FilePath, auditError.ErrorMessage))
+	}
+	return errors.New(strings.Join(errorsMessages, "\n"))
+}
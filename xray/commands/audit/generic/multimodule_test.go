@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectSubProjects(t *testing.T) {
+	rootDir := t.TempDir()
+	createFiles(t, rootDir,
+		"go.mod",
+		filepath.Join("services", "api", "package.json"),
+		filepath.Join("services", "api", "node_modules", "dep", "package.json"),
+		filepath.Join("services", "web", "package.json"),
+		filepath.Join("services", "web", "yarn.lock"),
+		filepath.Join("java", "pom.xml"),
+		filepath.Join("java", "module", "pom.xml"),
+		filepath.Join("tools", "go.mod"),
+		filepath.Join(".git", "go.mod"),
+	)
+
+	subProjects, err := DetectSubProjects(rootDir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []SubProject{
+		{Dir: ".", Technologies: []coreutils.Technology{coreutils.Go}},
+		// The Maven module is audited as part of its parent project
+		{Dir: "java", Technologies: []coreutils.Technology{coreutils.Maven}},
+		{Dir: filepath.Join("services", "api"), Technologies: []coreutils.Technology{coreutils.Npm}},
+		{Dir: filepath.Join("services", "web"), Technologies: []coreutils.Technology{coreutils.Yarn}},
+		{Dir: "tools", Technologies: []coreutils.Technology{coreutils.Go}},
+	}, subProjects)
+
+	// Only the requested technologies
+	subProjects, err = DetectSubProjects(rootDir, []string{"go"})
+	assert.NoError(t, err)
+	assert.Equal(t, []SubProject{
+		{Dir: ".", Technologies: []coreutils.Technology{coreutils.Go}},
+		{Dir: "tools", Technologies: []coreutils.Technology{coreutils.Go}},
+	}, subProjects)
+}
+
+func createFiles(t *testing.T, rootDir string, files ...string) {
+	for _, file := range files {
+		path := filepath.Join(rootDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte{}, 0644))
+	}
+}
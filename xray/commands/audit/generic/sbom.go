@@ -0,0 +1,182 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit"
+	xrutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	ioUtils "github.com/jfrog/jfrog-client-go/utils/io"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// SbomCommand generates an SBOM from the same dependency trees that are scanned by the audit command.
+type SbomCommand struct {
+	serverDetails *config.ServerDetails
+	format        xrutils.SbomFormat
+	// If empty, the SBOM is printed to the standard output
+	outputFile string
+	// Scan the dependency trees with Xray and attach the vulnerabilities found to the SBOM
+	includeVulnerabilities  bool
+	projectKey              string
+	excludeTestDependencies bool
+	useWrapper              bool
+	insecureTls             bool
+	args                    []string
+	technologies            []string
+	requirementsFile        string
+	progress                ioUtils.ProgressMgr
+}
+
+func NewSbomCommand() *SbomCommand {
+	return &SbomCommand{format: xrutils.CycloneDxJson}
+}
+
+func (sc *SbomCommand) SetServerDetails(server *config.ServerDetails) *SbomCommand {
+	sc.serverDetails = server
+	return sc
+}
+
+func (sc *SbomCommand) ServerDetails() (*config.ServerDetails, error) {
+	return sc.serverDetails, nil
+}
+
+func (sc *SbomCommand) SetFormat(format xrutils.SbomFormat) *SbomCommand {
+	sc.format = format
+	return sc
+}
+
+func (sc *SbomCommand) SetOutputFile(outputFile string) *SbomCommand {
+	sc.outputFile = outputFile
+	return sc
+}
+
+func (sc *SbomCommand) SetIncludeVulnerabilities(include bool) *SbomCommand {
+	sc.includeVulnerabilities = include
+	return sc
+}
+
+func (sc *SbomCommand) SetProject(project string) *SbomCommand {
+	sc.projectKey = project
+	return sc
+}
+
+func (sc *SbomCommand) SetExcludeTestDependencies(excludeTestDependencies bool) *SbomCommand {
+	sc.excludeTestDependencies = excludeTestDependencies
+	return sc
+}
+
+func (sc *SbomCommand) SetUseWrapper(useWrapper bool) *SbomCommand {
+	sc.useWrapper = useWrapper
+	return sc
+}
+
+func (sc *SbomCommand) SetInsecureTls(insecureTls bool) *SbomCommand {
+	sc.insecureTls = insecureTls
+	return sc
+}
+
+func (sc *SbomCommand) SetNpmScope(depType string) *SbomCommand {
+	switch depType {
+	case "devOnly":
+		sc.args = []string{"--dev"}
+	case "prodOnly":
+		sc.args = []string{"--prod"}
+	}
+	return sc
+}
+
+func (sc *SbomCommand) SetTechnologies(technologies []string) *SbomCommand {
+	sc.technologies = technologies
+	return sc
+}
+
+func (sc *SbomCommand) SetRequirementsFile(requirementsFile string) *SbomCommand {
+	sc.requirementsFile = requirementsFile
+	return sc
+}
+
+func (sc *SbomCommand) SetProgress(progress ioUtils.ProgressMgr) {
+	sc.progress = progress
+}
+
+func (sc *SbomCommand) Run() (err error) {
+	technologies := sc.technologies
+	if len(technologies) == 0 {
+		if technologies, err = detectedTechnologies(); err != nil {
+			return
+		}
+	}
+	var dependencyTrees []*services.GraphNode
+	var vulnerabilities []services.Vulnerability
+	for _, tech := range coreutils.ToTechnologies(technologies) {
+		if tech == coreutils.Dotnet {
+			continue
+		}
+		if sc.progress != nil {
+			sc.progress.SetHeadlineMsg(fmt.Sprintf("Calculating %v dependencies", tech.ToFormal()))
+		}
+		techDependencyTrees, e := buildDependencyTrees(tech, sc.excludeTestDependencies, sc.useWrapper, sc.insecureTls, sc.args, sc.requirementsFile, false)
+		if e != nil {
+			return errorutils.CheckErrorf("failed calculating the %s dependencies:\n%s", tech.ToFormal(), e.Error())
+		}
+		dependencyTrees = append(dependencyTrees, techDependencyTrees...)
+		if sc.includeVulnerabilities {
+			techVulnerabilities, e := sc.scanDependencyTrees(techDependencyTrees, tech)
+			if e != nil {
+				return e
+			}
+			vulnerabilities = append(vulnerabilities, techVulnerabilities...)
+		}
+	}
+	if sc.progress != nil {
+		if err = sc.progress.Quit(); err != nil {
+			return
+		}
+	}
+	wd, err := os.Getwd()
+	if errorutils.CheckError(err) != nil {
+		return
+	}
+	sbom, err := xrutils.GenerateSbom(xrutils.SbomParams{Name: filepath.Base(wd), DependencyTrees: dependencyTrees, Vulnerabilities: vulnerabilities}, sc.format)
+	if err != nil {
+		return
+	}
+	if sc.outputFile == "" {
+		log.Output(sbom)
+		return
+	}
+	if err = os.WriteFile(sc.outputFile, []byte(sbom), 0644); errorutils.CheckError(err) != nil {
+		return
+	}
+	log.Info("The SBOM was written to " + sc.outputFile)
+	return
+}
+
+func (sc *SbomCommand) scanDependencyTrees(dependencyTrees []*services.GraphNode, tech coreutils.Technology) (vulnerabilities []services.Vulnerability, err error) {
+	params := services.XrayGraphScanParams{
+		ScanType:               services.Dependency,
+		ProjectKey:             sc.projectKey,
+		IncludeVulnerabilities: true,
+	}
+	if params.ProjectKey == "" {
+		params.ProjectKey = os.Getenv(coreutils.Project)
+	}
+	results, err := audit.Audit(dependencyTrees, params, sc.serverDetails, sc.progress, tech)
+	if err != nil {
+		return
+	}
+	for _, result := range results {
+		vulnerabilities = append(vulnerabilities, result.Vulnerabilities...)
+	}
+	return
+}
+
+func (sc *SbomCommand) CommandName() string {
+	return "generic_sbom"
+}
@@ -50,6 +50,12 @@ type ScanCommand struct {
 	printExtendedTable     bool
 	bypassArchiveLimits    bool
 	progress               ioUtils.ProgressMgr
+	// A local policy file, which determines whether the results fail the build
+	policyFile string
+	policy     *xrutils.Policy
+	// The path to the data downloaded by the offline-update command. If set, the files are scanned locally instead of by Xray.
+	offlineDbPath string
+	offlineDb     *xrutils.OfflineDb
 }
 
 func (scanCmd *ScanCommand) SetProgress(progress ioUtils.ProgressMgr) {
@@ -110,6 +116,16 @@ func (scanCmd *ScanCommand) SetPrintExtendedTable(printExtendedTable bool) *Scan
 	return scanCmd
 }
 
+func (scanCmd *ScanCommand) SetPolicyFile(policyFile string) *ScanCommand {
+	scanCmd.policyFile = policyFile
+	return scanCmd
+}
+
+func (scanCmd *ScanCommand) SetOfflineDbPath(offlineDbPath string) *ScanCommand {
+	scanCmd.offlineDbPath = offlineDbPath
+	return scanCmd
+}
+
 func (scanCmd *ScanCommand) SetBypassArchiveLimits(bypassArchiveLimits bool) *ScanCommand {
 	scanCmd.bypassArchiveLimits = bypassArchiveLimits
 	return scanCmd
@@ -152,27 +168,15 @@ func (scanCmd *ScanCommand) Run() (err error) {
 			}
 		}
 	}()
-	xrayManager, xrayVersion, err := commands.CreateXrayServiceManagerAndGetVersion(scanCmd.serverDetails)
-	if err != nil {
-		return err
-	}
-
-	// Validate Xray minimum version for graph scan command
-	err = commands.ValidateXrayMinimumVersion(xrayVersion, commands.GraphScanMinXrayVersion)
-	if err != nil {
-		return err
-	}
-
-	if scanCmd.bypassArchiveLimits {
-		// Validate Xray minimum version for BypassArchiveLimits flag for indexer
-		err = commands.ValidateXrayMinimumVersion(xrayVersion, commands.BypassArchiveLimitsMinXrayVersion)
-		if err != nil {
+	if scanCmd.policyFile != "" {
+		if scanCmd.policy, err = xrutils.LoadPolicy(scanCmd.policyFile); err != nil {
 			return err
 		}
+		if scanCmd.policy.AllowDevDependencies {
+			log.Warn("The scanned files have no dev dependencies, so the allowDevDependencies option of the policy is ignored.")
+		}
 	}
-	log.Info("JFrog Xray version is:", xrayVersion)
-	// First download Xray Indexer if needed
-	scanCmd.indexerPath, err = xrutils.DownloadIndexerIfNeeded(xrayManager, xrayVersion)
+	xrayVersion, err := scanCmd.prepareIndexer()
 	if err != nil {
 		return err
 	}
@@ -243,6 +247,11 @@ func (scanCmd *ScanCommand) Run() (err error) {
 			return xrutils.NewFailBuildError()
 		}
 	}
+	if scanCmd.policy != nil {
+		if err = xrutils.CheckPolicy(scanCmd.policy, flatResults, nil, scanCmd.includeVulnerabilities, true); err != nil {
+			return err
+		}
+	}
 	if len(scanErrors) > 0 {
 		return errorutils.CheckErrorf(scanErrors[0].ErrorMessage)
 	}
@@ -250,6 +259,49 @@ func (scanCmd *ScanCommand) Run() (err error) {
 	return nil
 }
 
+// Validates the Xray version and downloads the Xray indexer if needed.
+// When scanning with an offline vulnerabilities database, the locally cached indexer is used instead.
+func (scanCmd *ScanCommand) prepareIndexer() (xrayVersion string, err error) {
+	if scanCmd.offlineDbPath != "" {
+		if len(scanCmd.watches) > 0 || scanCmd.projectKey != "" {
+			return "", errorutils.CheckErrorf("the watches and project options require Xray, and can't be used with an offline vulnerabilities database")
+		}
+		if scanCmd.offlineDb, err = xrutils.LoadOfflineDb(scanCmd.offlineDbPath); err != nil {
+			return
+		}
+		// Without Xray policies, only vulnerabilities can be found
+		scanCmd.includeVulnerabilities = true
+		scanCmd.indexerPath, err = xrutils.GetCachedIndexerPath()
+		return
+	}
+	xrayManager, xrayVersion, err := commands.CreateXrayServiceManagerAndGetVersion(scanCmd.serverDetails)
+	if err != nil {
+		return "", err
+	}
+
+	// Validate Xray minimum version for graph scan command
+	err = commands.ValidateXrayMinimumVersion(xrayVersion, commands.GraphScanMinXrayVersion)
+	if err != nil {
+		return "", err
+	}
+
+	if scanCmd.bypassArchiveLimits {
+		// Validate Xray minimum version for BypassArchiveLimits flag for indexer
+		err = commands.ValidateXrayMinimumVersion(xrayVersion, commands.BypassArchiveLimitsMinXrayVersion)
+		if err != nil {
+			return "", err
+		}
+	}
+	log.Info("JFrog Xray version is:", xrayVersion)
+	// First download Xray Indexer if needed
+	scanCmd.indexerPath, err = xrutils.DownloadIndexerIfNeeded(xrayManager, xrayVersion)
+	return
+}
+
+func (scanCmd *ScanCommand) policyRequiresLicenses() bool {
+	return scanCmd.policy != nil && scanCmd.policy.HasLicenseRules()
+}
+
 func NewScanCommand() *ScanCommand {
 	return &ScanCommand{}
 }
@@ -309,7 +361,12 @@ func (scanCmd *ScanCommand) createIndexerHandlerFunc(file *spec.File, indexedFil
 				if scanCmd.progress != nil {
 					scanCmd.progress.SetHeadlineMsg("Scanning 🔍")
 				}
-				scanResults, err := commands.RunScanGraphAndGetResults(scanCmd.serverDetails, params, scanCmd.includeVulnerabilities, scanCmd.includeLicenses, xrayVersion)
+				var scanResults *services.ScanResponse
+				if scanCmd.offlineDb != nil {
+					scanResults = scanCmd.offlineDb.ScanGraph(graph)
+				} else {
+					scanResults, err = commands.RunScanGraphAndGetResults(scanCmd.serverDetails, params, scanCmd.includeVulnerabilities, scanCmd.includeLicenses || scanCmd.policyRequiresLicenses(), xrayVersion)
+				}
 				if err != nil {
 					log.Error(fmt.Sprintf("Scanning %s failed with error: %s", graph.Id, err.Error()))
 					indexedFileErrors[threadId] = append(indexedFileErrors[threadId], formats.SimpleJsonError{FilePath: filePath, ErrorMessage: err.Error()})
@@ -15,6 +15,19 @@ type SimpleJsonResults struct {
 	Errors                    []SimpleJsonError             `json:"errors"`
 }
 
+// This struct holds the results of the simple-json output of a multi-module audit, grouped by directory and technology.
+type SimpleJsonGroupedResults struct {
+	Projects []SimpleJsonProjectResults `json:"projects"`
+	Errors   []SimpleJsonError          `json:"errors"`
+}
+
+type SimpleJsonProjectResults struct {
+	// The directory of the project, relative to the audited root directory
+	Directory  string            `json:"directory"`
+	Technology string            `json:"technology"`
+	Results    SimpleJsonResults `json:"results"`
+}
+
 // Used for vulnerabilities and security violations
 type VulnerabilityOrViolationRow struct {
 	Summary                  string                    `json:"summary"`
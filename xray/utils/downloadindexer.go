@@ -66,6 +66,40 @@ func DownloadIndexerIfNeeded(xrayManager *xray.XrayServicesManager, xrayVersionS
 	return
 }
 
+// GetCachedIndexerPath returns the path to the newest Xray indexer cached locally, for scanning without access to Xray.
+func GetCachedIndexerPath() (string, error) {
+	dependenciesPath, err := config.GetJfrogDependenciesPath()
+	if err != nil {
+		return "", err
+	}
+	indexerDirPath := filepath.Join(dependenciesPath, indexerDirName)
+	filesList, err := os.ReadDir(indexerDirPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", errorutils.CheckError(err)
+	}
+	var dirsList []string
+	for _, file := range filesList {
+		if file.IsDir() && file.Name() != tempIndexerDirName {
+			dirsList = append(dirsList, file.Name())
+		}
+	}
+	sort.Slice(dirsList, func(i, j int) bool {
+		currVersion := version.NewVersion(dirsList[i])
+		return currVersion.AtLeast(dirsList[j])
+	})
+	for _, dir := range dirsList {
+		indexerPath := filepath.Join(indexerDirPath, dir, getIndexerBinaryName())
+		exists, err := fileutils.IsFileExists(indexerPath, false)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return indexerPath, nil
+		}
+	}
+	return "", errorutils.CheckErrorf("the JFrog Xray Indexer isn't cached locally in %s. Run a scan with access to Xray to download it, or copy it from another machine", indexerDirPath)
+}
+
 func downloadIndexer(xrayManager *xray.XrayServicesManager, indexerDirPath, indexerBinaryName string) (string, error) {
 	tempDirPath := filepath.Join(indexerDirPath, tempIndexerDirName)
 
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	clientUtils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/owenrumney/go-sarif/v2/sarif"
+)
+
+// ScanResultsGroup holds the scan results of a single project, in a scan of multiple projects.
+type ScanResultsGroup struct {
+	// The directory of the project, relative to the scanned root directory
+	Directory       string                  `json:"directory"`
+	Technology      coreutils.Technology    `json:"technology"`
+	Results         []services.ScanResponse `json:"results"`
+	IsMultipleRoots bool                    `json:"-"`
+	// The names of the direct dev and test dependencies of the project, used by the policy evaluation
+	DevDependencies []string `json:"-"`
+}
+
+// PrintGroupedScanResults prints the Xray scan results of multiple projects in the given format, grouped by directory and technology.
+// Note that errors are printed only on Table and SimpleJson formats.
+func PrintGroupedScanResults(groups []ScanResultsGroup, errors []formats.SimpleJsonError, format OutputFormat, includeVulnerabilities, includeLicenses, printExtended bool) error {
+	switch format {
+	case Table:
+		return printGroupedScanResultsTables(groups, errors, includeVulnerabilities, includeLicenses, printExtended)
+	case SimpleJson:
+		jsonTable, err := convertGroupedScanToSimpleJson(groups, errors, includeVulnerabilities, includeLicenses)
+		if err != nil {
+			return err
+		}
+		return printJson(jsonTable)
+	case Json:
+		return printJson(groups)
+	case Sarif:
+		sarifFile, err := GenerateSarifFileFromGroupedScan(groups, includeVulnerabilities)
+		if err != nil {
+			return err
+		}
+		log.Output(sarifFile)
+	}
+	return nil
+}
+
+func printGroupedScanResultsTables(groups []ScanResultsGroup, errors []formats.SimpleJsonError, includeVulnerabilities, includeLicenses, printExtended bool) error {
+	var allResults []services.ScanResponse
+	for _, group := range groups {
+		allResults = append(allResults, group.Results...)
+	}
+	if len(allResults) > 0 {
+		resultsPath, err := writeJsonResults(allResults)
+		if err != nil {
+			return err
+		}
+		log.Output("The full scan results are available here: " + resultsPath)
+	}
+	for _, group := range groups {
+		log.Output(coreutils.PrintTitle(coreutils.PrintBold(fmt.Sprintf("📁 %s (%s)", group.Directory, group.Technology.ToFormal()))))
+		violations, vulnerabilities, licenses := splitScanResults(group.Results)
+		var err error
+		if includeVulnerabilities {
+			err = PrintVulnerabilitiesTable(vulnerabilities, group.IsMultipleRoots, printExtended)
+		} else {
+			err = PrintViolationsTable(violations, group.IsMultipleRoots, printExtended)
+		}
+		if err != nil {
+			return err
+		}
+		if includeLicenses {
+			if err = PrintLicensesTable(licenses, group.IsMultipleRoots, printExtended); err != nil {
+				return err
+			}
+		}
+	}
+	for _, scanError := range errors {
+		log.Output(fmt.Sprintf("❌ %s: %s", scanError.FilePath, scanError.ErrorMessage))
+	}
+	return nil
+}
+
+func convertGroupedScanToSimpleJson(groups []ScanResultsGroup, errors []formats.SimpleJsonError, includeVulnerabilities, includeLicenses bool) (formats.SimpleJsonGroupedResults, error) {
+	groupedResults := formats.SimpleJsonGroupedResults{Projects: []formats.SimpleJsonProjectResults{}, Errors: errors}
+	for _, group := range groups {
+		jsonTable, err := convertScanToSimpleJson(group.Results, nil, includeVulnerabilities, group.IsMultipleRoots, includeLicenses)
+		if err != nil {
+			return formats.SimpleJsonGroupedResults{}, err
+		}
+		groupedResults.Projects = append(groupedResults.Projects, formats.SimpleJsonProjectResults{
+			Directory:  group.Directory,
+			Technology: group.Technology.ToString(),
+			Results:    jsonTable,
+		})
+	}
+	return groupedResults, nil
+}
+
+// GenerateSarifFileFromGroupedScan generates a single SARIF run with the results of all the projects.
+// The location of each result is the package descriptor of the project it belongs to.
+func GenerateSarifFileFromGroupedScan(groups []ScanResultsGroup, includeVulnerabilities bool) (string, error) {
+	report, err := sarif.New(sarif.Version210)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	run := sarif.NewRunWithInformationURI("JFrog Xray", "https://jfrog.com/xray/")
+	for _, group := range groups {
+		if err = convertScanToSarif(run, group.Results, includeVulnerabilities, group.IsMultipleRoots, group.Directory); err != nil {
+			return "", err
+		}
+	}
+	report.AddRun(run)
+	out, err := json.Marshal(report)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return clientUtils.IndentJson(out), nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSampleGroups() []ScanResultsGroup {
+	createResults := func(issueId, technology string) []services.ScanResponse {
+		return []services.ScanResponse{{Vulnerabilities: []services.Vulnerability{{
+			IssueId:    issueId,
+			Summary:    "summary",
+			Severity:   "High",
+			Components: map[string]services.Component{"component-A": {}},
+			Technology: technology,
+		}}}}
+	}
+	return []ScanResultsGroup{
+		{Directory: ".", Technology: "go", Results: createResults("XRAY-1", "go")},
+		{Directory: filepath.Join("services", "api"), Technology: "npm", Results: createResults("XRAY-2", "npm")},
+	}
+}
+
+func TestConvertGroupedScanToSimpleJson(t *testing.T) {
+	scanErrors := []formats.SimpleJsonError{{FilePath: "java", ErrorMessage: "failed"}}
+	groupedResults, err := convertGroupedScanToSimpleJson(getSampleGroups(), scanErrors, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, scanErrors, groupedResults.Errors)
+	if assert.Len(t, groupedResults.Projects, 2) {
+		assert.Equal(t, ".", groupedResults.Projects[0].Directory)
+		assert.Equal(t, "go", groupedResults.Projects[0].Technology)
+		assert.Len(t, groupedResults.Projects[0].Results.Vulnerabilities, 1)
+		assert.Equal(t, "XRAY-1", groupedResults.Projects[0].Results.Vulnerabilities[0].IssueId)
+		assert.Equal(t, filepath.Join("services", "api"), groupedResults.Projects[1].Directory)
+		assert.Equal(t, "XRAY-2", groupedResults.Projects[1].Results.Vulnerabilities[0].IssueId)
+	}
+}
+
+func TestGenerateSarifFileFromGroupedScan(t *testing.T) {
+	sarif, err := GenerateSarifFileFromGroupedScan(getSampleGroups(), true)
+	assert.NoError(t, err)
+	assert.Contains(t, sarif, `"uri": "go.mod"`)
+	assert.Contains(t, sarif, `"uri": "services/api/package.json"`)
+	assert.Contains(t, sarif, `"id": "XRAY-1"`)
+	assert.Contains(t, sarif, `"id": "XRAY-2"`)
+}
@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const OfflineScanId = "offline"
+
+// OfflineDb is a local vulnerabilities database, read from the data downloaded by the offline-update command.
+// It allows scanning dependency graphs without access to Xray.
+type OfflineDb struct {
+	// Maps package IDs (the component IDs without versions, such as "npm://lodash") to the vulnerabilities affecting them
+	vulnerabilitiesByPackage map[string][]*OfflineVulnerability
+}
+
+// The offline-update command saves the vulnerabilities in a vuln_<timestamp>.zip archive, and the components in a comp_<timestamp>.zip archive.
+// The archives contain the files downloaded from Xray, named <directory>__<file>, such as "2018-05__onboarding__vulnR1_1__.zip".
+// The downloaded files are JSON files, or zip archives of JSON files.
+const (
+	offlineVulnerabilitiesArchivePrefix = "vuln_"
+	offlineComponentsArchivePrefix      = "comp_"
+	offlineVulnerabilitiesFileMarker    = "__vuln"
+	offlineComponentsFileMarker         = "__comp"
+)
+
+// OfflineDbFile is the content of a vulnerabilities JSON file downloaded from Xray.
+type OfflineDbFile struct {
+	Vulnerabilities []*OfflineVulnerability `json:"vulnerabilities"`
+}
+
+type OfflineVulnerability struct {
+	Id          string                          `json:"id"`
+	Summary     string                          `json:"summary"`
+	Description string                          `json:"description,omitempty"`
+	Severity    string                          `json:"severity"`
+	Sources     []OfflineVulnerabilitySource    `json:"sources,omitempty"`
+	References  []string                        `json:"references,omitempty"`
+	Components  []OfflineVulnerabilityComponent `json:"components"`
+}
+
+// OfflineVulnerabilitySource is a public identifier of the vulnerability, such as a CVE.
+type OfflineVulnerabilitySource struct {
+	SourceId string `json:"source_id"`
+	Name     string `json:"name,omitempty"`
+	Url      string `json:"url,omitempty"`
+}
+
+type OfflineVulnerabilityComponent struct {
+	// The component ID without a version, such as "npm://lodash" or "gav://org.apache.logging.log4j:log4j-core"
+	Id string `json:"component_id"`
+	// Version ranges in the Xray format, such as "(,4.17.21)" or "[2.0.0,2.15.0)"
+	VulnerableVersions []string `json:"vulnerable_versions"`
+	FixedVersions      []string `json:"fixed_versions,omitempty"`
+}
+
+// LoadOfflineDb loads the vulnerabilities from the data downloaded by the offline-update command.
+// The path may be the target directory of the command, or one of the vulnerabilities archives it created.
+// Components archives, and other files in the directory, are skipped.
+func LoadOfflineDb(path string) (*OfflineDb, error) {
+	db := &OfflineDb{vulnerabilitiesByPackage: make(map[string][]*OfflineVulnerability)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("couldn't read the offline vulnerabilities database: %s", err.Error())
+	}
+	if !info.IsDir() {
+		err = db.loadFile(path)
+	} else {
+		err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			return db.loadFile(filePath)
+		})
+	}
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if len(db.vulnerabilitiesByPackage) == 0 {
+		return nil, errorutils.CheckErrorf("no vulnerabilities were found in the offline vulnerabilities database at %s. "+
+			"The database is expected to be the vulnerabilities data downloaded by the offline-update command, without the DBSync V3 option", path)
+	}
+	log.Debug("Loaded the offline vulnerabilities database of", len(db.vulnerabilitiesByPackage), "packages from", path)
+	return db, nil
+}
+
+func (db *OfflineDb) loadFile(filePath string) error {
+	fileName := filepath.Base(filePath)
+	if !strings.HasPrefix(fileName, offlineVulnerabilitiesArchivePrefix) && !strings.Contains(fileName, offlineVulnerabilitiesFileMarker) {
+		log.Debug("Skipping " + filePath + ", which isn't vulnerabilities data")
+		return nil
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return db.loadData(filePath, content)
+}
+
+// Loads a vulnerabilities JSON file, or a zip archive of vulnerabilities files.
+func (db *OfflineDb) loadData(filePath string, content []byte) error {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".zip":
+		return db.loadZip(filePath, content)
+	case ".json":
+		return db.loadJson(filePath, content)
+	}
+	log.Debug("Skipping " + filePath + ", which is neither a JSON file nor a zip archive")
+	return nil
+}
+
+func (db *OfflineDb) loadZip(zipPath string, content []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("failed to read the zip archive %s: %s", zipPath, err.Error())
+	}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if strings.Contains(path.Base(file.Name), offlineComponentsFileMarker) || strings.HasPrefix(path.Base(file.Name), offlineComponentsArchivePrefix) {
+			log.Debug("Skipping " + zipPath + "/" + file.Name + ", which isn't vulnerabilities data")
+			continue
+		}
+		fileContent, err := readZipFile(file)
+		if err != nil {
+			return err
+		}
+		if err = db.loadData(zipPath+"/"+file.Name, fileContent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readZipFile(file *zip.File) (content []byte, err error) {
+	fileReader, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := fileReader.Close(); err == nil {
+			err = e
+		}
+	}()
+	return io.ReadAll(fileReader)
+}
+
+func (db *OfflineDb) loadJson(filePath string, content []byte) error {
+	var dbFile OfflineDbFile
+	if err := json.Unmarshal(content, &dbFile); err != nil {
+		return fmt.Errorf("failed to parse the vulnerabilities file %s: %s", filePath, err.Error())
+	}
+	for i, vulnerability := range dbFile.Vulnerabilities {
+		if vulnerability == nil || vulnerability.Id == "" {
+			return fmt.Errorf("the vulnerability #%d in the vulnerabilities file %s has no ID", i+1, filePath)
+		}
+		for _, component := range vulnerability.Components {
+			if component.Id == "" {
+				return fmt.Errorf("the vulnerability %s in the vulnerabilities file %s has a component without an ID", vulnerability.Id, filePath)
+			}
+			db.vulnerabilitiesByPackage[component.Id] = append(db.vulnerabilitiesByPackage[component.Id], vulnerability)
+		}
+	}
+	return nil
+}
+
+// ScanGraph matches the components of the dependency graph against the database.
+// The results have the same structure as the results of a graph scan with vulnerabilities included.
+func (db *OfflineDb) ScanGraph(graph *services.GraphNode) *services.ScanResponse {
+	results := &services.ScanResponse{ScanId: OfflineScanId, Vulnerabilities: []services.Vulnerability{}}
+	// Maps the vulnerabilities found to their indexes in the results
+	vulnerabilitiesIndexes := make(map[*OfflineVulnerability]int)
+	db.scanNode(graph, nil, results, vulnerabilitiesIndexes)
+	return results
+}
+
+func (db *OfflineDb) scanNode(node *services.GraphNode, parentPath []services.ImpactPathNode, results *services.ScanResponse, vulnerabilitiesIndexes map[*OfflineVulnerability]int) {
+	impactPath := append(append([]services.ImpactPathNode{}, parentPath...), services.ImpactPathNode{ComponentId: node.Id})
+	packageId, componentVersion := splitOfflineComponentId(node.Id)
+	for _, vulnerability := range db.vulnerabilitiesByPackage[packageId] {
+		for _, component := range vulnerability.Components {
+			if component.Id != packageId || !isVersionInRanges(componentVersion, component.VulnerableVersions) {
+				continue
+			}
+			index, exists := vulnerabilitiesIndexes[vulnerability]
+			if !exists {
+				index = len(results.Vulnerabilities)
+				vulnerabilitiesIndexes[vulnerability] = index
+				results.Vulnerabilities = append(results.Vulnerabilities, services.Vulnerability{
+					IssueId:    vulnerability.Id,
+					Summary:    vulnerability.Summary,
+					Severity:   vulnerability.Severity,
+					Cves:       vulnerability.getCves(),
+					References: vulnerability.References,
+					Components: make(map[string]services.Component),
+				})
+			}
+			affectedComponent := results.Vulnerabilities[index].Components[node.Id]
+			affectedComponent.FixedVersions = component.FixedVersions
+			affectedComponent.ImpactPaths = append(affectedComponent.ImpactPaths, impactPath)
+			results.Vulnerabilities[index].Components[node.Id] = affectedComponent
+		}
+	}
+	for _, child := range node.Nodes {
+		db.scanNode(child, impactPath, results, vulnerabilitiesIndexes)
+	}
+}
+
+func (ov *OfflineVulnerability) getCves() (cves []services.Cve) {
+	for _, source := range ov.Sources {
+		if strings.HasPrefix(strings.ToUpper(source.SourceId), "CVE-") {
+			cves = append(cves, services.Cve{Id: source.SourceId})
+		}
+	}
+	return
+}
+
+// Splits a component ID, such as "npm://lodash:4.17.0", to the package ID "npm://lodash" and the version "4.17.0".
+func splitOfflineComponentId(componentId string) (packageId, componentVersion string) {
+	separatorIndex := strings.Index(componentId, "://")
+	lastColonIndex := strings.LastIndex(componentId, ":")
+	if separatorIndex == -1 || lastColonIndex <= separatorIndex {
+		return componentId, ""
+	}
+	return componentId[:lastColonIndex], componentId[lastColonIndex+1:]
+}
+
+func isVersionInRanges(componentVersion string, versionRanges []string) bool {
+	if componentVersion == "" {
+		return false
+	}
+	for _, versionRange := range versionRanges {
+		if isVersionInRange(componentVersion, versionRange) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks whether the version is in a range of the Xray format.
+// Examples: "[1.0.0]" - exactly 1.0.0, "(,1.0.0)" - below 1.0.0, "[1.0.0,2.0.0)" - 1.0.0 and above, and below 2.0.0.
+// Versions without brackets are exact versions.
+func isVersionInRange(componentVersion, versionRange string) bool {
+	versionRange = strings.TrimSpace(versionRange)
+	if len(versionRange) < 2 || !strings.ContainsAny(versionRange[:1], "[(") || !strings.ContainsAny(versionRange[len(versionRange)-1:], "])") {
+		return compareVersions(componentVersion, versionRange) == 0
+	}
+	includeLower, includeUpper := versionRange[0] == '[', versionRange[len(versionRange)-1] == ']'
+	bounds := strings.Split(versionRange[1:len(versionRange)-1], ",")
+	if len(bounds) == 1 {
+		return compareVersions(componentVersion, strings.TrimSpace(bounds[0])) == 0
+	}
+	lower, upper := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+	if lower != "" {
+		if compare := compareVersions(componentVersion, lower); compare < 0 || (compare == 0 && !includeLower) {
+			return false
+		}
+	}
+	if upper != "" {
+		if compare := compareVersions(componentVersion, upper); compare > 0 || (compare == 0 && !includeUpper) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns 1 if version1 is greater than version2, -1 if it is smaller, or 0 if they are equal.
+// A 'v' prefix, as in Go modules versions, is ignored.
+func compareVersions(version1, version2 string) int {
+	return version.NewVersion(strings.TrimPrefix(version2, "v")).Compare(strings.TrimPrefix(version1, "v"))
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/offlineupdate"
+	testsutils "github.com/jfrog/jfrog-client-go/utils/tests"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOfflineVulnerabilitiesFile = `{"vulnerabilities": [
+  {"id": "XRAY-1", "summary": "Prototype pollution", "severity": "High", "sources": [{"source_id": "CVE-2020-8203", "name": "NVD"}],
+   "components": [{"component_id": "npm://lodash", "vulnerable_versions": ["(,4.17.19)"], "fixed_versions": ["[4.17.19]"]}]}
+]}`
+	testOfflineNestedVulnerabilitiesFile = `{"vulnerabilities": [
+  {"id": "XRAY-2", "summary": "Remote code execution", "severity": "Critical",
+   "components": [{"component_id": "gav://org.apache.logging.log4j:log4j-core", "vulnerable_versions": ["[2.0.0,2.15.0)"], "fixed_versions": ["[2.15.0]"]}]}
+]}`
+	testOfflineComponentsFile = `{"components": [{"component_id": "npm://lodash", "versions": ["4.17.19"]}]}`
+)
+
+// Runs the offline-update command against a fake Xray updates server, and returns the target directory of the command.
+// Xray serves the vulnerabilities and the components as JSON files, or as zip archives of JSON files.
+func createTestOfflineDb(t *testing.T, vulnerabilitiesFile string) string {
+	files := map[string][]byte{
+		"/data/2018-05/onboarding__vuln9__.json":      []byte(vulnerabilitiesFile),
+		"/data/2018-06-07/onboarding__vulnR1_1__.zip": createTestZip(t, "vulnR1_1.json", testOfflineNestedVulnerabilitiesFile),
+		"/data/2018-05/onboarding__comp1__.zip":       createTestZip(t, "comp1.json", testOfflineComponentsFile),
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+offlineupdate.JxrayApiOnboarding {
+			filesList := offlineupdate.FilesList{LastUpdate: 1672531200}
+			for filePath := range files {
+				filesList.Urls = append(filesList.Urls, server.URL+filePath+"?expiry=1")
+			}
+			content, err := json.Marshal(filesList)
+			assert.NoError(t, err)
+			_, err = w.Write(content)
+			assert.NoError(t, err)
+			return
+		}
+		content, exists := files[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(testsutils.SetEnvWithCallbackAndAssert(t, "JFROG_CLI_JXRAY_BASE_URL", server.URL))
+
+	targetDir := t.TempDir()
+	assert.NoError(t, offlineupdate.OfflineUpdate(&offlineupdate.OfflineUpdatesFlags{License: "license", Target: targetDir}))
+	return targetDir
+}
+
+func createTestZip(t *testing.T, fileName, content string) []byte {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+	fileWriter, err := zipWriter.Create(fileName)
+	assert.NoError(t, err)
+	_, err = fileWriter.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, zipWriter.Close())
+	return buffer.Bytes()
+}
+
+func TestLoadOfflineDb(t *testing.T) {
+	dbDir := createTestOfflineDb(t, testOfflineVulnerabilitiesFile)
+	// Files of other formats in the target directory are skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(dbDir, "README"), []byte("readme"), 0644))
+	db, err := LoadOfflineDb(dbDir)
+	assert.NoError(t, err)
+	assert.Len(t, db.vulnerabilitiesByPackage, 2)
+	assert.Equal(t, "XRAY-1", db.vulnerabilitiesByPackage["npm://lodash"][0].Id)
+	// The nested archive is read as well
+	assert.Equal(t, "XRAY-2", db.vulnerabilitiesByPackage["gav://org.apache.logging.log4j:log4j-core"][0].Id)
+
+	// The vulnerabilities archive may be loaded by itself
+	db, err = LoadOfflineDb(filepath.Join(dbDir, "vuln_1672531200.zip"))
+	assert.NoError(t, err)
+	assert.Len(t, db.vulnerabilitiesByPackage, 2)
+
+	// The components archive has no vulnerabilities
+	_, err = LoadOfflineDb(filepath.Join(dbDir, "comp_1672531200.zip"))
+	assert.ErrorContains(t, err, "no vulnerabilities were found")
+	_, err = LoadOfflineDb(t.TempDir())
+	assert.ErrorContains(t, err, "no vulnerabilities were found")
+	_, err = LoadOfflineDb(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "couldn't read the offline vulnerabilities database")
+}
+
+func TestLoadOfflineDbInvalidData(t *testing.T) {
+	_, err := LoadOfflineDb(createTestOfflineDb(t, `{"vulnerabilities": {}}`))
+	assert.ErrorContains(t, err, "failed to parse the vulnerabilities file")
+	assert.ErrorContains(t, err, "2018-05__onboarding__vuln9__.json")
+
+	_, err = LoadOfflineDb(createTestOfflineDb(t, `{"vulnerabilities": [{"summary": "No ID"}]}`))
+	assert.ErrorContains(t, err, "has no ID")
+}
+
+func TestOfflineDbScanGraph(t *testing.T) {
+	db, err := LoadOfflineDb(createTestOfflineDb(t, testOfflineVulnerabilitiesFile))
+	assert.NoError(t, err)
+	graph := &services.GraphNode{Id: "npm://my-project:1.0.0", Nodes: []*services.GraphNode{
+		{Id: "npm://lodash:4.17.15"},
+		{Id: "npm://express:4.18.0", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.15"}}},
+		{Id: "npm://lodash-es:4.17.15"},
+	}}
+
+	results := db.ScanGraph(graph)
+	assert.Equal(t, OfflineScanId, results.ScanId)
+	if assert.Len(t, results.Vulnerabilities, 1) {
+		vulnerability := results.Vulnerabilities[0]
+		assert.Equal(t, "XRAY-1", vulnerability.IssueId)
+		assert.Equal(t, "High", vulnerability.Severity)
+		assert.Equal(t, "CVE-2020-8203", vulnerability.Cves[0].Id)
+		assert.Equal(t, map[string]services.Component{"npm://lodash:4.17.15": {
+			FixedVersions: []string{"[4.17.19]"},
+			ImpactPaths: [][]services.ImpactPathNode{
+				{{ComponentId: "npm://my-project:1.0.0"}, {ComponentId: "npm://lodash:4.17.15"}},
+				{{ComponentId: "npm://my-project:1.0.0"}, {ComponentId: "npm://express:4.18.0"}, {ComponentId: "npm://lodash:4.17.15"}},
+			},
+		}}, vulnerability.Components)
+	}
+
+	// Not vulnerable versions
+	results = db.ScanGraph(&services.GraphNode{Id: "gav://org.apache.logging.log4j:log4j-core:2.15.0"})
+	assert.Empty(t, results.Vulnerabilities)
+	results = db.ScanGraph(&services.GraphNode{Id: "gav://org.apache.logging.log4j:log4j-core:2.14.1"})
+	assert.Len(t, results.Vulnerabilities, 1)
+}
+
+func TestIsVersionInRange(t *testing.T) {
+	testCases := []struct {
+		version  string
+		ranges   string
+		expected bool
+	}{
+		{"1.0.0", "[1.0.0]", true},
+		{"1.0.1", "[1.0.0]", false},
+		{"1.0.0", "1.0.0", true},
+		{"0.9.0", "(,1.0.0)", true},
+		{"1.0.0", "(,1.0.0)", false},
+		{"1.0.0", "(,1.0.0]", true},
+		{"1.0.0", "[1.0.0,2.0.0)", true},
+		{"1.0.0", "(1.0.0,2.0.0)", false},
+		{"1.10.0", "[1.2.0,)", true},
+		{"2.0.0", "[1.0.0,2.0.0)", false},
+		{"v1.2.3", "[1.2.0,1.3.0)", true},
+	}
+	for _, test := range testCases {
+		t.Run(test.version+" "+test.ranges, func(t *testing.T) {
+			assert.Equal(t, test.expected, isVersionInRange(test.version, test.ranges))
+		})
+	}
+	assert.False(t, isVersionInRanges("", []string{"(,1.0.0)"}))
+}
+
+func TestSplitOfflineComponentId(t *testing.T) {
+	packageId, componentVersion := splitOfflineComponentId("gav://org.apache:log4j:2.14.1")
+	assert.Equal(t, "gav://org.apache:log4j", packageId)
+	assert.Equal(t, "2.14.1", componentVersion)
+	packageId, componentVersion = splitOfflineComponentId("go://github.com/jfrog/my-module")
+	assert.Equal(t, "go://github.com/jfrog/my-module", packageId)
+	assert.Equal(t, "", componentVersion)
+}
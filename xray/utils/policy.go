@@ -0,0 +1,402 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	policyVersion        = 1
+	policyDateLayout     = "2006-01-02"
+	securityRuleType     = "security"
+	licenseRuleType      = "license"
+	policyFailedErrorMsg = "The local policy failed the build:"
+)
+
+// Policy is a local policy that determines whether the results of an audit or a scan fail the build.
+// Unlike Xray watches, it is kept as a YAML file in the repository and evaluated by the CLI, so gating in CI is deterministic.
+//
+// Example:
+//
+//	version: 1
+//	allowDevDependencies: true
+//	rules:
+//	  - name: critical-with-fix
+//	    minSeverity: Critical
+//	    fixAvailable: true
+//	  - name: no-gpl
+//	    deniedLicenses: ["GPL-3.0*", "AGPL-*"]
+//	ignore:
+//	  - id: CVE-2022-1234
+//	    until: 2026-12-31
+//	    reason: Not exploitable, waiting for the vendor fix
+type Policy struct {
+	Version int `yaml:"version"`
+	// Issues which the project gets only through its direct dev and test dependencies never fail the build
+	AllowDevDependencies bool               `yaml:"allowDevDependencies,omitempty"`
+	Rules                []PolicyRule       `yaml:"rules,omitempty"`
+	Ignore               []PolicyIgnoreRule `yaml:"ignore,omitempty"`
+}
+
+// PolicyRule fails the build if a vulnerability or a license matches all of its criteria.
+// Rules with deniedLicenses are license rules, and all other rules are security rules.
+type PolicyRule struct {
+	Name string `yaml:"name"`
+	// Security rules criteria
+	MinSeverity  string   `yaml:"minSeverity,omitempty"`
+	FixAvailable bool     `yaml:"fixAvailable,omitempty"`
+	Cves         []string `yaml:"cves,omitempty"`
+	// License rules criteria, wildcards are supported
+	DeniedLicenses []string `yaml:"deniedLicenses,omitempty"`
+	// Limits the rule to these packages, wildcards are supported
+	Packages []string `yaml:"packages,omitempty"`
+}
+
+// PolicyIgnoreRule excludes an issue or a license from the policy evaluation, optionally until a given date.
+type PolicyIgnoreRule struct {
+	// A CVE or an Xray issue ID
+	Id       string   `yaml:"id,omitempty"`
+	License  string   `yaml:"license,omitempty"`
+	Packages []string `yaml:"packages,omitempty"`
+	// The last day of the ignore rule, in the YYYY-MM-DD format. If empty, the rule never expires.
+	Until  string `yaml:"until,omitempty"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// PolicyViolation is a vulnerability or a license that failed a rule of the policy.
+type PolicyViolation struct {
+	Rule           string
+	Type           string
+	IssueId        string
+	Cves           []string
+	Severity       string
+	License        string
+	PackageName    string
+	PackageVersion string
+	FixedVersions  []string
+}
+
+func (pv PolicyViolation) String() string {
+	pkg := pv.PackageName
+	if pv.PackageVersion != "" {
+		pkg += ":" + pv.PackageVersion
+	}
+	if pv.Type == licenseRuleType {
+		return fmt.Sprintf("Rule '%s': %s is licensed under the denied license %s", pv.Rule, pkg, pv.License)
+	}
+	issue := pv.IssueId
+	if len(pv.Cves) > 0 {
+		issue = strings.Join(pv.Cves, ", ")
+	}
+	msg := fmt.Sprintf("Rule '%s': %s (%s) in %s", pv.Rule, issue, pv.Severity, pkg)
+	if len(pv.FixedVersions) > 0 {
+		msg += fmt.Sprintf(", fixed versions: %s", strings.Join(pv.FixedVersions, ", "))
+	}
+	return msg
+}
+
+// LoadPolicy reads and validates a policy file.
+func LoadPolicy(policyFile string) (*Policy, error) {
+	content, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	policy := &Policy{}
+	if err = yaml.UnmarshalStrict(content, policy); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the policy file %s: %s", policyFile, err.Error())
+	}
+	if err = policy.validate(); err != nil {
+		return nil, errorutils.CheckErrorf("invalid policy file %s: %s", policyFile, err.Error())
+	}
+	return policy, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Version != policyVersion {
+		return fmt.Errorf("unsupported version %d, the supported version is %d", p.Version, policyVersion)
+	}
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule #%d has no name", i+1)
+		}
+		if rule.MinSeverity != "" && severities[rule.MinSeverity] == nil {
+			return fmt.Errorf("rule '%s' has an unknown severity '%s', the supported severities are: Low, Medium, High, Critical", rule.Name, rule.MinSeverity)
+		}
+		hasSecurityCriteria := rule.MinSeverity != "" || rule.FixAvailable || len(rule.Cves) > 0
+		if hasSecurityCriteria && len(rule.DeniedLicenses) > 0 {
+			return fmt.Errorf("rule '%s' mixes security and license criteria", rule.Name)
+		}
+		if !hasSecurityCriteria && len(rule.DeniedLicenses) == 0 && len(rule.Packages) == 0 {
+			return fmt.Errorf("rule '%s' has no criteria", rule.Name)
+		}
+	}
+	for i, ignoreRule := range p.Ignore {
+		if ignoreRule.Id == "" && ignoreRule.License == "" && len(ignoreRule.Packages) == 0 {
+			return fmt.Errorf("ignore rule #%d has no id, license or packages", i+1)
+		}
+		if ignoreRule.Until != "" {
+			if _, err := time.Parse(policyDateLayout, ignoreRule.Until); err != nil {
+				return fmt.Errorf("ignore rule #%d has an invalid date '%s', the expected format is YYYY-MM-DD", i+1, ignoreRule.Until)
+			}
+		}
+	}
+	return nil
+}
+
+// HasLicenseRules returns true if the licenses of the dependencies are needed for the evaluation of the policy.
+func (p *Policy) HasLicenseRules() bool {
+	for _, rule := range p.Rules {
+		if rule.isLicenseRule() {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate returns the vulnerabilities and licenses in the results, which fail the rules of the policy.
+// devDependencies - The names of the direct dev and test dependencies of the project, used if the policy allows dev dependencies.
+// now - Used to determine whether the ignore rules have expired.
+func (p *Policy) Evaluate(results formats.SimpleJsonResults, devDependencies []string, now time.Time) (violations []PolicyViolation) {
+	activeIgnoreRules := p.getActiveIgnoreRules(now)
+	reported := make(map[string]bool)
+	addViolation := func(violation PolicyViolation) {
+		key := strings.Join([]string{violation.Rule, violation.IssueId, violation.License, violation.PackageName, violation.PackageVersion}, "|")
+		if !reported[key] {
+			reported[key] = true
+			violations = append(violations, violation)
+		}
+	}
+	securityRows := append(append([]formats.VulnerabilityOrViolationRow{}, results.Vulnerabilities...), results.SecurityViolations...)
+	licenseRows := getPolicyLicenseRows(results)
+	for _, rule := range p.Rules {
+		if rule.isLicenseRule() {
+			for _, row := range licenseRows {
+				if rule.matchesLicense(row) && !isLicenseIgnored(activeIgnoreRules, row) && !p.isDevOnly(row.ImpactPaths, devDependencies) {
+					addViolation(PolicyViolation{Rule: rule.Name, Type: licenseRuleType, License: row.LicenseKey,
+						PackageName: row.ImpactedPackageName, PackageVersion: row.ImpactedPackageVersion})
+				}
+			}
+			continue
+		}
+		for _, row := range securityRows {
+			if rule.matchesVulnerability(row) && !isVulnerabilityIgnored(activeIgnoreRules, row) && !p.isDevOnly(row.ImpactPaths, devDependencies) {
+				addViolation(PolicyViolation{Rule: rule.Name, Type: securityRuleType, IssueId: row.IssueId, Cves: getCveIds(row),
+					Severity: row.Severity, PackageName: row.ImpactedPackageName, PackageVersion: row.ImpactedPackageVersion, FixedVersions: row.FixedVersions})
+			}
+		}
+	}
+	return
+}
+
+// Returns true if dev dependencies are allowed, and all the impact paths of the issue go through direct dev dependencies.
+// The first node of an impact path is the project or module itself, and the second one is the direct dependency.
+// Issues without impact paths, or in the project itself, are never considered dev only.
+func (p *Policy) isDevOnly(impactPaths [][]formats.ComponentRow, devDependencies []string) bool {
+	if !p.AllowDevDependencies || len(impactPaths) == 0 || len(devDependencies) == 0 {
+		return false
+	}
+	for _, impactPath := range impactPaths {
+		if len(impactPath) < 2 || !containsName(devDependencies, impactPath[1].Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsName(names []string, name string) bool {
+	for _, currName := range names {
+		if strings.EqualFold(currName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) getActiveIgnoreRules(now time.Time) (activeRules []PolicyIgnoreRule) {
+	for _, ignoreRule := range p.Ignore {
+		if ignoreRule.Until != "" {
+			// The ignore rule is valid until the end of the given day
+			until, _ := time.Parse(policyDateLayout, ignoreRule.Until)
+			if !now.Before(until.AddDate(0, 0, 1)) {
+				log.Warn(fmt.Sprintf("The ignore rule of '%s' in the policy expired on %s.", ignoreRule.describe(), ignoreRule.Until))
+				continue
+			}
+		}
+		activeRules = append(activeRules, ignoreRule)
+	}
+	return
+}
+
+func (pir PolicyIgnoreRule) describe() string {
+	if pir.Id != "" {
+		return pir.Id
+	}
+	if pir.License != "" {
+		return pir.License
+	}
+	return strings.Join(pir.Packages, ", ")
+}
+
+func (pr PolicyRule) isLicenseRule() bool {
+	return len(pr.DeniedLicenses) > 0
+}
+
+func (pr PolicyRule) matchesVulnerability(row formats.VulnerabilityOrViolationRow) bool {
+	if !matchesAnyPattern(row.ImpactedPackageName, pr.Packages) {
+		return false
+	}
+	if pr.MinSeverity != "" && getSeverity(row.Severity).numValue < getSeverity(pr.MinSeverity).numValue {
+		return false
+	}
+	if pr.FixAvailable && len(row.FixedVersions) == 0 {
+		return false
+	}
+	if len(pr.Cves) > 0 && !containsAnyId(row, pr.Cves) {
+		return false
+	}
+	return true
+}
+
+func (pr PolicyRule) matchesLicense(row formats.LicenseRow) bool {
+	return matchesAnyPattern(row.ImpactedPackageName, pr.Packages) && matchesAnyPattern(row.LicenseKey, pr.DeniedLicenses)
+}
+
+func isVulnerabilityIgnored(ignoreRules []PolicyIgnoreRule, row formats.VulnerabilityOrViolationRow) bool {
+	for _, ignoreRule := range ignoreRules {
+		if ignoreRule.License != "" || !matchesAnyPattern(row.ImpactedPackageName, ignoreRule.Packages) {
+			continue
+		}
+		if ignoreRule.Id == "" || containsAnyId(row, []string{ignoreRule.Id}) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLicenseIgnored(ignoreRules []PolicyIgnoreRule, row formats.LicenseRow) bool {
+	for _, ignoreRule := range ignoreRules {
+		if ignoreRule.Id != "" || !matchesAnyPattern(row.ImpactedPackageName, ignoreRule.Packages) {
+			continue
+		}
+		if ignoreRule.License == "" || matchesAnyPattern(row.LicenseKey, []string{ignoreRule.License}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the Xray issue ID or one of the CVEs of the row is in the given IDs.
+func containsAnyId(row formats.VulnerabilityOrViolationRow, ids []string) bool {
+	for _, id := range ids {
+		if strings.EqualFold(id, row.IssueId) {
+			return true
+		}
+		for _, cve := range row.Cves {
+			if strings.EqualFold(id, cve.Id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getCveIds(row formats.VulnerabilityOrViolationRow) (cveIds []string) {
+	for _, cve := range row.Cves {
+		if cve.Id != "" {
+			cveIds = append(cveIds, cve.Id)
+		}
+	}
+	return
+}
+
+func getPolicyLicenseRows(results formats.SimpleJsonResults) []formats.LicenseRow {
+	licenseRows := append([]formats.LicenseRow{}, results.Licenses...)
+	for _, violation := range results.LicensesViolations {
+		licenseRows = append(licenseRows, formats.LicenseRow{
+			LicenseKey:             violation.LicenseKey,
+			ImpactedPackageName:    violation.ImpactedPackageName,
+			ImpactedPackageVersion: violation.ImpactedPackageVersion,
+			ImpactedPackageType:    violation.ImpactedPackageType,
+			Components:             violation.Components,
+		})
+	}
+	return licenseRows
+}
+
+// Returns true if the value matches one of the patterns, case-insensitively. The patterns may include '*' wildcards.
+// An empty patterns list matches all values.
+func matchesAnyPattern(value string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		regex := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, _ := regexp.MatchString(regex, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPolicy evaluates the policy against the scan results.
+// devDependencies - The names of the direct dev and test dependencies of the scanned project.
+// If the policy fails the build, an error explaining which rules failed is returned.
+func CheckPolicy(policy *Policy, results []services.ScanResponse, devDependencies []string, includeVulnerabilities, isMultipleRoots bool) error {
+	violations, err := evaluatePolicy(policy, results, devDependencies, includeVulnerabilities, isMultipleRoots)
+	if err != nil {
+		return err
+	}
+	return newPolicyFailBuildError(violations)
+}
+
+// CheckPolicyForGroups evaluates the policy against the scan results of multiple projects.
+func CheckPolicyForGroups(policy *Policy, groups []ScanResultsGroup, includeVulnerabilities bool) error {
+	var violations []string
+	for _, group := range groups {
+		groupViolations, err := evaluatePolicy(policy, group.Results, group.DevDependencies, includeVulnerabilities, group.IsMultipleRoots)
+		if err != nil {
+			return err
+		}
+		for _, violation := range groupViolations {
+			violations = append(violations, fmt.Sprintf("%s: %s", group.Directory, violation))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return newPolicyFailBuildErrorFromMessages(violations)
+}
+
+func evaluatePolicy(policy *Policy, results []services.ScanResponse, devDependencies []string, includeVulnerabilities, isMultipleRoots bool) ([]PolicyViolation, error) {
+	// Licenses are always converted, since they are returned by Xray only when needed
+	simpleJsonResults, err := convertScanToSimpleJson(results, nil, includeVulnerabilities, isMultipleRoots, true)
+	if err != nil {
+		return nil, err
+	}
+	return policy.Evaluate(simpleJsonResults, devDependencies, time.Now()), nil
+}
+
+func newPolicyFailBuildError(violations []PolicyViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	return newPolicyFailBuildErrorFromMessages(messages)
+}
+
+func newPolicyFailBuildErrorFromMessages(messages []string) error {
+	return coreutils.CliError{ExitCode: coreutils.ExitCodeVulnerableBuild, ErrorMsg: policyFailedErrorMsg + "\n" + strings.Join(messages, "\n")}
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `version: 1
+allowDevDependencies: true
+rules:
+  - name: critical-with-fix
+    minSeverity: Critical
+    fixAvailable: true
+  - name: no-gpl
+    deniedLicenses: ["GPL-3.0*"]
+ignore:
+  - id: CVE-2022-0002
+    until: 2026-12-31
+    reason: Not exploitable
+  - license: GPL-3.0-only
+    packages: ["internal-*"]
+`
+
+func createPolicyFile(t *testing.T, content string) string {
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(policyFile, []byte(content), 0644))
+	return policyFile
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := LoadPolicy(createPolicyFile(t, testPolicy))
+	assert.NoError(t, err)
+	assert.True(t, policy.AllowDevDependencies)
+	assert.Len(t, policy.Rules, 2)
+	assert.True(t, policy.Rules[0].FixAvailable)
+	assert.Equal(t, "2026-12-31", policy.Ignore[0].Until)
+	assert.True(t, policy.HasLicenseRules())
+
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{"unknown field", "version: 1\nrulez: []", "failed to parse the policy file"},
+		{"bad version", "version: 2", "unsupported version 2"},
+		{"no rule name", "version: 1\nrules:\n  - minSeverity: High", "rule #1 has no name"},
+		{"bad severity", "version: 1\nrules:\n  - name: a\n    minSeverity: Severe", "unknown severity 'Severe'"},
+		{"mixed criteria", "version: 1\nrules:\n  - name: a\n    minSeverity: High\n    deniedLicenses: [MIT]", "mixes security and license criteria"},
+		{"no criteria", "version: 1\nrules:\n  - name: a", "rule 'a' has no criteria"},
+		{"bad date", "version: 1\nignore:\n  - id: CVE-1\n    until: 31/12/2026", "invalid date '31/12/2026'"},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadPolicy(createPolicyFile(t, test.content))
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy, err := LoadPolicy(createPolicyFile(t, testPolicy))
+	assert.NoError(t, err)
+	results := formats.SimpleJsonResults{
+		Vulnerabilities: []formats.VulnerabilityOrViolationRow{
+			{IssueId: "XRAY-1", Severity: "Critical", ImpactedPackageName: "lodash", ImpactedPackageVersion: "4.17.0",
+				FixedVersions: []string{"[4.17.21]"}, Cves: []formats.CveRow{{Id: "CVE-2022-0001"}}},
+			// Ignored until the end of 2026
+			{IssueId: "XRAY-2", Severity: "Critical", ImpactedPackageName: "minimist", ImpactedPackageVersion: "1.0.0",
+				FixedVersions: []string{"[1.2.6]"}, Cves: []formats.CveRow{{Id: "CVE-2022-0002"}}},
+			// No fix available
+			{IssueId: "XRAY-3", Severity: "Critical", ImpactedPackageName: "left-pad", ImpactedPackageVersion: "1.0.0"},
+			// Not critical
+			{IssueId: "XRAY-4", Severity: "High", ImpactedPackageName: "axios", ImpactedPackageVersion: "0.1.0", FixedVersions: []string{"[1.0.0]"}},
+		},
+		Licenses: []formats.LicenseRow{
+			{LicenseKey: "GPL-3.0-or-later", ImpactedPackageName: "readline", ImpactedPackageVersion: "8.0.0"},
+			{LicenseKey: "GPL-3.0-only", ImpactedPackageName: "internal-lib", ImpactedPackageVersion: "1.0.0"},
+			{LicenseKey: "MIT", ImpactedPackageName: "lodash", ImpactedPackageVersion: "4.17.0"},
+		},
+	}
+
+	violations := policy.Evaluate(results, nil, time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, []PolicyViolation{
+		{Rule: "critical-with-fix", Type: securityRuleType, IssueId: "XRAY-1", Cves: []string{"CVE-2022-0001"}, Severity: "Critical",
+			PackageName: "lodash", PackageVersion: "4.17.0", FixedVersions: []string{"[4.17.21]"}},
+		{Rule: "no-gpl", Type: licenseRuleType, License: "GPL-3.0-or-later", PackageName: "readline", PackageVersion: "8.0.0"},
+	}, violations)
+	assert.Equal(t, "Rule 'critical-with-fix': CVE-2022-0001 (Critical) in lodash:4.17.0, fixed versions: [4.17.21]", violations[0].String())
+	assert.Equal(t, "Rule 'no-gpl': readline:8.0.0 is licensed under the denied license GPL-3.0-or-later", violations[1].String())
+
+	// After the ignore rule expires
+	violations = policy.Evaluate(results, nil, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, violations, 3)
+	assert.Equal(t, "XRAY-2", violations[1].IssueId)
+}
+
+func TestPolicyEvaluateDevDependencies(t *testing.T) {
+	policy, err := LoadPolicy(createPolicyFile(t, testPolicy))
+	assert.NoError(t, err)
+	devImpactPath := []formats.ComponentRow{{Name: "root"}, {Name: "jest", Version: "29.0.0"}, {Name: "minimatch", Version: "3.0.4"}}
+	prodImpactPath := []formats.ComponentRow{{Name: "root"}, {Name: "express", Version: "4.0.0"}, {Name: "minimatch", Version: "3.0.4"}}
+	results := formats.SimpleJsonResults{
+		Vulnerabilities: []formats.VulnerabilityOrViolationRow{
+			// Only a dev dependency brings the vulnerable package
+			{IssueId: "XRAY-1", Severity: "Critical", ImpactedPackageName: "minimatch", ImpactedPackageVersion: "3.0.4",
+				FixedVersions: []string{"[3.0.5]"}, ImpactPaths: [][]formats.ComponentRow{devImpactPath}},
+			// Both a dev and a production dependency bring the vulnerable package
+			{IssueId: "XRAY-2", Severity: "Critical", ImpactedPackageName: "minimatch", ImpactedPackageVersion: "3.0.4",
+				FixedVersions: []string{"[3.0.5]"}, ImpactPaths: [][]formats.ComponentRow{devImpactPath, prodImpactPath}},
+		},
+		Licenses: []formats.LicenseRow{
+			{LicenseKey: "GPL-3.0-only", ImpactedPackageName: "Jest", ImpactedPackageVersion: "29.0.0",
+				ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "Jest", Version: "29.0.0"}}}},
+		},
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	violations := policy.Evaluate(results, []string{"jest"}, now)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "XRAY-2", violations[0].IssueId)
+	}
+
+	// All the issues fail the build if dev dependencies aren't allowed
+	policy.AllowDevDependencies = false
+	assert.Len(t, policy.Evaluate(results, []string{"jest"}, now), 3)
+}
+
+func TestNewPolicyFailBuildError(t *testing.T) {
+	assert.NoError(t, newPolicyFailBuildError(nil))
+	err := newPolicyFailBuildError([]PolicyViolation{{Rule: "no-gpl", Type: licenseRuleType, License: "GPL-3.0", PackageName: "readline"}})
+	cliError, ok := err.(coreutils.CliError)
+	if assert.True(t, ok) {
+		assert.Equal(t, coreutils.ExitCodeVulnerableBuild, cliError.ExitCode)
+		assert.Equal(t, policyFailedErrorMsg+"\nRule 'no-gpl': readline is licensed under the denied license GPL-3.0", cliError.ErrorMsg)
+	}
+}
+
+func TestMatchesAnyPattern(t *testing.T) {
+	assert.True(t, matchesAnyPattern("anything", nil))
+	assert.True(t, matchesAnyPattern("GPL-3.0-only", []string{"MIT", "gpl-3.0*"}))
+	assert.True(t, matchesAnyPattern("@scope/pkg", []string{"@scope/*"}))
+	assert.False(t, matchesAnyPattern("LGPL-3.0", []string{"GPL-3.0*"}))
+	assert.False(t, matchesAnyPattern("a.b", []string{"a?b"}))
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"github.com/owenrumney/go-sarif/v2/sarif"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -82,7 +84,7 @@ func GenerateSarifFileFromScan(currentScan []services.ScanResponse, includeVulne
 		return "", errorutils.CheckError(err)
 	}
 	run := sarif.NewRunWithInformationURI("JFrog Xray", "https://jfrog.com/xray/")
-	err = convertScanToSarif(run, currentScan, includeVulnerabilities, isMultipleRoots)
+	err = convertScanToSarif(run, currentScan, includeVulnerabilities, isMultipleRoots, "")
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +129,8 @@ func convertScanToSimpleJson(results []services.ScanResponse, errors []formats.S
 	return jsonTable, nil
 }
 
-func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, includeVulnerabilities, isMultipleRoots bool) error {
+// projectDir - The directory of the scanned project, relative to the root of the scan. The locations of the results are relative to this directory.
+func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, includeVulnerabilities, isMultipleRoots bool, projectDir string) error {
 	var errors []formats.SimpleJsonError
 	jsonTable, err := convertScanToSimpleJson(currentScan, errors, includeVulnerabilities, isMultipleRoots, false)
 	if err != nil {
@@ -145,7 +148,7 @@ func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, inc
 			if err != nil {
 				return err
 			}
-			err = addScanResultsToSarifRun(run, severity, violations[i].IssueId, impactedPackageFull, violations[i].Summary, violations[i].Technology)
+			err = addScanResultsToSarifRun(run, severity, violations[i].IssueId, impactedPackageFull, violations[i].Summary, violations[i].Technology, projectDir)
 			if err != nil {
 				return err
 			}
@@ -155,7 +158,7 @@ func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, inc
 			if err != nil {
 				return err
 			}
-			err = addScanResultsToSarifRun(run, "", licenses[i].ImpactedPackageVersion, impactedPackageFull, licenses[i].LicenseKey, coreutils.Technology(strings.ToLower(licenses[i].ImpactedPackageType)))
+			err = addScanResultsToSarifRun(run, "", licenses[i].ImpactedPackageVersion, impactedPackageFull, licenses[i].LicenseKey, coreutils.Technology(strings.ToLower(licenses[i].ImpactedPackageType)), projectDir)
 			if err != nil {
 				return err
 			}
@@ -174,7 +177,7 @@ func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, inc
 			if err != nil {
 				return err
 			}
-			err = addScanResultsToSarifRun(run, severity, vulnerabilities[i].IssueId, impactedPackageFull, vulnerabilities[i].Summary, vulnerabilities[i].Technology)
+			err = addScanResultsToSarifRun(run, severity, vulnerabilities[i].IssueId, impactedPackageFull, vulnerabilities[i].Summary, vulnerabilities[i].Technology, projectDir)
 			if err != nil {
 				return err
 			}
@@ -185,8 +188,11 @@ func convertScanToSarif(run *sarif.Run, currentScan []services.ScanResponse, inc
 }
 
 // Adding the Xray scan results details to the sarif struct, for each issue found in the scan
-func addScanResultsToSarifRun(run *sarif.Run, severity string, issueId string, impactedPackage string, description string, technology coreutils.Technology) error {
+func addScanResultsToSarifRun(run *sarif.Run, severity string, issueId string, impactedPackage string, description string, technology coreutils.Technology, projectDir string) error {
 	techPackageDescriptor := technology.GetPackageDescriptor()
+	if projectDir != "" {
+		techPackageDescriptor = path.Join(filepath.ToSlash(projectDir), techPackageDescriptor)
+	}
 	pb := sarif.NewPropertyBag()
 	if severity != missingCveScore {
 		pb.Add("security-severity", severity)
@@ -0,0 +1,534 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+type SbomFormat string
+
+const (
+	// SbomFormat values
+	CycloneDxJson SbomFormat = "cyclonedx-json"
+	CycloneDxXml  SbomFormat = "cyclonedx-xml"
+	SpdxJson      SbomFormat = "spdx-json"
+
+	cycloneDxSpecVersion = "1.5"
+	cycloneDxXmlns       = "http://cyclonedx.org/schema/bom/1.5"
+	spdxVersion          = "SPDX-2.3"
+	spdxNoAssertion      = "NOASSERTION"
+	sbomToolName         = "jfrog-cli"
+	xraySourceName       = "JFrog Xray"
+)
+
+var SbomFormats = []string{string(CycloneDxJson), string(CycloneDxXml), string(SpdxJson)}
+
+func GetSbomFormat(format string) (SbomFormat, error) {
+	for _, sbomFormat := range SbomFormats {
+		if format == sbomFormat {
+			return SbomFormat(format), nil
+		}
+	}
+	return "", errorutils.CheckErrorf("unsupported SBOM format '%s'. The supported formats are: %s", format, strings.Join(SbomFormats, ", "))
+}
+
+// Maps the package types of Xray component IDs to package-url types
+var purlTypes = map[string]string{
+	"gav":      "maven",
+	"npm":      "npm",
+	"go":       "golang",
+	"nuget":    "nuget",
+	"pypi":     "pypi",
+	"pip":      "pypi",
+	"composer": "composer",
+	"deb":      "deb",
+	"rpm":      "rpm",
+	"alpine":   "apk",
+	"docker":   "docker",
+	"generic":  "generic",
+}
+
+type SbomParams struct {
+	// The name of the project described by the SBOM
+	Name            string
+	DependencyTrees []*services.GraphNode
+	// The vulnerabilities found by the graph scan of the dependency trees. Optional.
+	Vulnerabilities []services.Vulnerability
+	// If empty, the current time is used
+	Timestamp time.Time
+	// A UUID identifying the SBOM. If empty, a random UUID is generated.
+	SerialNumber string
+}
+
+// GenerateSbom serialises the dependency trees to an SBOM in the given format.
+func GenerateSbom(params SbomParams, format SbomFormat) (string, error) {
+	if params.Timestamp.IsZero() {
+		params.Timestamp = time.Now()
+	}
+	if params.SerialNumber == "" {
+		params.SerialNumber = uuid.New().String()
+	}
+	graph := newSbomGraph(params.Name, params.DependencyTrees)
+	switch format {
+	case CycloneDxJson:
+		content, err := json.MarshalIndent(createCycloneDxBom(params, graph), "", "  ")
+		return string(content), errorutils.CheckError(err)
+	case CycloneDxXml:
+		content, err := xml.MarshalIndent(createCycloneDxBom(params, graph), "", "  ")
+		return xml.Header + string(content), errorutils.CheckError(err)
+	case SpdxJson:
+		content, err := json.MarshalIndent(createSpdxDocument(params, graph), "", "  ")
+		return string(content), errorutils.CheckError(err)
+	}
+	return "", errorutils.CheckErrorf("unsupported SBOM format '%s'", format)
+}
+
+type sbomComponent struct {
+	componentId string
+	// The purl if the package type is known, or the component ID otherwise
+	ref       string
+	purl      string
+	namespace string
+	name      string
+	version   string
+	licenses  []string
+}
+
+// The flattened dependency trees, with each component appearing once
+type sbomGraph struct {
+	roots      []string
+	components []*sbomComponent
+	// Maps component IDs to their components
+	componentsById map[string]*sbomComponent
+	dependsOn      map[string]map[string]bool
+}
+
+func newSbomGraph(projectName string, trees []*services.GraphNode) *sbomGraph {
+	graph := &sbomGraph{componentsById: make(map[string]*sbomComponent), dependsOn: make(map[string]map[string]bool)}
+	for _, tree := range trees {
+		graph.roots = append(graph.roots, graph.addNode(tree, projectName))
+	}
+	return graph
+}
+
+func (sg *sbomGraph) addNode(node *services.GraphNode, projectName string) string {
+	if _, exist := sg.componentsById[node.Id]; !exist {
+		component := newSbomComponent(node.Id, projectName)
+		component.licenses = node.Licenses
+		sg.componentsById[node.Id] = component
+		sg.components = append(sg.components, component)
+		sg.dependsOn[node.Id] = make(map[string]bool)
+	}
+	for _, child := range node.Nodes {
+		sg.dependsOn[node.Id][sg.addNode(child, projectName)] = true
+	}
+	return node.Id
+}
+
+// Returns the refs of the direct dependencies of the component, sorted.
+func (sg *sbomGraph) getDependencies(componentId string) (refs []string) {
+	for dependencyId := range sg.dependsOn[componentId] {
+		refs = append(refs, sg.componentsById[dependencyId].ref)
+	}
+	sort.Strings(refs)
+	return
+}
+
+func (sg *sbomGraph) isRoot(componentId string) bool {
+	for _, root := range sg.roots {
+		if root == componentId {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbackName - The component name, if the component ID doesn't include one. Used for the roots of some dependency trees.
+func newSbomComponent(componentId, fallbackName string) *sbomComponent {
+	component := &sbomComponent{componentId: componentId, ref: componentId}
+	component.name, component.version, _ = splitComponentId(componentId)
+	if component.name == "" {
+		component.name = fallbackName
+		return component
+	}
+	purlType, exist := purlTypes[strings.SplitN(componentId, "://", 2)[0]]
+	if !exist {
+		return component
+	}
+	component.namespace, component.name = splitPurlNamespace(purlType, component.name)
+	component.purl = createPurl(purlType, component.namespace, component.name, component.version)
+	component.ref = component.purl
+	return component
+}
+
+// Splits the component name to the purl namespace and name.
+// For example, Maven groups, npm scopes and Go module paths are purl namespaces.
+func splitPurlNamespace(purlType, componentName string) (namespace, name string) {
+	separator := ""
+	switch purlType {
+	case "maven":
+		separator = ":"
+	case "npm", "golang":
+		separator = "/"
+	case "pypi":
+		return "", strings.ReplaceAll(strings.ToLower(componentName), "_", "-")
+	}
+	if separator == "" {
+		return "", componentName
+	}
+	if lastIndex := strings.LastIndex(componentName, separator); lastIndex > 0 {
+		return componentName[:lastIndex], componentName[lastIndex+1:]
+	}
+	return "", componentName
+}
+
+// Creates a package-url: pkg:type/namespace/name@version
+func createPurl(purlType, namespace, name, version string) string {
+	purl := "pkg:" + purlType + "/"
+	if namespace != "" {
+		var segments []string
+		for _, segment := range strings.Split(namespace, "/") {
+			segments = append(segments, escapePurlSegment(segment))
+		}
+		purl += strings.Join(segments, "/") + "/"
+	}
+	purl += escapePurlSegment(name)
+	if version != "" {
+		purl += "@" + escapePurlSegment(version)
+	}
+	return purl
+}
+
+func escapePurlSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
+
+// Maps the component IDs to the vulnerabilities affecting them
+func getVulnerabilitiesByComponent(vulnerabilities []services.Vulnerability) map[string][]services.Vulnerability {
+	vulnerabilitiesByComponent := make(map[string][]services.Vulnerability)
+	for _, vulnerability := range vulnerabilities {
+		for componentId := range vulnerability.Components {
+			vulnerabilitiesByComponent[componentId] = append(vulnerabilitiesByComponent[componentId], vulnerability)
+		}
+	}
+	return vulnerabilitiesByComponent
+}
+
+type cycloneDxBom struct {
+	XMLName         xml.Name                 `json:"-" xml:"bom"`
+	Xmlns           string                   `json:"-" xml:"xmlns,attr"`
+	BomFormat       string                   `json:"bomFormat" xml:"-"`
+	SpecVersion     string                   `json:"specVersion" xml:"-"`
+	SerialNumber    string                   `json:"serialNumber" xml:"serialNumber,attr"`
+	Version         int                      `json:"version" xml:"version,attr"`
+	Metadata        cycloneDxMetadata        `json:"metadata" xml:"metadata"`
+	Components      []cycloneDxComponent     `json:"components" xml:"components>component"`
+	Dependencies    []cycloneDxDependency    `json:"dependencies" xml:"dependencies>dependency"`
+	Vulnerabilities []cycloneDxVulnerability `json:"vulnerabilities,omitempty" xml:"vulnerabilities>vulnerability,omitempty"`
+}
+
+type cycloneDxMetadata struct {
+	Timestamp string              `json:"timestamp" xml:"timestamp"`
+	Tools     cycloneDxTools      `json:"tools" xml:"tools"`
+	Component *cycloneDxComponent `json:"component,omitempty" xml:"component,omitempty"`
+}
+
+type cycloneDxTools struct {
+	Components []cycloneDxComponent `json:"components" xml:"components>component"`
+}
+
+type cycloneDxComponent struct {
+	Type     string            `json:"type" xml:"type,attr"`
+	BomRef   string            `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Group    string            `json:"group,omitempty" xml:"group,omitempty"`
+	Name     string            `json:"name" xml:"name"`
+	Version  string            `json:"version,omitempty" xml:"version,omitempty"`
+	Licenses cycloneDxLicenses `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	Purl     string            `json:"purl,omitempty" xml:"purl,omitempty"`
+}
+
+type cycloneDxLicenses []cycloneDxLicenseChoice
+
+// In XML, the license elements are not wrapped by license choices
+func (licenses cycloneDxLicenses) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	xmlLicenses := struct {
+		Licenses []cycloneDxLicense `xml:"license"`
+	}{}
+	for _, licenseChoice := range licenses {
+		xmlLicenses.Licenses = append(xmlLicenses.Licenses, licenseChoice.License)
+	}
+	return encoder.EncodeElement(xmlLicenses, start)
+}
+
+type cycloneDxLicenseChoice struct {
+	License cycloneDxLicense `json:"license"`
+}
+
+type cycloneDxLicense struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type cycloneDxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// In XML, the dependencies are nested dependency elements, referencing the components in their ref attribute
+func (dependency cycloneDxDependency) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type dependencyRef struct {
+		Ref string `xml:"ref,attr"`
+	}
+	xmlDependency := struct {
+		Ref       string          `xml:"ref,attr"`
+		DependsOn []dependencyRef `xml:"dependency"`
+	}{Ref: dependency.Ref}
+	for _, ref := range dependency.DependsOn {
+		xmlDependency.DependsOn = append(xmlDependency.DependsOn, dependencyRef{Ref: ref})
+	}
+	return encoder.EncodeElement(xmlDependency, start)
+}
+
+type cycloneDxVulnerability struct {
+	BomRef      string                 `json:"bom-ref" xml:"bom-ref,attr"`
+	Id          string                 `json:"id" xml:"id"`
+	Source      cycloneDxSource        `json:"source" xml:"source"`
+	References  []cycloneDxReference   `json:"references,omitempty" xml:"references>reference,omitempty"`
+	Ratings     []cycloneDxRating      `json:"ratings" xml:"ratings>rating"`
+	Description string                 `json:"description,omitempty" xml:"description,omitempty"`
+	Affects     []cycloneDxAffectedRef `json:"affects" xml:"affects>target"`
+}
+
+type cycloneDxSource struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type cycloneDxReference struct {
+	Id     string          `json:"id" xml:"id"`
+	Source cycloneDxSource `json:"source" xml:"source"`
+}
+
+type cycloneDxRating struct {
+	Score    *float64 `json:"score,omitempty" xml:"score,omitempty"`
+	Severity string   `json:"severity" xml:"severity"`
+	Method   string   `json:"method,omitempty" xml:"method,omitempty"`
+}
+
+type cycloneDxAffectedRef struct {
+	Ref string `json:"ref" xml:"ref"`
+}
+
+func createCycloneDxBom(params SbomParams, graph *sbomGraph) *cycloneDxBom {
+	bom := &cycloneDxBom{
+		Xmlns:        cycloneDxXmlns,
+		BomFormat:    "CycloneDX",
+		SpecVersion:  cycloneDxSpecVersion,
+		SerialNumber: "urn:uuid:" + params.SerialNumber,
+		Version:      1,
+		Metadata: cycloneDxMetadata{
+			Timestamp: params.Timestamp.UTC().Format(time.RFC3339),
+			Tools:     cycloneDxTools{Components: []cycloneDxComponent{{Type: "application", Name: sbomToolName, Version: coreutils.GetCliUserAgentVersion()}}},
+		},
+		Components:   []cycloneDxComponent{},
+		Dependencies: []cycloneDxDependency{},
+	}
+	if len(graph.roots) == 1 {
+		// The single root is the described project
+		rootComponent := toCycloneDxComponent(graph.componentsById[graph.roots[0]], "application")
+		bom.Metadata.Component = &rootComponent
+	} else {
+		// The roots are modules of the described project
+		bom.Metadata.Component = &cycloneDxComponent{Type: "application", BomRef: params.Name, Name: params.Name}
+		dependency := cycloneDxDependency{Ref: params.Name, DependsOn: []string{}}
+		for _, root := range graph.roots {
+			dependency.DependsOn = append(dependency.DependsOn, graph.componentsById[root].ref)
+		}
+		bom.Dependencies = append(bom.Dependencies, dependency)
+	}
+	for _, component := range graph.components {
+		if !(len(graph.roots) == 1 && graph.isRoot(component.componentId)) {
+			bom.Components = append(bom.Components, toCycloneDxComponent(component, "library"))
+		}
+		bom.Dependencies = append(bom.Dependencies, cycloneDxDependency{Ref: component.ref, DependsOn: append([]string{}, graph.getDependencies(component.componentId)...)})
+	}
+	// The same issue may be reported by several scan results, so each of its affected components is added only once
+	addedVulnerabilities := make(map[string]bool)
+	for _, vulnerability := range params.Vulnerabilities {
+		for _, cdxVulnerability := range toCycloneDxVulnerabilities(vulnerability, graph) {
+			if !addedVulnerabilities[cdxVulnerability.BomRef] {
+				addedVulnerabilities[cdxVulnerability.BomRef] = true
+				bom.Vulnerabilities = append(bom.Vulnerabilities, cdxVulnerability)
+			}
+		}
+	}
+	return bom
+}
+
+func toCycloneDxComponent(component *sbomComponent, componentType string) cycloneDxComponent {
+	cdxComponent := cycloneDxComponent{
+		Type:    componentType,
+		BomRef:  component.ref,
+		Group:   component.namespace,
+		Name:    component.name,
+		Version: component.version,
+		Purl:    component.purl,
+	}
+	for _, license := range component.licenses {
+		cdxComponent.Licenses = append(cdxComponent.Licenses, cycloneDxLicenseChoice{License: cycloneDxLicense{Name: license}})
+	}
+	return cdxComponent
+}
+
+// Returns a vulnerability for each of the affected components, which are in the SBOM.
+// The bom-ref of each vulnerability is made of the issue ID and the ref of the affected component, since bom-refs must be unique in the document.
+func toCycloneDxVulnerabilities(vulnerability services.Vulnerability, graph *sbomGraph) (cdxVulnerabilities []cycloneDxVulnerability) {
+	var affectedRefs []string
+	for componentId := range vulnerability.Components {
+		if component, exist := graph.componentsById[componentId]; exist {
+			affectedRefs = append(affectedRefs, component.ref)
+		}
+	}
+	sort.Strings(affectedRefs)
+	for _, affectedRef := range affectedRefs {
+		cdxVulnerabilities = append(cdxVulnerabilities, toCycloneDxVulnerability(vulnerability, affectedRef))
+	}
+	return
+}
+
+func toCycloneDxVulnerability(vulnerability services.Vulnerability, affectedRef string) cycloneDxVulnerability {
+	cdxVulnerability := cycloneDxVulnerability{
+		BomRef:      vulnerability.IssueId + "/" + affectedRef,
+		Id:          vulnerability.IssueId,
+		Source:      cycloneDxSource{Name: xraySourceName},
+		Description: vulnerability.Summary,
+		Affects:     []cycloneDxAffectedRef{{Ref: affectedRef}},
+	}
+	rating := cycloneDxRating{Severity: strings.ToLower(vulnerability.Severity)}
+	if rating.Severity == "" {
+		rating.Severity = "unknown"
+	}
+	if len(vulnerability.Cves) > 0 && vulnerability.Cves[0].Id != "" {
+		// The CVE identifies the vulnerability, and the Xray issue is a reference
+		cdxVulnerability.Id = vulnerability.Cves[0].Id
+		cdxVulnerability.Source = cycloneDxSource{Name: "NVD"}
+		cdxVulnerability.References = []cycloneDxReference{{Id: vulnerability.IssueId, Source: cycloneDxSource{Name: xraySourceName}}}
+		if score, err := strconv.ParseFloat(vulnerability.Cves[0].CvssV3Score, 64); err == nil {
+			rating.Score, rating.Method = &score, "CVSSv3"
+		}
+	}
+	cdxVulnerability.Ratings = []cycloneDxRating{rating}
+	return cdxVulnerability
+}
+
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SpdxId            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SpdxId           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+func createSpdxDocument(params SbomParams, graph *sbomGraph) *spdxDocument {
+	document := &spdxDocument{
+		SpdxVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SpdxId:            "SPDXRef-DOCUMENT",
+		Name:              params.Name,
+		DocumentNamespace: fmt.Sprintf("https://jfrog.com/spdxdocs/%s-%s", url.PathEscape(params.Name), params.SerialNumber),
+		CreationInfo: spdxCreationInfo{
+			Created:  params.Timestamp.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + sbomToolName + "-" + coreutils.GetCliUserAgentVersion()},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+	vulnerabilitiesByComponent := getVulnerabilitiesByComponent(params.Vulnerabilities)
+	// SPDX IDs may only contain letters, numbers, '.' and '-', so the packages are numbered
+	spdxIds := make(map[string]string)
+	for i, component := range graph.components {
+		spdxIds[component.componentId] = fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		document.Packages = append(document.Packages, toSpdxPackage(component, spdxIds[component.componentId], vulnerabilitiesByComponent[component.componentId]))
+	}
+	for _, root := range graph.roots {
+		document.Relationships = append(document.Relationships, spdxRelationship{SpdxElementId: document.SpdxId, RelationshipType: "DESCRIBES", RelatedSpdxElement: spdxIds[root]})
+	}
+	for _, component := range graph.components {
+		var dependencies []string
+		for dependencyId := range graph.dependsOn[component.componentId] {
+			dependencies = append(dependencies, spdxIds[dependencyId])
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			document.Relationships = append(document.Relationships, spdxRelationship{SpdxElementId: spdxIds[component.componentId], RelationshipType: "DEPENDS_ON", RelatedSpdxElement: dependency})
+		}
+	}
+	return document
+}
+
+func toSpdxPackage(component *sbomComponent, spdxId string, vulnerabilities []services.Vulnerability) spdxPackage {
+	name := component.name
+	if component.namespace != "" {
+		name = component.namespace + "/" + name
+	}
+	spdxPkg := spdxPackage{
+		Name:             name,
+		SpdxId:           spdxId,
+		VersionInfo:      component.version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+	}
+	if component.purl != "" {
+		spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.purl})
+	}
+	// SPDX doesn't describe vulnerabilities, so the CVEs are attached as security advisories
+	for _, vulnerability := range vulnerabilities {
+		for _, cve := range vulnerability.Cves {
+			if cve.Id != "" {
+				spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "SECURITY", ReferenceType: "advisory", ReferenceLocator: "https://nvd.nist.gov/vuln/detail/" + cve.Id})
+			}
+		}
+	}
+	return spdxPkg
+}
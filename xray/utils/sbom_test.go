@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSampleSbomParams() SbomParams {
+	sharedDependency := &services.GraphNode{Id: "npm://@types/node:18.0.0"}
+	return SbomParams{
+		Name: "my-project",
+		DependencyTrees: []*services.GraphNode{{
+			Id: "npm://my-project:1.0.0",
+			Nodes: []*services.GraphNode{
+				{Id: "npm://lodash:4.17.0", Licenses: []string{"MIT"}, Nodes: []*services.GraphNode{sharedDependency}},
+				sharedDependency,
+			},
+		}},
+		Vulnerabilities: []services.Vulnerability{{
+			IssueId:    "XRAY-1",
+			Summary:    "Prototype pollution",
+			Severity:   "High",
+			Cves:       []services.Cve{{Id: "CVE-2020-8203", CvssV3Score: "7.4"}},
+			Components: map[string]services.Component{"npm://lodash:4.17.0": {}, "npm://not-in-sbom:1.0.0": {}},
+		}},
+		Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		SerialNumber: "3e671687-395b-41f5-a30f-a58921a69b79",
+	}
+}
+
+func TestComponentIdToPurl(t *testing.T) {
+	testCases := []struct {
+		componentId  string
+		expectedPurl string
+	}{
+		{"gav://org.apache.logging.log4j:log4j-core:2.14.1", "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		{"npm://@types/node:18.0.0", "pkg:npm/%40types/node@18.0.0"},
+		{"npm://lodash:4.17.0", "pkg:npm/lodash@4.17.0"},
+		{"go://github.com/jfrog/gofrog:v1.2.5", "pkg:golang/github.com/jfrog/gofrog@v1.2.5"},
+		{"go://github.com/jfrog/my-module", "pkg:golang/github.com/jfrog/my-module"},
+		{"pypi://Flask_Cors:3.0.10", "pkg:pypi/flask-cors@3.0.10"},
+		{"nuget://Newtonsoft.Json:13.0.1", "pkg:nuget/Newtonsoft.Json@13.0.1"},
+		{"unknown://a:1", ""},
+		{"invalid", ""},
+	}
+	for _, test := range testCases {
+		t.Run(test.componentId, func(t *testing.T) {
+			assert.Equal(t, test.expectedPurl, newSbomComponent(test.componentId, "project").purl)
+		})
+	}
+	// Components without names, such as the roots of Python projects, are named after the project
+	assert.Equal(t, "project", newSbomComponent("pypi://", "project").name)
+}
+
+func TestGenerateCycloneDxJson(t *testing.T) {
+	content, err := GenerateSbom(getSampleSbomParams(), CycloneDxJson)
+	assert.NoError(t, err)
+	var bom cycloneDxBom
+	assert.NoError(t, json.Unmarshal([]byte(content), &bom))
+
+	assert.Equal(t, "CycloneDX", bom.BomFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom.SerialNumber)
+	assert.Equal(t, "2026-01-02T03:04:05Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "pkg:npm/my-project@1.0.0", bom.Metadata.Component.Purl)
+	assert.Equal(t, []cycloneDxComponent{
+		{Type: "library", BomRef: "pkg:npm/lodash@4.17.0", Name: "lodash", Version: "4.17.0", Purl: "pkg:npm/lodash@4.17.0",
+			Licenses: cycloneDxLicenses{{License: cycloneDxLicense{Name: "MIT"}}}},
+		{Type: "library", BomRef: "pkg:npm/%40types/node@18.0.0", Group: "@types", Name: "node", Version: "18.0.0", Purl: "pkg:npm/%40types/node@18.0.0"},
+	}, bom.Components)
+	assert.Equal(t, []cycloneDxDependency{
+		{Ref: "pkg:npm/my-project@1.0.0", DependsOn: []string{"pkg:npm/%40types/node@18.0.0", "pkg:npm/lodash@4.17.0"}},
+		{Ref: "pkg:npm/lodash@4.17.0", DependsOn: []string{"pkg:npm/%40types/node@18.0.0"}},
+		{Ref: "pkg:npm/%40types/node@18.0.0", DependsOn: []string{}},
+	}, bom.Dependencies)
+	if assert.Len(t, bom.Vulnerabilities, 1) {
+		vulnerability := bom.Vulnerabilities[0]
+		assert.Equal(t, "CVE-2020-8203", vulnerability.Id)
+		assert.Equal(t, "XRAY-1", vulnerability.References[0].Id)
+		assert.Equal(t, "high", vulnerability.Ratings[0].Severity)
+		assert.Equal(t, 7.4, *vulnerability.Ratings[0].Score)
+		assert.Equal(t, []cycloneDxAffectedRef{{Ref: "pkg:npm/lodash@4.17.0"}}, vulnerability.Affects)
+		assert.Equal(t, "XRAY-1/pkg:npm/lodash@4.17.0", vulnerability.BomRef)
+	}
+}
+
+func TestGenerateCycloneDxUniqueVulnerabilityRefs(t *testing.T) {
+	params := getSampleSbomParams()
+	// The issue affects several components, and is reported by several scan results
+	params.Vulnerabilities[0].Components["npm://@types/node:18.0.0"] = services.Component{}
+	params.Vulnerabilities = append(params.Vulnerabilities, params.Vulnerabilities[0])
+	content, err := GenerateSbom(params, CycloneDxJson)
+	assert.NoError(t, err)
+	var bom cycloneDxBom
+	assert.NoError(t, json.Unmarshal([]byte(content), &bom))
+
+	var bomRefs []string
+	for _, vulnerability := range bom.Vulnerabilities {
+		bomRefs = append(bomRefs, vulnerability.BomRef)
+		assert.Equal(t, "CVE-2020-8203", vulnerability.Id)
+	}
+	assert.Equal(t, []string{"XRAY-1/pkg:npm/%40types/node@18.0.0", "XRAY-1/pkg:npm/lodash@4.17.0"}, bomRefs)
+}
+
+func TestGenerateCycloneDxXml(t *testing.T) {
+	params := getSampleSbomParams()
+	// Multiple roots are described as modules of the project
+	params.DependencyTrees = append(params.DependencyTrees, &services.GraphNode{Id: "npm://other-module:1.0.0"})
+	content, err := GenerateSbom(params, CycloneDxXml)
+	assert.NoError(t, err)
+	assert.NoError(t, xml.Unmarshal([]byte(content), new(interface{})))
+
+	assert.Contains(t, content, `<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" version="1">`)
+	assert.Contains(t, content, `<component type="application" bom-ref="my-project">`)
+	assert.Contains(t, content, `<component type="library" bom-ref="pkg:npm/other-module@1.0.0">`)
+	assert.Contains(t, content, `<licenses>
+        <license>
+          <name>MIT</name>
+        </license>
+      </licenses>`)
+	assert.NotContains(t, content, `<licenses></licenses>`)
+	assert.Contains(t, content, `<dependency ref="my-project">
+      <dependency ref="pkg:npm/my-project@1.0.0"></dependency>
+      <dependency ref="pkg:npm/other-module@1.0.0"></dependency>
+    </dependency>`)
+	assert.Contains(t, content, `<affects>
+        <target>
+          <ref>pkg:npm/lodash@4.17.0</ref>
+        </target>
+      </affects>`)
+}
+
+func TestGenerateSpdxJson(t *testing.T) {
+	content, err := GenerateSbom(getSampleSbomParams(), SpdxJson)
+	assert.NoError(t, err)
+	var document spdxDocument
+	assert.NoError(t, json.Unmarshal([]byte(content), &document))
+
+	assert.Equal(t, "SPDX-2.3", document.SpdxVersion)
+	assert.Equal(t, "https://jfrog.com/spdxdocs/my-project-3e671687-395b-41f5-a30f-a58921a69b79", document.DocumentNamespace)
+	if assert.Len(t, document.Packages, 3) {
+		assert.Equal(t, "lodash", document.Packages[1].Name)
+		assert.Equal(t, "SPDXRef-Package-2", document.Packages[1].SpdxId)
+		assert.Equal(t, []spdxExternalRef{
+			{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:npm/lodash@4.17.0"},
+			{ReferenceCategory: "SECURITY", ReferenceType: "advisory", ReferenceLocator: "https://nvd.nist.gov/vuln/detail/CVE-2020-8203"},
+		}, document.Packages[1].ExternalRefs)
+		assert.Equal(t, "@types/node", document.Packages[2].Name)
+	}
+	assert.Equal(t, []spdxRelationship{
+		{SpdxElementId: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSpdxElement: "SPDXRef-Package-1"},
+		{SpdxElementId: "SPDXRef-Package-1", RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-Package-2"},
+		{SpdxElementId: "SPDXRef-Package-1", RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-Package-3"},
+		{SpdxElementId: "SPDXRef-Package-2", RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-Package-3"},
+	}, document.Relationships)
+}
+
+func TestGetSbomFormat(t *testing.T) {
+	format, err := GetSbomFormat("spdx-json")
+	assert.NoError(t, err)
+	assert.Equal(t, SpdxJson, format)
+	_, err = GetSbomFormat("spdx-xml")
+	assert.ErrorContains(t, err, "unsupported SBOM format 'spdx-xml'")
+}